@@ -0,0 +1,51 @@
+package audit
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"time"
+)
+
+// hashedFields is the canonical form of an entry that goes into its hash
+type hashedFields struct {
+	PrevHash  string `json:"prev_hash"`
+	CreatedAt string `json:"created_at"`
+	Actor     string `json:"actor"`
+	Source    string `json:"source"`
+	Action    string `json:"action"`
+	ProjectID string `json:"project_id"`
+	SecretID  string `json:"secret_id"`
+	SecretKey string `json:"secret_key"`
+}
+
+// entryHash chains an entry to the hash of the one before it, so changing
+// or removing any row breaks every hash after it
+func entryHash(prevHash string, createdAt time.Time, actor, source, action string, projectID, secretID, secretKey *string) string {
+	data, _ := json.Marshal(hashedFields{
+		PrevHash:  prevHash,
+		CreatedAt: createdAt.UTC().Format(time.RFC3339Nano),
+		Actor:     actor,
+		Source:    source,
+		Action:    action,
+		ProjectID: deref(projectID),
+		SecretID:  deref(secretID),
+		SecretKey: deref(secretKey),
+	})
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+func deref(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
+
+func optional(s string) *string {
+	if s == "" {
+		return nil
+	}
+	return &s
+}
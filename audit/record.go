@@ -0,0 +1,101 @@
+package audit
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/Knightshrestha/Secret-Injector/database/generated"
+)
+
+// Record appends entries in one transaction, chained to the last stored
+// entry
+func (l *Logger) Record(ctx context.Context, entries ...Entry) error {
+	if len(entries) == 0 {
+		return nil
+	}
+
+	txn, err := l.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer txn.Rollback()
+
+	if err := RecordTx(ctx, l.queries.WithTx(txn), entries...); err != nil {
+		return err
+	}
+	return txn.Commit()
+}
+
+// RecordTx appends entries inside the caller's transaction, so they are
+// committed with the change they describe or not at all
+func RecordTx(ctx context.Context, queriesTx *generated.Queries, entries ...Entry) error {
+	prevHash := ""
+	last, err := queriesTx.GetLastAuditEntry(ctx)
+	if err == nil {
+		prevHash = last.Hash
+	} else if err != sql.ErrNoRows {
+		return fmt.Errorf("failed to fetch last audit entry: %w", err)
+	}
+
+	now := time.Now().UTC()
+	for _, entry := range entries {
+		params := generated.CreateAuditEntryParams{
+			CreatedAt: now,
+			Actor:     entry.Actor,
+			Source:    string(entry.Source),
+			Action:    string(entry.Action),
+			ProjectID: optional(entry.ProjectID),
+			SecretID:  optional(entry.SecretID),
+			SecretKey: optional(entry.Key),
+			PrevHash:  prevHash,
+		}
+		params.Hash = entryHash(params.PrevHash, params.CreatedAt, params.Actor, params.Source,
+			params.Action, params.ProjectID, params.SecretID, params.SecretKey)
+
+		if _, err := queriesTx.CreateAuditEntry(ctx, params); err != nil {
+			return fmt.Errorf("failed to write audit entry: %w", err)
+		}
+		prevHash = params.Hash
+	}
+	return nil
+}
+
+// SecretEntries builds one entry per secret
+func SecretEntries(actor string, source Source, action Action, secrets []generated.SecretList) []Entry {
+	entries := make([]Entry, 0, len(secrets))
+	for _, secret := range secrets {
+		entries = append(entries, Entry{
+			Actor:     actor,
+			Source:    source,
+			Action:    action,
+			ProjectID: secret.ProjectID,
+			SecretID:  secret.ID,
+			Key:       secret.Key,
+		})
+	}
+	return entries
+}
+
+// SecretEntry is the entry for a change to secret made by origin
+func (o Origin) SecretEntry(action Action, secret generated.SecretList) Entry {
+	return Entry{
+		Actor:     o.Actor,
+		Source:    o.Source,
+		Action:    action,
+		ProjectID: secret.ProjectID,
+		SecretID:  secret.ID,
+		Key:       secret.Key,
+	}
+}
+
+// ProjectEntry is the entry for a change to a project made by origin
+func (o Origin) ProjectEntry(action Action, projectID string) Entry {
+	return Entry{
+		Actor:     o.Actor,
+		Source:    o.Source,
+		Action:    action,
+		ProjectID: projectID,
+	}
+}
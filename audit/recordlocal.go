@@ -0,0 +1,30 @@
+package audit
+
+import (
+	"context"
+
+	"github.com/Knightshrestha/Secret-Injector/auth"
+	"github.com/Knightshrestha/Secret-Injector/database"
+	"github.com/Knightshrestha/Secret-Injector/database/generated"
+)
+
+// LocalOrigin is the origin of changes made by CLI commands, as the OS user
+func LocalOrigin() Origin {
+	return Origin{Actor: auth.LocalActor(), Source: SourceCLI}
+}
+
+// RecordLocal records secrets read by a CLI command, as the OS user
+func RecordLocal(ctx context.Context, action Action, secrets []generated.SecretList) error {
+	if len(secrets) == 0 {
+		return nil
+	}
+
+	// The command migrated the database when it opened it
+	mainDb, err := database.OpenWriteDatabase()
+	if err != nil {
+		return err
+	}
+	defer database.CloseWriteDatabase(mainDb.DB)
+
+	return NewLogger(mainDb.DB, mainDb.Queries).Record(ctx, SecretEntries(auth.LocalActor(), SourceCLI, action, secrets)...)
+}
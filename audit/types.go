@@ -0,0 +1,57 @@
+package audit
+
+import (
+	"database/sql"
+
+	"github.com/Knightshrestha/Secret-Injector/database/generated"
+)
+
+type Action string
+
+const (
+	ActionRead     Action = "read"
+	ActionHistory  Action = "history"
+	ActionCreate   Action = "create"
+	ActionUpdate   Action = "update"
+	ActionDelete   Action = "delete"
+	ActionRollback Action = "rollback"
+	ActionRestore  Action = "restore"
+	ActionExport   Action = "export"
+	ActionInject   Action = "inject"
+	ActionRedact   Action = "redact"
+	ActionScan     Action = "scan"
+)
+
+type Source string
+
+const (
+	SourceAPI Source = "api"
+	SourceCLI Source = "cli"
+)
+
+// Entry is one audited event. Secret values are never part of it.
+type Entry struct {
+	Actor     string
+	Source    Source
+	Action    Action
+	ProjectID string
+	SecretID  string
+	Key       string
+}
+
+// Origin is who makes a change and through what. Writes take it to record
+// their entries in their own transaction.
+type Origin struct {
+	Actor  string
+	Source Source
+}
+
+// Logger appends entries to the hash-chained audit_log table
+type Logger struct {
+	db      *sql.DB
+	queries *generated.Queries
+}
+
+func NewLogger(db *sql.DB, queries *generated.Queries) *Logger {
+	return &Logger{db: db, queries: queries}
+}
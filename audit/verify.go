@@ -0,0 +1,33 @@
+package audit
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/Knightshrestha/Secret-Injector/database/generated"
+)
+
+// Verify walks the whole chain and returns the number of entries checked.
+// The error names the first entry whose hash does not match.
+func Verify(ctx context.Context, queries *generated.Queries) (int, error) {
+	entries, err := queries.GetAllAuditEntries(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("failed to fetch audit entries: %w", err)
+	}
+
+	prevHash := ""
+	for i, entry := range entries {
+		if entry.PrevHash != prevHash {
+			return i, fmt.Errorf("audit entry %d does not follow the previous entry, rows were removed or reordered", entry.ID)
+		}
+
+		expected := entryHash(entry.PrevHash, entry.CreatedAt, entry.Actor, entry.Source,
+			entry.Action, entry.ProjectID, entry.SecretID, entry.SecretKey)
+		if entry.Hash != expected {
+			return i, fmt.Errorf("audit entry %d has been modified", entry.ID)
+		}
+		prevHash = entry.Hash
+	}
+
+	return len(entries), nil
+}
@@ -0,0 +1,28 @@
+package auth
+
+import (
+	"os"
+	"os/user"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// ActorFromCtx names the token behind an API request for history and
+// audit records
+func ActorFromCtx(c *fiber.Ctx) string {
+	return "token:" + GrantFromCtx(c).Token.Name
+}
+
+// LocalActor names the OS user running a CLI command
+func LocalActor() string {
+	if u, err := user.Current(); err == nil && u.Username != "" {
+		return "local:" + u.Username
+	}
+	if name := os.Getenv("USER"); name != "" {
+		return "local:" + name
+	}
+	if name := os.Getenv("USERNAME"); name != "" {
+		return "local:" + name
+	}
+	return "local"
+}
@@ -0,0 +1,68 @@
+package auth
+
+import (
+	"time"
+
+	"github.com/Knightshrestha/Secret-Injector/database/generated"
+	"github.com/gofiber/fiber/v2"
+)
+
+const grantLocal = "auth_grant"
+
+// Grant is what an authenticated token is allowed to do
+type Grant struct {
+	Token    generated.ApiToken
+	projects map[string]bool
+}
+
+func NewGrant(token generated.ApiToken, projectIDs []string) Grant {
+	projects := make(map[string]bool, len(projectIDs))
+	for _, id := range projectIDs {
+		projects[id] = true
+	}
+	return Grant{Token: token, projects: projects}
+}
+
+func (g Grant) Scope() Scope {
+	return Scope(g.Token.Scope)
+}
+
+// AllProjects reports whether the token is not limited to a project list
+func (g Grant) AllProjects() bool {
+	return !g.Token.Restricted
+}
+
+func (g Grant) IsAdmin() bool {
+	return g.Scope().allows(ScopeAdmin)
+}
+
+// CanRead reports whether the token may read the project and its secrets
+func (g Grant) CanRead(projectID string) bool {
+	return g.Scope().allows(ScopeRead) && (g.AllProjects() || g.projects[projectID])
+}
+
+// CanWrite reports whether the token may change the project and its secrets
+func (g Grant) CanWrite(projectID string) bool {
+	return g.Scope().allows(ScopeWrite) && (g.AllProjects() || g.projects[projectID])
+}
+
+// CanCreateProjects is limited to write tokens without a project list
+func (g Grant) CanCreateProjects() bool {
+	return g.Scope().allows(ScopeWrite) && g.AllProjects()
+}
+
+func (g Grant) Expired(now time.Time) bool {
+	return g.Token.ExpiresAt != nil && now.After(*g.Token.ExpiresAt)
+}
+
+// SetGrant stores the grant for the rest of the request
+func SetGrant(c *fiber.Ctx, grant Grant) {
+	c.Locals(grantLocal, grant)
+}
+
+// GrantFromCtx returns the grant of the authenticated request. Requests
+// that were not authenticated get a grant that allows nothing.
+func GrantFromCtx(c *fiber.Ctx) Grant {
+	grant, _ := c.Locals(grantLocal).(Grant)
+	return grant
+}
@@ -0,0 +1,34 @@
+package auth
+
+import (
+	"fmt"
+	"strings"
+)
+
+type Scope string
+
+const (
+	ScopeRead  Scope = "read"  // Read projects and secrets
+	ScopeWrite Scope = "write" // Also create, update and delete them
+	ScopeAdmin Scope = "admin" // Everything, never limited to projects
+)
+
+// ParseScope validates a --scope value
+func ParseScope(s string) (Scope, error) {
+	switch Scope(strings.ToLower(s)) {
+	case ScopeRead:
+		return ScopeRead, nil
+	case ScopeWrite:
+		return ScopeWrite, nil
+	case ScopeAdmin:
+		return ScopeAdmin, nil
+	default:
+		return "", fmt.Errorf("unknown scope %q (expected read, write or admin)", s)
+	}
+}
+
+// allows reports whether a token with scope s may act with scope required
+func (s Scope) allows(required Scope) bool {
+	rank := map[Scope]int{ScopeRead: 1, ScopeWrite: 2, ScopeAdmin: 3}
+	return rank[s] >= rank[required]
+}
@@ -0,0 +1,99 @@
+package auth
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/Knightshrestha/Secret-Injector/database/generated"
+	"github.com/google/uuid"
+)
+
+// ErrDuplicateToken is returned by CreateToken for a name already in use
+var ErrDuplicateToken = errors.New("a token with this name already exists")
+
+// GenerateToken returns a new random bearer token
+func GenerateToken() (string, error) {
+	buf := make([]byte, tokenBytes)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return TokenPrefix + base64.RawURLEncoding.EncodeToString(buf), nil
+}
+
+// HashToken returns the value stored in api_tokens.token_hash. Tokens are
+// long random strings, so a plain SHA-256 is enough and allows lookups.
+func HashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+// TokenParams describes a token to create
+type TokenParams struct {
+	Name       string
+	Scope      Scope
+	ProjectIDs []string   // Empty means every project
+	ExpiresAt  *time.Time // Nil never expires
+}
+
+// CreateToken stores a new token and returns it in plaintext, it cannot be
+// recovered afterwards
+func CreateToken(ctx context.Context, db *sql.DB, queries *generated.Queries, params TokenParams) (string, generated.ApiToken, error) {
+	name := strings.TrimSpace(params.Name)
+	if name == "" {
+		return "", generated.ApiToken{}, fmt.Errorf("token name is required")
+	}
+
+	if params.Scope == ScopeAdmin && len(params.ProjectIDs) > 0 {
+		return "", generated.ApiToken{}, fmt.Errorf("admin tokens cannot be limited to projects")
+	}
+
+	token, err := GenerateToken()
+	if err != nil {
+		return "", generated.ApiToken{}, fmt.Errorf("failed to generate token: %w", err)
+	}
+
+	txn, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return "", generated.ApiToken{}, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer txn.Rollback()
+	queriesTx := queries.WithTx(txn)
+
+	row, err := queriesTx.CreateApiToken(ctx, generated.CreateApiTokenParams{
+		ID:         uuid.New().String(),
+		Name:       name,
+		TokenHash:  HashToken(token),
+		Scope:      string(params.Scope),
+		Restricted: len(params.ProjectIDs) > 0,
+		ExpiresAt:  params.ExpiresAt,
+	})
+	if err != nil {
+		if strings.Contains(err.Error(), "UNIQUE constraint failed") {
+			return "", generated.ApiToken{}, fmt.Errorf("%w: %q", ErrDuplicateToken, name)
+		}
+		return "", generated.ApiToken{}, fmt.Errorf("failed to store token: %w", err)
+	}
+
+	for _, projectID := range params.ProjectIDs {
+		if err := queriesTx.AddApiTokenProject(ctx, generated.AddApiTokenProjectParams{
+			TokenID:   row.ID,
+			ProjectID: projectID,
+		}); err != nil {
+			return "", generated.ApiToken{}, fmt.Errorf("failed to store token project: %w", err)
+		}
+	}
+
+	if err := txn.Commit(); err != nil {
+		return "", generated.ApiToken{}, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return token, row, nil
+}
@@ -0,0 +1,14 @@
+package auth
+
+const (
+	// TokenPrefix makes tokens easy to spot in configs and logs
+	TokenPrefix = "si_"
+
+	// CookieName holds the token for the embedded UI
+	CookieName = "si_token"
+
+	// AdminTokenName is the token created on the first start of `serve`
+	AdminTokenName = "admin"
+
+	tokenBytes = 32
+)
@@ -0,0 +1,162 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/Knightshrestha/Secret-Injector/config"
+	"github.com/Knightshrestha/Secret-Injector/utils"
+	"github.com/spf13/cobra"
+)
+
+// allowCmd represents the allow command
+var allowCmd = &cobra.Command{
+	Use:   "allow [path]",
+	Short: "Allow a directory's .secretinjector.toml",
+	Long: `Allow the .secretinjector.toml of path (a file or a directory), or the one
+inject and export would use in the current directory. The file comes with the
+repository, so its settings are only honoured once it has been read and
+allowed, and again after every change to it.`,
+	Example: `  secret_injector allow
+  secret_injector allow ~/src/api`,
+	Args: cobra.MaximumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		path, err := findDirectoryFile(args)
+		if err != nil {
+			fail(err)
+		}
+
+		file, err := config.LoadDirectoryFile(path)
+		if err != nil {
+			fail(usageError("%v", err))
+		}
+		if err := file.Allow(); err != nil {
+			failf("failed to allow %s: %w", file.Path, err)
+		}
+
+		printResult(file, func() {
+			fmt.Printf("✓ Allowed %s\n", file.Path)
+		})
+	},
+}
+
+// denyCmd represents the deny command
+var denyCmd = &cobra.Command{
+	Use:   "deny [path]",
+	Short: "Revoke the approval of a .secretinjector.toml",
+	Args:  cobra.MaximumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		path, err := findDirectoryFile(args)
+		if err != nil {
+			fail(err)
+		}
+
+		denied, err := config.Deny(path)
+		if err != nil {
+			failf("failed to deny %s: %w", path, err)
+		}
+
+		printResult(denyResult{Path: path, Denied: denied}, func() {
+			if denied {
+				fmt.Printf("✓ Denied %s\n", path)
+			} else {
+				fmt.Printf("%s was not allowed\n", path)
+			}
+		})
+	},
+}
+
+type denyResult struct {
+	Path   string `json:"path"`
+	Denied bool   `json:"denied"`
+}
+
+func init() {
+	rootCmd.AddCommand(allowCmd)
+	rootCmd.AddCommand(denyCmd)
+}
+
+// findDirectoryFile returns the .secretinjector.toml named by args: the
+// file itself, the one in a directory, or the one above the working
+// directory when args is empty
+func findDirectoryFile(args []string) (string, error) {
+	if len(args) == 0 {
+		wd, err := os.Getwd()
+		if err != nil {
+			return "", err
+		}
+		path, err := config.FindDirectoryFile(wd)
+		if err != nil {
+			return "", err
+		}
+		if path == "" {
+			return "", notFoundError("no %s in %s or its parents", config.DirectoryFileName, wd)
+		}
+		return path, nil
+	}
+
+	path, err := filepath.Abs(args[0])
+	if err != nil {
+		return "", err
+	}
+	if info, err := os.Stat(path); err == nil && info.IsDir() {
+		path = filepath.Join(path, config.DirectoryFileName)
+	}
+	return path, nil
+}
+
+// directoryFile returns the allowed .secretinjector.toml applying to the
+// working directory, or nil when there is none. Projects given with
+// --project on the command line take precedence and ignore the file.
+func directoryFile(cmd *cobra.Command) (*config.DirectoryFile, error) {
+	if cmd.Flags().Changed("project") {
+		return nil, nil
+	}
+
+	wd, err := os.Getwd()
+	if err != nil {
+		return nil, err
+	}
+	path, err := config.FindDirectoryFile(wd)
+	if err != nil || path == "" {
+		return nil, err
+	}
+
+	file, err := config.LoadDirectoryFile(path)
+	if err != nil {
+		return nil, usageError("%v", err)
+	}
+	if err := file.CheckAllowed(); err != nil {
+		if errors.Is(err, config.ErrNotAllowed) {
+			return nil, usageError("%v, review it and run 'secret_injector allow' (or pass --project)", err)
+		}
+		return nil, err
+	}
+	return file, nil
+}
+
+// useDirectoryFile returns the projects and environment to use, taking
+// them from file when there is one. --env on the command line still wins.
+func useDirectoryFile(cmd *cobra.Command, file *config.DirectoryFile, projects []string, environment string) ([]string, string, error) {
+	if file == nil {
+		return projects, environment, nil
+	}
+
+	if len(file.Projects) > 0 {
+		projects = file.Projects
+	}
+	if file.Env != "" && !cmd.Flags().Changed("env") {
+		env, err := utils.NormalizeEnvironment(file.Env)
+		if err != nil {
+			return nil, "", usageError("%s: %v", file.Path, err)
+		}
+		environment = env
+	}
+
+	if outputFormat == outputText {
+		fmt.Fprintf(os.Stderr, "✓ Using %s\n", file.Path)
+	}
+	return projects, environment, nil
+}
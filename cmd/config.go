@@ -0,0 +1,249 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/Knightshrestha/Secret-Injector/config"
+	"github.com/Knightshrestha/Secret-Injector/database"
+	"github.com/Knightshrestha/Secret-Injector/vault"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+// setting ties a command flag to a key of the config file and to the
+// environment variable SECRET_INJECTOR_<KEY>, e.g. server.port and
+// SECRET_INJECTOR_SERVER_PORT. Flags win over the environment, which wins
+// over the config file, which wins over the flag default.
+type setting struct {
+	key  string
+	cmd  *cobra.Command
+	flag string
+}
+
+var settings = []setting{
+	{"server.bind", serveCmd, "bind"},
+	{"server.port", serveCmd, "port"},
+	{"server.tls_cert", serveCmd, "tls-cert"},
+	{"server.tls_key", serveCmd, "tls-key"},
+	{"server.tls_self_signed", serveCmd, "tls-self-signed"},
+	{"server.socket", serveCmd, "socket"},
+	{"server.cors_origins", serveCmd, "cors-origins"},
+	{"server.backup_interval", serveCmd, "backup-interval"},
+	{"server.backup_keep", serveCmd, "backup-keep"},
+	{"server.debug", serveCmd, "debug"},
+
+	{"inject.projects", injectCmd, "project"},
+	{"inject.env", injectCmd, "env"},
+	{"inject.no_inherit", injectCmd, "no-inherit"},
+	{"inject.allow_env", injectCmd, "allow-env"},
+	{"inject.raw", injectCmd, "raw"},
+	{"inject.file_var", injectCmd, "file-var"},
+	{"inject.mask", injectCmd, "mask"},
+
+	{"export.projects", exportCmd, "project"},
+	{"export.env", exportCmd, "env"},
+	{"export.format", exportCmd, "format"},
+	{"export.raw", exportCmd, "raw"},
+
+	{"redact.projects", redactCmd, "project"},
+	{"redact.env", redactCmd, "env"},
+	{"redact.min_length", redactCmd, "min-length"},
+
+	{"scan.projects", scanCmd, "project"},
+	{"scan.env", scanCmd, "env"},
+	{"scan.min_length", scanCmd, "min-length"},
+
+	{"update.owner", updateCmd, "owner"},
+	{"update.repo", updateCmd, "repo"},
+}
+
+func (s setting) env() string {
+	return "SECRET_INJECTOR_" + strings.ToUpper(strings.ReplaceAll(s.key, ".", "_"))
+}
+
+func (s setting) lookup() *pflag.Flag {
+	return s.cmd.Flags().Lookup(s.flag)
+}
+
+// resolve returns the value a setting takes and where it comes from. Lists
+// are comma separated in the environment.
+func (s setting) resolve(file config.File) ([]string, string) {
+	flag := s.lookup()
+	_, isList := flag.Value.(pflag.SliceValue)
+
+	if flag.Changed {
+		return nil, "flag"
+	}
+	if value, ok := os.LookupEnv(s.env()); ok {
+		if isList {
+			return strings.Split(value, ","), "env " + s.env()
+		}
+		return []string{value}, "env " + s.env()
+	}
+	if values, ok := file.Values[s.key]; ok {
+		return values, "config"
+	}
+	return nil, "default"
+}
+
+// applySettings fills the flags of cmd that were not given from the
+// environment and the config file
+func applySettings(cmd *cobra.Command, file config.File) error {
+	for key := range file.Values {
+		if !knownSetting(key) {
+			return fmt.Errorf("unknown setting %q in %s", key, file.Path)
+		}
+	}
+
+	for _, s := range settings {
+		if s.cmd != cmd {
+			continue
+		}
+
+		values, source := s.resolve(file)
+		if values == nil {
+			continue
+		}
+
+		flag := s.lookup()
+		if list, ok := flag.Value.(pflag.SliceValue); ok {
+			if err := list.Replace(values); err != nil {
+				return fmt.Errorf("invalid %s from %s: %w", s.key, source, err)
+			}
+			continue
+		}
+
+		if len(values) != 1 {
+			return fmt.Errorf("invalid %s from %s: expected a single value", s.key, source)
+		}
+		if err := flag.Value.Set(values[0]); err != nil {
+			return fmt.Errorf("invalid %s from %s: %w", s.key, source, err)
+		}
+	}
+	return nil
+}
+
+func knownSetting(key string) bool {
+	for _, s := range settings {
+		if s.key == key {
+			return true
+		}
+	}
+	return false
+}
+
+// configCmd represents the config command
+var configCmd = &cobra.Command{
+	Use:   "config",
+	Short: "Inspect the configuration",
+	Long: `Settings come from flags, SECRET_INJECTOR_* environment variables, the
+config file and built-in defaults, in that order. The config file is
+~/.config/secret_injector/config.toml unless --config or
+SECRET_INJECTOR_CONFIG points elsewhere.`,
+}
+
+var configShowCmd = &cobra.Command{
+	Use:   "show",
+	Short: "Print the effective configuration and where each value comes from",
+	Example: `  secret_injector config show
+  secret_injector config show --vault work`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		file, err := config.Load()
+		if err != nil {
+			fail(&cliError{code: exitUsage, err: err})
+		}
+
+		loc, err := database.Locate()
+		if err != nil {
+			fail(&cliError{code: exitUsage, err: err})
+		}
+
+		result := configResult{ConfigFile: file.Path, Found: file.Found}
+
+		vaultName, vaultSource := "-", "default"
+		if loc.Vault != "" {
+			vaultName, vaultSource = loc.Vault, "env "+database.EnvVault
+			if database.VaultFlag != "" {
+				vaultSource = "flag"
+			}
+		}
+		keyFile, keySource := keyFileSetting(loc)
+		result.Settings = append(result.Settings,
+			configSetting{"vault", vaultName, vaultSource},
+			configSetting{"db", loc.DB, loc.Source},
+			configSetting{"key_file", keyFile, keySource},
+		)
+
+		for _, s := range settings {
+			values, source := s.resolve(file)
+
+			flag := s.lookup()
+			value := flag.Value.String()
+			if values != nil {
+				value = strings.Join(values, ",")
+			} else if source == "default" {
+				value = flag.DefValue
+			}
+			if _, isList := flag.Value.(pflag.SliceValue); isList && values == nil {
+				value = strings.TrimSuffix(strings.TrimPrefix(value, "["), "]")
+			}
+			if value == "" {
+				value = "-"
+			}
+
+			result.Settings = append(result.Settings, configSetting{s.key, value, source})
+		}
+
+		printResult(result, func() {
+			if result.Found {
+				fmt.Printf("Config file: %s\n\n", result.ConfigFile)
+			} else {
+				fmt.Printf("Config file: %s (not found)\n\n", result.ConfigFile)
+			}
+
+			w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+			fmt.Fprintln(w, "KEY\tVALUE\tSOURCE")
+			for _, setting := range result.Settings {
+				fmt.Fprintf(w, "%s\t%s\t%s\n", setting.Key, setting.Value, setting.Source)
+			}
+			w.Flush()
+		})
+	},
+}
+
+type configResult struct {
+	ConfigFile string          `json:"config_file"`
+	Found      bool            `json:"found"`
+	Settings   []configSetting `json:"settings"`
+}
+
+type configSetting struct {
+	Key    string `json:"key"`
+	Value  string `json:"value"`
+	Source string `json:"source"`
+}
+
+// keyFileSetting reports the master key file the same way vault picks it
+func keyFileSetting(loc database.Location) (string, string) {
+	switch {
+	case rootCmd.PersistentFlags().Changed("key-file"):
+		return vault.KeyFile, "flag"
+	case os.Getenv(vault.EnvKeyFile) != "":
+		return os.Getenv(vault.EnvKeyFile), "env " + vault.EnvKeyFile
+	case os.Getenv(vault.EnvKey) != "":
+		return "(key in environment)", "env " + vault.EnvKey
+	case loc.KeyFile != "":
+		return loc.KeyFile, "config"
+	default:
+		return "- (passphrase)", "default"
+	}
+}
+
+func init() {
+	rootCmd.AddCommand(configCmd)
+	configCmd.AddCommand(configShowCmd)
+}
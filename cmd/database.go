@@ -0,0 +1,39 @@
+package cmd
+
+import (
+	"errors"
+
+	"github.com/Knightshrestha/Secret-Injector/database"
+	"github.com/spf13/cobra"
+)
+
+// openWriteDatabase opens the database for commands that change it, pending
+// migrations are applied first
+func openWriteDatabase() database.DB_Struct {
+	mainDb, err := database.OpenMigratedWriteDatabase()
+	if err != nil {
+		failf("failed to open database: %w", err)
+	}
+	return mainDb
+}
+
+// openReadDatabase opens the database for commands that only read, pending
+// migrations are applied first. Reads are audited with audit.RecordLocal,
+// which opens a write connection only when there is something to record.
+func openReadDatabase() database.DB_Struct {
+	mainDb, err := database.OpenMigratedReadDatabase()
+	if err != nil {
+		failf("failed to open database: %w", err)
+	}
+	return mainDb
+}
+
+// lockDatabase keeps `serve` from running while cmd changes the data key or
+// replaces the database file, and refuses to start while it is running
+func lockDatabase(cmd *cobra.Command) (func(), error) {
+	release, err := database.Lock(cmd.Name())
+	if errors.Is(err, database.ErrInUse) {
+		return nil, conflictError("%v, stop it before running %s", err, cmd.Name())
+	}
+	return release, err
+}
@@ -0,0 +1,176 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	"github.com/Knightshrestha/Secret-Injector/database"
+	"github.com/spf13/cobra"
+)
+
+var migrateTo int
+var migrateSteps int
+var migrateYes bool
+
+// dbCmd represents the db command
+var dbCmd = &cobra.Command{
+	Use:   "db",
+	Short: "Maintain the database",
+	Long:  `Inspect and change the database schema.`,
+}
+
+var dbMigrateCmd = &cobra.Command{
+	Use:   "migrate",
+	Short: "Apply or roll back schema migrations",
+	Long: `The database schema is versioned by numbered migrations built into the
+binary. Pending migrations are applied automatically by "setup", "serve" and
+the other commands, so "migrate up" is only needed to stop at a given version.`,
+}
+
+var dbMigrateStatusCmd = &cobra.Command{
+	Use:   "status",
+	Short: "List migrations and whether they are applied",
+	Args:  cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		states, current, err := database.MigrationStatus()
+		if err != nil {
+			failf("failed to read migration status: %w", err)
+		}
+
+		result := migrateStatusResult{
+			Version:       current,
+			LatestVersion: database.LatestSchemaVersion(),
+			Migrations:    []migrationResult{},
+		}
+		for _, state := range states {
+			result.Migrations = append(result.Migrations, migrationResult{
+				Version:   state.Version,
+				Name:      state.Name,
+				AppliedAt: state.AppliedAt,
+			})
+		}
+
+		printResult(result, func() {
+			w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+			fmt.Fprintln(w, "VERSION\tNAME\tAPPLIED")
+			for _, state := range states {
+				applied := "pending"
+				if state.AppliedAt != nil {
+					applied = formatTime(state.AppliedAt)
+				}
+				fmt.Fprintf(w, "%04d\t%s\t%s\n", state.Version, state.Name, applied)
+			}
+			w.Flush()
+
+			fmt.Printf("\nDatabase at version %d, binary supports %d\n", current, result.LatestVersion)
+		})
+		if current > result.LatestVersion {
+			fail(database.ErrSchemaTooNew)
+		}
+	},
+}
+
+var dbMigrateUpCmd = &cobra.Command{
+	Use:   "up",
+	Short: "Apply pending migrations",
+	Long: `Apply pending migrations, up to --to when given. An existing database is
+backed up next to it first.`,
+	Example: `  secret_injector db migrate up
+  secret_injector db migrate up --to 5`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		if migrateTo < 0 {
+			fail(usageError("--to must not be negative"))
+		}
+
+		applied, err := database.MigrateUp(migrateTo)
+		if err != nil {
+			failf("failed to migrate database: %w", err)
+		}
+
+		_, current, err := database.MigrationStatus()
+		if err != nil {
+			failf("failed to read migration status: %w", err)
+		}
+
+		result := migrateResult{Version: current, Migrations: []migrationResult{}}
+		for _, migration := range applied {
+			result.Migrations = append(result.Migrations, migrationResult{Version: migration.Version, Name: migration.Name})
+		}
+
+		printResult(result, func() {
+			if len(applied) == 0 {
+				fmt.Println("✓ Database is already up to date")
+				return
+			}
+			fmt.Printf("✓ Applied %d migration(s), database at version %d\n", len(applied), current)
+		})
+	},
+}
+
+var dbMigrateDownCmd = &cobra.Command{
+	Use:   "down",
+	Short: "Roll back the latest migrations",
+	Long: `Roll back the latest --steps migrations. Rolling back can drop data that
+the older schema has no place for, e.g. secrets outside the base environment,
+so --yes is required. The database is backed up next to it first.`,
+	Example: `  secret_injector db migrate down --yes
+  secret_injector db migrate down --steps 2 --yes`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		if migrateSteps < 1 {
+			fail(usageError("--steps must be at least 1"))
+		}
+		if !migrateYes {
+			fail(usageError("rolling back migrations can lose data, pass --yes to continue"))
+		}
+
+		rolledBack, err := database.MigrateDown(migrateSteps)
+		if err != nil {
+			failf("failed to roll back database: %w", err)
+		}
+
+		last := rolledBack[len(rolledBack)-1]
+		result := migrateResult{Version: last.Version - 1, Migrations: []migrationResult{}}
+		for _, migration := range rolledBack {
+			result.Migrations = append(result.Migrations, migrationResult{Version: migration.Version, Name: migration.Name})
+		}
+
+		printResult(result, func() {
+			fmt.Printf("✓ Rolled back %d migration(s), database at version %d\n", len(rolledBack), result.Version)
+		})
+	},
+}
+
+type migrationResult struct {
+	Version   int        `json:"version"`
+	Name      string     `json:"name"`
+	AppliedAt *time.Time `json:"applied_at,omitempty"`
+}
+
+type migrateStatusResult struct {
+	Version       int               `json:"version"`
+	LatestVersion int               `json:"latest_version"`
+	Migrations    []migrationResult `json:"migrations"`
+}
+
+// migrateResult lists the migrations applied or rolled back and the version
+// the database ends at
+type migrateResult struct {
+	Version    int               `json:"version"`
+	Migrations []migrationResult `json:"migrations"`
+}
+
+func init() {
+	rootCmd.AddCommand(dbCmd)
+	dbCmd.AddCommand(dbMigrateCmd)
+	dbMigrateCmd.AddCommand(dbMigrateStatusCmd)
+	dbMigrateCmd.AddCommand(dbMigrateUpCmd)
+	dbMigrateCmd.AddCommand(dbMigrateDownCmd)
+
+	dbMigrateUpCmd.Flags().IntVar(&migrateTo, "to", 0, "Stop at this schema version (default latest)")
+	dbMigrateDownCmd.Flags().IntVar(&migrateSteps, "steps", 1, "Number of migrations to roll back")
+	dbMigrateDownCmd.Flags().BoolVar(&migrateYes, "yes", false, "Confirm the rollback")
+}
@@ -2,44 +2,76 @@ package cmd
 
 import (
 	"fmt"
-	"log"
+	"os"
 	"strings"
 
+	"github.com/Knightshrestha/Secret-Injector/audit"
 	"github.com/Knightshrestha/Secret-Injector/core/db_ro"
 	"github.com/Knightshrestha/Secret-Injector/database/generated"
+	"github.com/Knightshrestha/Secret-Injector/exporter"
+	"github.com/Knightshrestha/Secret-Injector/utils"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/spf13/cobra"
 )
 
+var exportProjects []string
+var exportFormat string
+var exportOut string
+var exportEnv string
+var exportRaw bool
+
 // exportCmd represents the export command
 var exportCmd = &cobra.Command{
 	Use:   "export",
-	Short: "Initialize and select projects",
-	Long:  `Fetch available projects and allow user to select one or multiple projects.`,
+	Short: "Export secrets to an env file",
+	Long: `Export the secrets of the selected projects as dotenv, JSON, YAML, shell or
+Docker env-file. Without --project the projects are picked interactively.
+--env layers an environment such as prod over the base values of each
+project. ${KEY} references between the values, also across projects, are
+resolved as for inject unless --raw is given. Output goes to stdout unless
+--out is given, files are written atomically with 0600 permissions.
+
+Without --project the nearest allowed .secretinjector.toml selects the
+projects, environment and keys, see inject.`,
+	Example: `  secret_injector export --project API --format dotenv --out .env
+  secret_injector export --project API --env prod -f json
+  secret_injector export -p API -p SHARED -f json > secrets.json`,
 	Run: func(cmd *cobra.Command, args []string) {
-		// Fetch projects
-		projects, err := db_ro.FetchProjects()
+		format, err := exporter.ParseFormat(exportFormat)
 		if err != nil {
-			log.Fatalf("Something went wrong, fetching projects: %s", err)
+			fail(usageError("%v", err))
 		}
 
-		if len(projects) == 0 {
-			fmt.Println("No projects available")
-			return
+		environment, err := utils.NormalizeEnvironment(exportEnv)
+		if err != nil {
+			fail(usageError("%v", err))
+		}
+
+		dirFile, err := directoryFile(cmd)
+		if err != nil {
+			fail(err)
 		}
 
-		// Run interactive selector
-		selectedProjects := selectProjects(projects)
+		projectNames, environment, err := useDirectoryFile(cmd, dirFile, exportProjects, environment)
+		if err != nil {
+			fail(err)
+		}
+
+		selectedProjects, err := resolveProjects(projectNames)
+		if err != nil {
+			fail(err)
+		}
 
-		// Display results
 		if len(selectedProjects) == 0 {
-			fmt.Println("No projects selected")
-			return
+			fail(usageError("no projects selected"))
 		}
 
-		fmt.Println("\n✓ Selected projects:")
-		for _, project := range selectedProjects {
-			fmt.Printf("  • %s (ID: %s)\n", project.Name, project.ID)
+		// Only echo the choice back when it was made interactively
+		if len(projectNames) == 0 && outputFormat == outputText {
+			fmt.Fprintln(os.Stderr, "\n✓ Selected projects:")
+			for _, project := range selectedProjects {
+				fmt.Fprintf(os.Stderr, "  • %s (ID: %s)\n", project.Name, project.ID)
+			}
 		}
 
 		var projectIDs []string
@@ -47,30 +79,78 @@ var exportCmd = &cobra.Command{
 			projectIDs = append(projectIDs, project.ID)
 		}
 
-		allSecrets, err := db_ro.FetchSecrets(projectIDs)
+		allSecrets, err := db_ro.FetchSecrets(projectIDs, environment, audit.ActionExport)
+		if err != nil {
+			failf("failed to fetch secrets: %w", err)
+		}
+
+		secrets := utils.SecretsToMap(allSecrets)
+		if !exportRaw {
+			if secrets, err = utils.Interpolate(secrets, utils.FileKeys(allSecrets)); err != nil {
+				failf("failed to resolve references: %w", err)
+			}
+		}
+		if dirFile != nil {
+			secrets = dirFile.Apply(secrets)
+		}
+
+		data, err := exporter.Render(format, secrets)
 		if err != nil {
-			log.Fatalf("Something went wrong, fetching secrets: %s", err)
+			fail(err)
+		}
+
+		result := exportResult{
+			Projects:    selectedProjects,
+			Environment: environment,
+			Format:      string(format),
+			Count:       len(secrets),
 		}
-		
-		if len(allSecrets) == 0 {
-			fmt.Println("No secrets to display")
+
+		if exportOut == "" || exportOut == "-" {
+			// With --output json the rendered file is a field of the result
+			// instead of the whole of stdout
+			content := string(data)
+			result.Content = &content
+			printResult(result, func() {
+				os.Stdout.Write(data)
+			})
 			return
 		}
 
-		fmt.Println("\n✓ Selected Secrets:")
-		for _, secret := range allSecrets {
-			fmt.Printf("  • %s: %s (ID: %s)\n", secret.Key, secret.Value, secret.ID)
+		if err := exporter.WriteFile(exportOut, data); err != nil {
+			fail(err)
 		}
 
+		result.Path = exportOut
+		printResult(result, func() {
+			fmt.Fprintf(os.Stderr, "✓ Exported %d secrets to %s\n", len(secrets), exportOut)
+		})
 	},
 }
 
+type exportResult struct {
+	Projects    []generated.ProjectList `json:"projects"`
+	Environment string                  `json:"environment"`
+	Format      string                  `json:"format"`
+	Count       int                     `json:"count"`
+	Path        string                  `json:"path,omitempty"`
+	Content     *string                 `json:"content,omitempty"`
+}
+
 func init() {
 	rootCmd.AddCommand(exportCmd)
+
+	exportCmd.Flags().StringArrayVarP(&exportProjects, "project", "p", nil, "Project name or ID to export (repeatable, later wins)")
+	exportCmd.Flags().StringVarP(&exportFormat, "format", "f", "dotenv", "Output format: dotenv, json, yaml, shell or docker")
+	exportCmd.Flags().StringVarP(&exportEnv, "env", "e", utils.BaseEnvironment, "Environment to resolve, its values override the base ones")
+	exportCmd.Flags().BoolVar(&exportRaw, "raw", false, "Export values as stored, without resolving ${KEY} references")
+	exportCmd.Flags().StringVarP(&exportOut, "out", "o", "", "File to write to (default stdout)")
 }
 
-// selectProjects runs the interactive multi-select
-func selectProjects(projects []generated.ProjectList) []generated.ProjectList {
+// selectProjects runs the interactive multi-select. The projects come back
+// in list order, which is the order later projects win in. Quitting with
+// q or Ctrl+C cancels the command.
+func selectProjects(projects []generated.ProjectList) ([]generated.ProjectList, error) {
 	m := model{
 		projects: projects,
 		selected: make(map[int]bool),
@@ -80,17 +160,22 @@ func selectProjects(projects []generated.ProjectList) []generated.ProjectList {
 	p := tea.NewProgram(m)
 	result, err := p.Run()
 	if err != nil {
-		fmt.Printf("Error: %v\n", err)
-		return []generated.ProjectList{}
+		return nil, fmt.Errorf("project selection failed: %w", err)
 	}
 
 	finalModel := result.(model)
+	if finalModel.canceled {
+		return nil, usageError("project selection canceled")
+	}
+
 	var selected []generated.ProjectList
-	for idx := range finalModel.selected {
-		selected = append(selected, projects[idx])
+	for idx, project := range projects {
+		if finalModel.selected[idx] {
+			selected = append(selected, project)
+		}
 	}
 
-	return selected
+	return selected, nil
 }
 
 // Bubbletea Model
@@ -99,6 +184,7 @@ type model struct {
 	selected map[int]bool
 	cursor   int
 	quitting bool
+	canceled bool
 }
 
 func (m model) Init() tea.Cmd {
@@ -111,6 +197,7 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		switch msg.String() {
 		case "ctrl+c", "q":
 			m.quitting = true
+			m.canceled = true
 			return m, tea.Quit
 
 		case "up", "k":
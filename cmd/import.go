@@ -0,0 +1,161 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"text/tabwriter"
+
+	"github.com/Knightshrestha/Secret-Injector/audit"
+	"github.com/Knightshrestha/Secret-Injector/core/db_rw"
+	"github.com/Knightshrestha/Secret-Injector/database"
+	"github.com/Knightshrestha/Secret-Injector/importer"
+	"github.com/Knightshrestha/Secret-Injector/utils"
+	"github.com/Knightshrestha/Secret-Injector/vault"
+	"github.com/spf13/cobra"
+)
+
+var importProject string
+var importFormat string
+var importEnv string
+var importConflict string
+var importDryRun bool
+
+// importCmd represents the import command
+var importCmd = &cobra.Command{
+	Use:   "import --project NAME FILE",
+	Short: "Import secrets from a dotenv, JSON or YAML file",
+	Long: `Import the keys and values of a file into a project, in the environment
+given with --env. FILE - reads stdin.
+
+The format is taken from the file extension (.json, .yaml, .yml, anything
+else is dotenv) unless --format is given. Dotenv files may use quotes,
+multiline values and export prefixes, JSON and YAML files must be a flat
+mapping of keys to values. Keys are normalised the same way as in the UI.
+
+Keys that already hold a different value are handled by --on-conflict: skip
+keeps the stored value, overwrite replaces it (keeping the old one as a
+version) and fail imports nothing. Everything is applied in one transaction,
+--dry-run only shows what would change.`,
+	Example: `  secret_injector import --project API .env --dry-run
+  secret_injector import --project API --env prod config.yaml --on-conflict overwrite
+  cat secrets.json | secret_injector import -p API -f json -`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		if importProject == "" {
+			fail(usageError("--project is required"))
+		}
+
+		format := importer.DetectFormat(args[0])
+		if cmd.Flags().Changed("format") {
+			var err error
+			if format, err = importer.ParseFormat(importFormat); err != nil {
+				fail(usageError("%v", err))
+			}
+		}
+
+		policy, err := db_rw.ParseConflictPolicy(importConflict)
+		if err != nil {
+			fail(err)
+		}
+
+		data, err := readImportFile(args[0])
+		if err != nil {
+			fail(err)
+		}
+
+		entries, err := importer.Parse(format, data)
+		if err != nil {
+			fail(usageError("%s: %v", args[0], err))
+		}
+
+		mainDb := openWriteDatabase()
+		defer database.CloseWriteDatabase(mainDb.DB)
+
+		ctx := context.Background()
+
+		cipher, err := vault.Unlock(ctx, mainDb.Queries)
+		if err != nil {
+			fail(err)
+		}
+
+		project := lookupProject(ctx, mainDb.Queries, importProject)
+
+		result, err := db_rw.ImportSecrets(ctx, mainDb.DB, mainDb.Queries, cipher, db_rw.ImportParams{
+			ProjectID:   project.ID,
+			Environment: importEnv,
+			Entries:     entries,
+			Policy:      policy,
+			DryRun:      importDryRun,
+		}, audit.LocalOrigin())
+		if err != nil {
+			// Show which keys are in the way before failing
+			if errors.Is(err, db_rw.ErrImportConflict) && outputFormat == outputText {
+				printImportDiff(result)
+			}
+			fail(err)
+		}
+
+		printResult(result, func() {
+			printImportDiff(result)
+
+			summary := fmt.Sprintf("%d added, %d changed, %d unchanged, %d skipped",
+				result.Added, result.Changed, result.Unchanged, result.Skipped)
+			if result.DryRun {
+				fmt.Printf("Dry run, nothing was changed in %s (environment %s): %s\n", project.Name, result.Environment, summary)
+				return
+			}
+			fmt.Printf("✓ Imported into %s (environment %s): %s\n", project.Name, result.Environment, summary)
+		})
+	},
+}
+
+func readImportFile(path string) ([]byte, error) {
+	if path == "-" {
+		data, err := io.ReadAll(os.Stdin)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read stdin: %w", err)
+		}
+		return data, nil
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, notFoundError("%s does not exist", path)
+		}
+		return nil, fmt.Errorf("failed to read %s: %w", path, err)
+	}
+	return data, nil
+}
+
+func printImportDiff(result db_rw.ImportResult) {
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	for _, item := range result.Items {
+		switch item.Action {
+		case db_rw.ImportAdd:
+			fmt.Fprintf(w, "+ %s\tadd\n", item.Key)
+		case db_rw.ImportChange:
+			fmt.Fprintf(w, "~ %s\tchange\n", item.Key)
+		case db_rw.ImportUnchanged:
+			fmt.Fprintf(w, "  %s\tunchanged\n", item.Key)
+		case db_rw.ImportSkip:
+			fmt.Fprintf(w, "! %s\tskip, keeps the stored value\n", item.Key)
+		case db_rw.ImportConflict:
+			fmt.Fprintf(w, "✗ %s\tconflict, the stored value differs\n", item.Key)
+		}
+	}
+	w.Flush()
+}
+
+func init() {
+	rootCmd.AddCommand(importCmd)
+
+	importCmd.Flags().StringVarP(&importProject, "project", "p", "", "Project name or ID to import into")
+	importCmd.Flags().StringVarP(&importFormat, "format", "f", "", "File format: dotenv, json or yaml (default from the extension)")
+	importCmd.Flags().StringVarP(&importEnv, "env", "e", utils.BaseEnvironment, "Environment to import into")
+	importCmd.Flags().StringVar(&importConflict, "on-conflict", string(db_rw.ConflictSkip), "What to do with keys holding another value: skip, overwrite or fail")
+	importCmd.Flags().BoolVar(&importDryRun, "dry-run", false, "Only show what would change")
+}
@@ -1,30 +1,153 @@
 package cmd
 
 import (
-	"fmt"
+	"os"
+	"strings"
 
+	"github.com/Knightshrestha/Secret-Injector/audit"
+	"github.com/Knightshrestha/Secret-Injector/core/db_ro"
+	"github.com/Knightshrestha/Secret-Injector/injector"
+	"github.com/Knightshrestha/Secret-Injector/utils"
 	"github.com/spf13/cobra"
 )
 
+var injectProjects []string
+var injectNoInherit bool
+var injectAllowEnv []string
+var injectEnv string
+var injectRaw bool
+var injectFileVar string
+var injectMask bool
+
 // injectCmd represents the inject command
 var injectCmd = &cobra.Command{
-	Use:   "inject",
+	Use:   "inject [flags] -- command [args...]",
 	Short: "Inject secrets and run commands",
+	Long: `Run a command with the secrets of the selected projects added to its environment.
+
+Secrets override variables of the same name from the parent environment, and
+projects listed later override earlier ones. Without --project the projects
+are picked interactively. --env layers an environment such as prod over the
+base values of each project. ${KEY} references between the values, also
+across projects, are resolved unless --raw is given ($${KEY} is a literal
+${KEY}, a reference to a key that is not set is kept as written). The
+contents of file secrets are never resolved.
+
+Secrets of type file are written to a private temporary directory (in memory
+where the system offers one) and the command gets their path instead, in
+KEY_FILE by default (--file-var). The files are shredded when the command
+exits, and by the next run if inject itself was killed.
+
+--mask replaces the injected values, also base64 or URL encoded, with
+***KEY*** in the command's output. In a terminal the command runs on a
+pseudo-terminal of its own (Linux) so it stays interactive, otherwise its
+stdout and stderr are masked separately.
+
+SIGINT, SIGTERM and SIGHUP are forwarded to the command and its exit code is
+returned, 128 plus the signal number when a signal killed it.
+
+Without --project the nearest .secretinjector.toml in the working directory
+or its parents selects the projects, environment and keys, once it has been
+allowed with 'secret_injector allow'.`,
+	Example: `  secret_injector inject --project API -- npm start
+  secret_injector inject -- npm start   # projects from .secretinjector.toml
+  secret_injector inject --project API --env prod -- npm start
+  secret_injector inject -p API -p SHARED --no-inherit --allow-env PATH -- ./server
+  secret_injector inject -p API --mask -- npm test`,
+	Args: cobra.MinimumNArgs(1),
 	Run: func(cmd *cobra.Command, args []string) {
-		fmt.Println("inject called")
+		environment, err := utils.NormalizeEnvironment(injectEnv)
+		if err != nil {
+			fail(usageError("%v", err))
+		}
+
+		if !strings.Contains(injectFileVar, "{KEY}") {
+			fail(usageError("--file-var must contain {KEY}"))
+		}
+
+		dirFile, err := directoryFile(cmd)
+		if err != nil {
+			fail(err)
+		}
+
+		projectNames, environment, err := useDirectoryFile(cmd, dirFile, injectProjects, environment)
+		if err != nil {
+			fail(err)
+		}
+
+		projects, err := resolveProjects(projectNames)
+		if err != nil {
+			fail(err)
+		}
+
+		if len(projects) == 0 {
+			fail(usageError("no projects selected"))
+		}
+
+		var projectIDs []string
+		for _, project := range projects {
+			projectIDs = append(projectIDs, project.ID)
+		}
+
+		secrets, err := db_ro.FetchSecrets(projectIDs, environment, audit.ActionInject)
+		if err != nil {
+			failf("failed to fetch secrets: %w", err)
+		}
+
+		// The type of the secret that won a key decides how it is passed
+		values := utils.SecretsToMap(secrets)
+		fileKeys := utils.FileKeys(secrets)
+		if !injectRaw {
+			if values, err = utils.Interpolate(values, fileKeys); err != nil {
+				failf("failed to resolve references: %w", err)
+			}
+		}
+
+		files := make(map[string]string)
+		for key, isFile := range fileKeys {
+			if isFile {
+				files[key] = values[key]
+				delete(values, key)
+			}
+		}
+
+		if dirFile != nil {
+			values = dirFile.Apply(values)
+			files = dirFile.Apply(files)
+		}
+
+		inj := &injector.Injector{
+			Command:  args[0],
+			Args:     args[1:],
+			Secrets:  values,
+			Inherit:  !injectNoInherit,
+			AllowEnv: injectAllowEnv,
+			Files:    files,
+			FileVar:  injectFileVar,
+			Mask:     injectMask,
+		}
+
+		// The command's own exit code is passed through, only a failure to
+		// start it is reported in the --output format
+		code, err := inj.Run()
+		if err != nil {
+			reportError(err)
+		}
+		os.Exit(code)
 	},
 }
 
 func init() {
 	rootCmd.AddCommand(injectCmd)
 
-	// Here you will define your flags and configuration settings.
-
-	// Cobra supports Persistent Flags which will work for this command
-	// and all subcommands, e.g.:
-	// injectCmd.PersistentFlags().String("foo", "", "A help for foo")
+	// Everything after the command name belongs to the command
+	injectCmd.Flags().SetInterspersed(false)
 
-	// Cobra supports local flags which will only run when this command
-	// is called directly, e.g.:
-	// injectCmd.Flags().BoolP("toggle", "t", false, "Help message for toggle")
+	injectCmd.Flags().StringArrayVarP(&injectProjects, "project", "p", nil, "Project name or ID to inject (repeatable, later wins)")
+	injectCmd.Flags().StringVarP(&injectEnv, "env", "e", utils.BaseEnvironment, "Environment to resolve, its values override the base ones")
+	injectCmd.Flags().BoolVar(&injectRaw, "raw", false, "Pass values as stored, without resolving ${KEY} references")
+	injectCmd.Flags().BoolVar(&injectNoInherit, "no-inherit", false, "Start from an empty environment instead of the parent one")
+	injectCmd.Flags().StringVar(&injectFileVar, "file-var", injector.DefaultFileVar, "Variable holding the path of a file secret, {KEY} is the secret key")
+	injectCmd.Flags().BoolVar(&injectMask, "mask", false, "Replace secret values in the command's output with ***KEY***")
+	injectCmd.Flags().StringSliceVar(&injectAllowEnv, "allow-env", nil, "Parent variables to keep with --no-inherit (e.g. PATH,HOME)")
 }
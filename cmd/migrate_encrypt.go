@@ -0,0 +1,158 @@
+package cmd
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"os"
+
+	"github.com/Knightshrestha/Secret-Injector/database"
+	"github.com/Knightshrestha/Secret-Injector/database/generated"
+	"github.com/Knightshrestha/Secret-Injector/vault"
+	"github.com/spf13/cobra"
+)
+
+var generateKeyFile string
+
+// migrateEncryptCmd represents the migrate-encrypt command
+var migrateEncryptCmd = &cobra.Command{
+	Use:   "migrate-encrypt",
+	Short: "Encrypt secret values stored in plaintext",
+	Long: `Enable encryption at rest and seal every plaintext secret value.
+
+Each value is encrypted with AES-GCM under a data key, which is itself wrapped
+by a master key. The master key is read from --key-file, SECRET_INJECTOR_KEY_FILE
+or SECRET_INJECTOR_KEY (32 bytes, raw or base64), otherwise it is derived with
+Argon2id from SECRET_INJECTOR_PASSPHRASE or a passphrase prompt.
+
+Running it again on an encrypted database seals any values still left in
+plaintext. Everything happens in a single transaction. Stop "serve" first.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		// A running server would keep reading and writing plaintext
+		release, err := lockDatabase(cmd)
+		if err != nil {
+			fail(err)
+		}
+		defer release()
+
+		mainDb := openWriteDatabase()
+		defer database.CloseWriteDatabase(mainDb.DB)
+
+		ctx := context.Background()
+
+		txn, err := mainDb.DB.BeginTx(ctx, nil)
+		if err != nil {
+			failf("failed to begin transaction: %w", err)
+		}
+		defer txn.Rollback()
+		queriesTx := mainDb.Queries.WithTx(txn)
+
+		cipher, keyFile, err := unlockOrInitialize(ctx, queriesTx)
+		if err != nil {
+			fail(err)
+		}
+
+		count, err := cipher.EncryptPlaintextRows(ctx, queriesTx)
+		if err != nil {
+			failDiscarding(keyFile, fmt.Errorf("failed to encrypt secrets: %w", err))
+		}
+
+		if err := cipher.VerifyRows(ctx, queriesTx); err != nil {
+			failDiscarding(keyFile, fmt.Errorf("verification failed, nothing was changed: %w", err))
+		}
+
+		if err := txn.Commit(); err != nil {
+			failDiscarding(keyFile, fmt.Errorf("failed to commit transaction: %w", err))
+		}
+
+		// The new key file only appears once the data is sealed under it
+		if keyFile != nil {
+			if err := keyFile.Keep(); err != nil {
+				failf("encrypted, but %w", err)
+			}
+		}
+
+		// The plaintext is still in the pages the values were replaced in
+		if err := database.Scrub(mainDb.DB); err != nil {
+			failf("encrypted, but failed to scrub the old values from the database file: %w", err)
+		}
+
+		backups, err := database.PlaintextBackups()
+		if err != nil {
+			failf("encrypted, but failed to check the backups: %w", err)
+		}
+
+		result := migrateEncryptResult{Encrypted: count, KeyFile: generateKeyFile, PlaintextBackups: backups}
+		printResult(result, func() {
+			if keyFile != nil {
+				fmt.Printf("✓ New master key written to %s, keep it safe\n", keyFile.Path)
+			}
+			fmt.Printf("✓ Encrypted %d secret value(s)\n", count)
+			for _, backup := range backups {
+				fmt.Fprintf(os.Stderr, "⚠ %s still holds plaintext values, delete it once it is no longer needed\n", backup)
+			}
+		})
+	},
+}
+
+type migrateEncryptResult struct {
+	Encrypted int    `json:"encrypted"`
+	KeyFile   string `json:"key_file,omitempty"`
+
+	// PlaintextBackups are backups next to the database taken before the
+	// values were encrypted
+	PlaintextBackups []string `json:"plaintext_backups,omitempty"`
+}
+
+// unlockOrInitialize opens the existing data key, or creates one when the
+// database has never been encrypted. A key file generated for it is
+// returned too, still waiting to be kept.
+func unlockOrInitialize(ctx context.Context, queries *generated.Queries) (*vault.Cipher, *vault.NewKeyFile, error) {
+	row, err := queries.GetActiveEncryptionKey(ctx)
+	if err == nil {
+		if generateKeyFile != "" {
+			return nil, nil, fmt.Errorf("database is already encrypted, use `rekey` to change the master key")
+		}
+		cipher, err := vault.Unlock(ctx, queries)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to unlock database (key %s): %w", row.ID, err)
+		}
+		return cipher, nil, nil
+	}
+	if err != sql.ErrNoRows {
+		return nil, nil, fmt.Errorf("failed to read encryption key: %w", err)
+	}
+
+	if generateKeyFile == "" {
+		master, err := vault.LoadMasterKey(true)
+		if err != nil {
+			return nil, nil, err
+		}
+		cipher, err := vault.Initialize(ctx, queries, master)
+		return cipher, nil, err
+	}
+
+	keyFile, err := vault.GenerateKeyFile(generateKeyFile)
+	if err != nil {
+		return nil, nil, err
+	}
+	cipher, err := vault.Initialize(ctx, queries, keyFile.Key)
+	if err != nil {
+		keyFile.Discard()
+		return nil, nil, err
+	}
+	return cipher, keyFile, nil
+}
+
+// failDiscarding removes a generated key file that was never put to use,
+// then fails with err
+func failDiscarding(keyFile *vault.NewKeyFile, err error) {
+	keyFile.Discard()
+	fail(err)
+}
+
+func init() {
+	rootCmd.AddCommand(migrateEncryptCmd)
+
+	migrateEncryptCmd.Flags().StringVar(&generateKeyFile, "generate-key-file", "", "Create a new random master key file at this path and use it")
+}
@@ -0,0 +1,200 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/Knightshrestha/Secret-Injector/auth"
+	"github.com/Knightshrestha/Secret-Injector/core/db_rw"
+	"github.com/Knightshrestha/Secret-Injector/vault"
+	"gopkg.in/yaml.v3"
+)
+
+// Exit codes are part of the CLI contract, scripts may rely on them. A
+// command run by inject exits with its own code instead.
+const (
+	exitInternal = 1
+	exitUsage    = 2
+	exitNotFound = 3
+	exitConflict = 4
+	exitAuth     = 5
+	exitLeak     = 6
+)
+
+// errorCodes names the exit codes in --output json and yaml
+var errorCodes = map[int]string{
+	exitInternal: "internal",
+	exitUsage:    "usage",
+	exitNotFound: "not_found",
+	exitConflict: "conflict",
+	exitAuth:     "auth",
+	exitLeak:     "leak",
+}
+
+const (
+	outputText = "text"
+	outputJSON = "json"
+	outputYAML = "yaml"
+)
+
+var outputFormat string
+
+// cliError is an error that ends the process with code
+type cliError struct {
+	code int
+	err  error
+}
+
+func (e *cliError) Error() string {
+	return e.err.Error()
+}
+
+func (e *cliError) Unwrap() error {
+	return e.err
+}
+
+func usageError(format string, a ...any) error {
+	return &cliError{code: exitUsage, err: fmt.Errorf(format, a...)}
+}
+
+func notFoundError(format string, a ...any) error {
+	return &cliError{code: exitNotFound, err: fmt.Errorf(format, a...)}
+}
+
+func conflictError(format string, a ...any) error {
+	return &cliError{code: exitConflict, err: fmt.Errorf(format, a...)}
+}
+
+// leakError is a secret value found where it should not be
+func leakError(format string, a ...any) error {
+	return &cliError{code: exitLeak, err: fmt.Errorf(format, a...)}
+}
+
+// exitCode classifies err
+func exitCode(err error) int {
+	var cliErr *cliError
+	var invalidInput *db_rw.InvalidInputError
+
+	switch {
+	case errors.As(err, &cliErr):
+		return cliErr.code
+	case errors.As(err, &invalidInput):
+		return exitUsage
+	case errors.Is(err, db_rw.ErrProjectNotFound),
+		errors.Is(err, db_rw.ErrSecretNotFound),
+		errors.Is(err, db_rw.ErrVersionNotFound),
+		errors.Is(err, db_rw.ErrDeletedNotFound):
+		return exitNotFound
+	case errors.Is(err, db_rw.ErrDuplicateProject),
+		errors.Is(err, db_rw.ErrDuplicateKey),
+		errors.Is(err, db_rw.ErrImportConflict),
+		errors.Is(err, db_rw.ErrSecretReferenced),
+		errors.Is(err, auth.ErrDuplicateToken):
+		return exitConflict
+	case errors.Is(err, vault.ErrLocked):
+		return exitAuth
+	default:
+		return exitInternal
+	}
+}
+
+type errorResult struct {
+	Error struct {
+		Code     string `json:"code"`
+		ExitCode int    `json:"exit_code"`
+		Message  string `json:"message"`
+	} `json:"error"`
+}
+
+// reportError prints err to stderr in the --output format and returns the
+// exit code it stands for
+func reportError(err error) int {
+	code := exitCode(err)
+	if outputFormat == outputText {
+		fmt.Fprintln(os.Stderr, "Error:", err)
+		return code
+	}
+
+	var result errorResult
+	result.Error.Code = errorCodes[code]
+	result.Error.ExitCode = code
+	result.Error.Message = err.Error()
+	if writeErr := writeOutput(os.Stderr, result); writeErr != nil {
+		fmt.Fprintln(os.Stderr, "Error:", err)
+	}
+	return code
+}
+
+// fail reports err and exits with its code
+func fail(err error) {
+	os.Exit(reportError(err))
+}
+
+func failf(format string, a ...any) {
+	fail(fmt.Errorf(format, a...))
+}
+
+// printResult writes result to stdout as JSON or YAML, or runs text for
+// --output text
+func printResult(result any, text func()) {
+	if outputFormat == outputText {
+		text()
+		return
+	}
+	if err := writeOutput(os.Stdout, result); err != nil {
+		failf("failed to write output: %w", err)
+	}
+}
+
+// writeOutput encodes v in the --output format. YAML is converted from the
+// JSON encoding, so both use the same field names and order.
+func writeOutput(w io.Writer, v any) error {
+	var data bytes.Buffer
+	enc := json.NewEncoder(&data)
+	enc.SetEscapeHTML(false)
+	if outputFormat == outputJSON {
+		enc.SetIndent("", "  ")
+	}
+	if err := enc.Encode(v); err != nil {
+		return err
+	}
+
+	if outputFormat == outputJSON {
+		_, err := w.Write(data.Bytes())
+		return err
+	}
+
+	var node yaml.Node
+	if err := yaml.Unmarshal(data.Bytes(), &node); err != nil {
+		return err
+	}
+	blockStyle(&node)
+
+	yamlEnc := yaml.NewEncoder(w)
+	yamlEnc.SetIndent(2)
+	if err := yamlEnc.Encode(&node); err != nil {
+		return err
+	}
+	return yamlEnc.Close()
+}
+
+// blockStyle drops the flow style and quotes the nodes keep from JSON, the
+// encoder quotes strings again where YAML needs it
+func blockStyle(node *yaml.Node) {
+	node.Style = 0
+	for _, child := range node.Content {
+		blockStyle(child)
+	}
+}
+
+func checkOutputFormat() error {
+	switch outputFormat {
+	case outputText, outputJSON, outputYAML:
+		return nil
+	}
+	return usageError("invalid --output %q (expected text, json or yaml)", outputFormat)
+}
@@ -0,0 +1,210 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"text/tabwriter"
+
+	"github.com/Knightshrestha/Secret-Injector/audit"
+	"github.com/Knightshrestha/Secret-Injector/core/db_rw"
+	"github.com/Knightshrestha/Secret-Injector/database"
+	"github.com/Knightshrestha/Secret-Injector/database/generated"
+	"github.com/spf13/cobra"
+)
+
+var projectDescription string
+var projectDeleteYes bool
+
+// projectCmd represents the project command
+var projectCmd = &cobra.Command{
+	Use:   "project",
+	Short: "Create, rename and delete projects",
+	Long: `Manage projects from the command line. Names are normalised the same way as
+in the UI (e.g. "my api" becomes MY_API). A running "serve" picks the changes
+up and pushes them to the UI.`,
+}
+
+var projectListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List projects",
+	Args:  cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		mainDb := openWriteDatabase()
+		defer database.CloseWriteDatabase(mainDb.DB)
+
+		ctx := context.Background()
+
+		projects, err := mainDb.Queries.GetAllProjects(ctx)
+		if err != nil {
+			failf("failed to fetch projects: %w", err)
+		}
+		secrets, err := mainDb.Queries.GetAllSecrets(ctx)
+		if err != nil {
+			failf("failed to fetch secrets: %w", err)
+		}
+
+		counts := make(map[string]int)
+		for _, secret := range secrets {
+			counts[secret.ProjectID]++
+		}
+
+		result := []projectResult{}
+		for _, project := range projects {
+			result = append(result, projectResult{ProjectList: project, Secrets: counts[project.ID]})
+		}
+
+		printResult(result, func() {
+			w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+			fmt.Fprintln(w, "NAME\tID\tSECRETS\tDESCRIPTION\tUPDATED")
+			for _, project := range projects {
+				fmt.Fprintf(w, "%s\t%s\t%d\t%s\t%s\n",
+					project.Name, project.ID, counts[project.ID],
+					displayDescription(project.Description), formatTime(project.UpdatedAt))
+			}
+			w.Flush()
+		})
+	},
+}
+
+var projectCreateCmd = &cobra.Command{
+	Use:     "create NAME",
+	Short:   "Create a project",
+	Example: `  secret_injector project create api --description "Backend API"`,
+	Args:    cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		mainDb := openWriteDatabase()
+		defer database.CloseWriteDatabase(mainDb.DB)
+
+		ctx := context.Background()
+
+		var description *string
+		if cmd.Flags().Changed("description") {
+			description = &projectDescription
+		}
+
+		project, err := db_rw.CreateProject(ctx, mainDb.DB, mainDb.Queries, args[0], description, audit.LocalOrigin())
+		if err != nil {
+			failf("failed to create project: %w", err)
+		}
+
+		printResult(project, func() {
+			fmt.Printf("✓ Project %s created (ID: %s)\n", project.Name, project.ID)
+		})
+	},
+}
+
+var projectRenameCmd = &cobra.Command{
+	Use:     "rename PROJECT NEW_NAME",
+	Short:   "Rename a project",
+	Example: `  secret_injector project rename api backend`,
+	Args:    cobra.ExactArgs(2),
+	Run: func(cmd *cobra.Command, args []string) {
+		mainDb := openWriteDatabase()
+		defer database.CloseWriteDatabase(mainDb.DB)
+
+		ctx := context.Background()
+
+		project := lookupProject(ctx, mainDb.Queries, args[0])
+		updated := updateProject(ctx, mainDb, generated.UpdateProjectParams{
+			ID:   project.ID,
+			Name: &args[1],
+		})
+
+		printResult(updated, func() {
+			fmt.Printf("✓ Project %s renamed to %s\n", project.Name, updated.Name)
+		})
+	},
+}
+
+var projectDescribeCmd = &cobra.Command{
+	Use:     "describe PROJECT DESCRIPTION",
+	Short:   "Set the description of a project",
+	Example: `  secret_injector project describe api "Backend API"`,
+	Args:    cobra.ExactArgs(2),
+	Run: func(cmd *cobra.Command, args []string) {
+		mainDb := openWriteDatabase()
+		defer database.CloseWriteDatabase(mainDb.DB)
+
+		ctx := context.Background()
+
+		project := lookupProject(ctx, mainDb.Queries, args[0])
+		updated := updateProject(ctx, mainDb, generated.UpdateProjectParams{
+			ID:          project.ID,
+			Description: &args[1],
+		})
+
+		printResult(updated, func() {
+			fmt.Printf("✓ Description of %s updated\n", updated.Name)
+		})
+	},
+}
+
+var projectDeleteCmd = &cobra.Command{
+	Use:   "delete PROJECT",
+	Short: "Delete a project and all of its secrets",
+	Long: `Delete a project with the secrets of every environment and their history.
+This cannot be undone, so --yes is required.`,
+	Example: `  secret_injector project delete api --yes`,
+	Args:    cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		if !projectDeleteYes {
+			fail(usageError("deleting a project deletes all of its secrets, pass --yes to continue"))
+		}
+
+		mainDb := openWriteDatabase()
+		defer database.CloseWriteDatabase(mainDb.DB)
+
+		ctx := context.Background()
+
+		project := lookupProject(ctx, mainDb.Queries, args[0])
+		if err := db_rw.DeleteProject(ctx, mainDb.DB, mainDb.Queries, project.ID, audit.LocalOrigin()); err != nil {
+			failf("failed to delete project: %w", err)
+		}
+
+		printResult(project, func() {
+			fmt.Printf("✓ Project %s deleted\n", project.Name)
+		})
+	},
+}
+
+// projectResult is a project with the number of secrets it holds in every
+// environment
+type projectResult struct {
+	generated.ProjectList
+	Secrets int `json:"secrets"`
+}
+
+func updateProject(ctx context.Context, mainDb database.DB_Struct, params generated.UpdateProjectParams) generated.ProjectList {
+	project, err := db_rw.UpdateProject(ctx, mainDb.DB, mainDb.Queries, params, audit.LocalOrigin())
+	if err != nil {
+		failf("failed to update project: %w", err)
+	}
+	return project
+}
+
+// lookupProject finds a project by name or ID
+func lookupProject(ctx context.Context, queries *generated.Queries, name string) generated.ProjectList {
+	projects, err := queries.GetAllProjects(ctx)
+	if err != nil {
+		failf("failed to fetch projects: %w", err)
+	}
+
+	matched, err := matchProjects(projects, []string{name})
+	if err != nil {
+		fail(err)
+	}
+	return matched[0]
+}
+
+func init() {
+	rootCmd.AddCommand(projectCmd)
+	projectCmd.AddCommand(projectListCmd)
+	projectCmd.AddCommand(projectCreateCmd)
+	projectCmd.AddCommand(projectRenameCmd)
+	projectCmd.AddCommand(projectDescribeCmd)
+	projectCmd.AddCommand(projectDeleteCmd)
+
+	projectCreateCmd.Flags().StringVarP(&projectDescription, "description", "d", "", "Description of the project")
+	projectDeleteCmd.Flags().BoolVar(&projectDeleteYes, "yes", false, "Confirm the deletion")
+}
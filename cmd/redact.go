@@ -0,0 +1,231 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"text/tabwriter"
+
+	"github.com/Knightshrestha/Secret-Injector/audit"
+	"github.com/Knightshrestha/Secret-Injector/core/db_ro"
+	"github.com/Knightshrestha/Secret-Injector/database/generated"
+	"github.com/Knightshrestha/Secret-Injector/redact"
+	"github.com/Knightshrestha/Secret-Injector/utils"
+	"github.com/spf13/cobra"
+)
+
+var redactProjects []string
+var redactEnv string
+var redactRaw bool
+var redactMinLength int
+var redactInPlace bool
+var redactFail bool
+
+// redactCmd represents the redact command
+var redactCmd = &cobra.Command{
+	Use:   "redact [flags] [file...]",
+	Short: "Remove secret values from logs and other text",
+	Long: `Copy the files, or stdin when none are given, to stdout with every value of
+the selected projects replaced by ***KEY***. Values are also found base64 or
+URL encoded. --in-place rewrites the files instead.
+
+Values shorter than --min-length are left alone, short values such as "1" or
+"true" would mostly hide unrelated text. ${KEY} references are resolved
+first unless --raw is given, so composed values are found too.
+
+A report of what was replaced goes to stderr, in the --output format. With
+--fail the command exits with code 6 when any value was found, to stop a CI
+job from publishing a log that leaks a secret.`,
+	Example: `  secret_injector redact --project API < build.log > clean.log
+  secret_injector redact -p API -p SHARED --in-place logs/*.txt
+  ./build.sh 2>&1 | secret_injector redact -p API --fail > build.log`,
+	Run: func(cmd *cobra.Command, args []string) {
+		environment, err := utils.NormalizeEnvironment(redactEnv)
+		if err != nil {
+			fail(usageError("%v", err))
+		}
+
+		if redactMinLength < 1 {
+			fail(usageError("--min-length must be at least 1"))
+		}
+
+		if redactInPlace && len(args) == 0 {
+			fail(usageError("--in-place needs files to rewrite"))
+		}
+
+		// stdin is the text to redact, there is no picking projects from it
+		if len(redactProjects) == 0 {
+			fail(usageError("--project is required"))
+		}
+
+		projects, err := lookupProjects(redactProjects)
+		if err != nil {
+			fail(err)
+		}
+
+		r, err := loadRedactor(projects, environment, redactRaw, redactMinLength, audit.ActionRedact)
+		if err != nil {
+			fail(err)
+		}
+
+		counts := make(map[string]int)
+		if len(args) == 0 {
+			args = []string{"-"}
+		}
+		for _, path := range args {
+			var found map[string]int
+			if redactInPlace {
+				found, err = redactFile(r, path)
+			} else {
+				found, err = redactStream(r, path, os.Stdout)
+			}
+			if err != nil {
+				fail(err)
+			}
+			for key, count := range found {
+				counts[key] += count
+			}
+		}
+
+		result := redactResult{
+			Projects:    projects,
+			Environment: environment,
+			Keys:        counts,
+		}
+		for _, count := range counts {
+			result.Count += count
+		}
+
+		// stdout carries the redacted text unless the files were rewritten
+		report := os.Stderr
+		if redactInPlace {
+			report = os.Stdout
+		}
+		if outputFormat == outputText {
+			printRedactReport(result)
+		} else if err := writeOutput(report, result); err != nil {
+			failf("failed to write output: %w", err)
+		}
+
+		if redactFail && result.Count > 0 {
+			fail(leakError("found %d secret values", result.Count))
+		}
+	},
+}
+
+type redactResult struct {
+	Projects    []generated.ProjectList `json:"projects"`
+	Environment string                  `json:"environment"`
+	Count       int                     `json:"count"`
+	Keys        map[string]int          `json:"keys"`
+}
+
+func init() {
+	rootCmd.AddCommand(redactCmd)
+
+	redactCmd.Flags().StringArrayVarP(&redactProjects, "project", "p", nil, "Project name or ID whose values to remove (repeatable)")
+	redactCmd.Flags().StringVarP(&redactEnv, "env", "e", utils.BaseEnvironment, "Environment to resolve, its values override the base ones")
+	redactCmd.Flags().BoolVar(&redactRaw, "raw", false, "Look for values as stored, without resolving ${KEY} references")
+	redactCmd.Flags().IntVar(&redactMinLength, "min-length", redact.DefaultMinLength, "Shortest value to remove")
+	redactCmd.Flags().BoolVarP(&redactInPlace, "in-place", "i", false, "Rewrite the files instead of writing to stdout")
+	redactCmd.Flags().BoolVar(&redactFail, "fail", false, "Exit with code 6 when any value was found")
+}
+
+// loadRedactor builds a redactor for the values of projects in environment,
+// recording action in the audit log for the secrets read
+func loadRedactor(projects []generated.ProjectList, environment string, raw bool, minLength int, action audit.Action) (*redact.Redactor, error) {
+	var projectIDs []string
+	for _, project := range projects {
+		projectIDs = append(projectIDs, project.ID)
+	}
+
+	secrets, err := db_ro.FetchSecrets(projectIDs, environment, action)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch secrets: %w", err)
+	}
+
+	values := utils.SecretsToMap(secrets)
+	if !raw {
+		if values, err = utils.Interpolate(values, utils.FileKeys(secrets)); err != nil {
+			return nil, fmt.Errorf("failed to resolve references: %w", err)
+		}
+	}
+	return redact.New(values, minLength), nil
+}
+
+// redactStream copies the file at path, or stdin for "-", to w redacted
+func redactStream(r *redact.Redactor, path string, w io.Writer) (map[string]int, error) {
+	in := os.Stdin
+	if path != "-" {
+		file, err := os.Open(path)
+		if err != nil {
+			return nil, err
+		}
+		defer file.Close()
+		in = file
+	}
+
+	out := r.Writer(w)
+	if _, err := io.Copy(out, in); err != nil {
+		return nil, fmt.Errorf("failed to redact %s: %w", path, err)
+	}
+	if err := out.Close(); err != nil {
+		return nil, fmt.Errorf("failed to redact %s: %w", path, err)
+	}
+	return out.Counts(), nil
+}
+
+// redactFile rewrites the file at path redacted. The result replaces the
+// file in one rename and keeps its permissions.
+func redactFile(r *redact.Redactor, path string) (map[string]int, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, err
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+".redact-*")
+	if err != nil {
+		return nil, fmt.Errorf("failed to rewrite %s: %w", path, err)
+	}
+	defer os.Remove(tmp.Name())
+
+	counts, err := redactStream(r, path, tmp)
+	if err != nil {
+		tmp.Close()
+		return nil, err
+	}
+	err = tmp.Chmod(info.Mode().Perm())
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to rewrite %s: %w", path, err)
+	}
+
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return nil, fmt.Errorf("failed to rewrite %s: %w", path, err)
+	}
+	return counts, nil
+}
+
+func printRedactReport(result redactResult) {
+	if result.Count == 0 {
+		fmt.Fprintln(os.Stderr, "✓ No secret values found")
+		return
+	}
+
+	keys := make([]string, 0, len(result.Keys))
+	for key := range result.Keys {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	fmt.Fprintf(os.Stderr, "Redacted %d secret values:\n", result.Count)
+	w := tabwriter.NewWriter(os.Stderr, 0, 0, 2, ' ', 0)
+	for _, key := range keys {
+		fmt.Fprintf(w, "  %s\t%d\n", key, result.Keys[key])
+	}
+	w.Flush()
+}
@@ -0,0 +1,169 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/Knightshrestha/Secret-Injector/database"
+	"github.com/Knightshrestha/Secret-Injector/vault"
+	"github.com/spf13/cobra"
+)
+
+var rekeyNewKeyFile string
+var rekeyGenerateKeyFile string
+var rekeyReencrypt bool
+
+// rekeyCmd represents the rekey command
+var rekeyCmd = &cobra.Command{
+	Use:   "rekey",
+	Short: "Rotate the master key of an encrypted database",
+	Long: `Wrap the data key under a new master passphrase or key file.
+
+The current master key is read as usual (--key-file, environment or prompt).
+The new one comes from --new-key-file, --generate-key-file,
+SECRET_INJECTOR_NEW_PASSPHRASE or a prompt. With --reencrypt a fresh data key
+is generated and every secret is re-encrypted with it, which cannot be done
+while "serve" is running.
+
+A backup of the database is written next to it before anything changes. The
+rotation runs in a single transaction and is only committed once every secret
+decrypts under the new key.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		if rekeyNewKeyFile != "" && rekeyGenerateKeyFile != "" {
+			fail(usageError("use either --new-key-file or --generate-key-file, not both"))
+		}
+
+		// A running server keeps the data key it unlocked, it must not be
+		// replaced under it
+		if rekeyReencrypt {
+			release, err := lockDatabase(cmd)
+			if err != nil {
+				fail(err)
+			}
+			defer release()
+		}
+
+		mainDb := openWriteDatabase()
+		defer database.CloseWriteDatabase(mainDb.DB)
+
+		ctx := context.Background()
+
+		current, err := vault.Unlock(ctx, mainDb.Queries)
+		if err != nil {
+			fail(err)
+		}
+		if current == nil {
+			fail(usageError("database is not encrypted, run `migrate-encrypt` first"))
+		}
+
+		var master *vault.MasterKey
+		var keyFile *vault.NewKeyFile
+		if rekeyGenerateKeyFile != "" {
+			keyFile, err = vault.GenerateKeyFile(rekeyGenerateKeyFile)
+			if err == nil {
+				master = keyFile.Key
+			}
+		} else {
+			master, err = vault.LoadNewMasterKey(rekeyNewKeyFile)
+		}
+		if err != nil {
+			failf("failed to load new master key: %w", err)
+		}
+
+		// Backup before touching anything
+		backupPath, err := database.BackupPath("pre-rekey")
+		if err != nil {
+			failDiscarding(keyFile, err)
+		}
+		if err := database.BackupTo(mainDb.DB, backupPath); err != nil {
+			failDiscarding(keyFile, err)
+		}
+		if outputFormat == outputText {
+			fmt.Println("✓ Backup written to", backupPath, "(opens with the old master key)")
+		}
+
+		count, err := rotateMasterKey(ctx, mainDb, current, master)
+		if err != nil {
+			failDiscarding(keyFile, err)
+		}
+
+		// The new key file only appears once the data is sealed under it
+		if keyFile != nil {
+			if err := keyFile.Keep(); err != nil {
+				failf("master key rotated, but %w", err)
+			}
+		}
+
+		result := rekeyResult{
+			Reencrypted: rekeyReencrypt,
+			Count:       count,
+			Backup:      backupPath,
+			KeyFile:     rekeyGenerateKeyFile,
+		}
+		printResult(result, func() {
+			if rekeyReencrypt {
+				fmt.Printf("✓ Master key rotated, %d secret value(s) re-encrypted\n", count)
+			} else {
+				fmt.Println("✓ Master key rotated")
+			}
+			if rekeyGenerateKeyFile != "" {
+				fmt.Printf("✓ New master key written to %s, keep it safe\n", rekeyGenerateKeyFile)
+			}
+		})
+	},
+}
+
+// rotateMasterKey wraps the data key, or with --reencrypt a new one, under
+// master and commits once every secret decrypts with master alone. It
+// returns the number of re-encrypted values.
+func rotateMasterKey(ctx context.Context, mainDb database.DB_Struct, current *vault.Cipher, master *vault.MasterKey) (int, error) {
+	txn, err := mainDb.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer txn.Rollback()
+	queriesTx := mainDb.Queries.WithTx(txn)
+
+	count := 0
+	if rekeyReencrypt {
+		_, count, err = current.Reencrypt(ctx, queriesTx, master)
+	} else {
+		err = current.Rewrap(ctx, queriesTx, master)
+	}
+	if err != nil {
+		return 0, fmt.Errorf("rotation failed, nothing was changed: %w", err)
+	}
+
+	// Unlock from the stored key with only the new master key
+	row, err := queriesTx.GetActiveEncryptionKey(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("failed to read new encryption key: %w", err)
+	}
+	verifier, err := vault.UnlockWith(row, master)
+	if err != nil {
+		return 0, fmt.Errorf("verification failed, nothing was changed: %w", err)
+	}
+	if err := verifier.VerifyRows(ctx, queriesTx); err != nil {
+		return 0, fmt.Errorf("verification failed, nothing was changed: %w", err)
+	}
+
+	if err := txn.Commit(); err != nil {
+		return 0, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return count, nil
+}
+
+type rekeyResult struct {
+	Reencrypted bool   `json:"reencrypted"`
+	Count       int    `json:"count"`
+	Backup      string `json:"backup"`
+	KeyFile     string `json:"key_file,omitempty"`
+}
+
+func init() {
+	rootCmd.AddCommand(rekeyCmd)
+
+	rekeyCmd.Flags().StringVar(&rekeyNewKeyFile, "new-key-file", "", "Use this key file as the new master key")
+	rekeyCmd.Flags().StringVar(&rekeyGenerateKeyFile, "generate-key-file", "", "Create a new random master key file at this path and use it")
+	rekeyCmd.Flags().BoolVar(&rekeyReencrypt, "reencrypt", false, "Also generate a new data key and re-encrypt every secret")
+}
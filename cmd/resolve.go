@@ -0,0 +1,74 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/Knightshrestha/Secret-Injector/core/db_ro"
+	"github.com/Knightshrestha/Secret-Injector/database/generated"
+	"github.com/Knightshrestha/Secret-Injector/utils"
+)
+
+// resolveProjects maps --project values (name or ID) to projects, keeping
+// the order they were given in. Without names it falls back to the
+// interactive selector.
+func resolveProjects(names []string) ([]generated.ProjectList, error) {
+	projects, err := db_ro.FetchProjects()
+	if err != nil {
+		return nil, fmt.Errorf("fetching projects: %w", err)
+	}
+
+	if len(projects) == 0 {
+		return nil, notFoundError("no projects available")
+	}
+
+	if len(names) == 0 {
+		// The selector needs a terminal and would garble the output
+		if outputFormat != outputText {
+			return nil, usageError("--project is required with --output %s", outputFormat)
+		}
+		return selectProjects(projects)
+	}
+
+	return matchProjects(projects, names)
+}
+
+// lookupProjects is resolveProjects without the interactive fallback
+func lookupProjects(names []string) ([]generated.ProjectList, error) {
+	if len(names) == 0 {
+		return nil, nil
+	}
+
+	projects, err := db_ro.FetchProjects()
+	if err != nil {
+		return nil, fmt.Errorf("fetching projects: %w", err)
+	}
+
+	return matchProjects(projects, names)
+}
+
+func matchProjects(projects []generated.ProjectList, names []string) ([]generated.ProjectList, error) {
+	var selected []generated.ProjectList
+	var missing []string
+
+	for _, name := range names {
+		normalized := utils.ToScreamingSnakeCase(name)
+		found := false
+		for _, project := range projects {
+			if project.ID == name || project.Name == normalized {
+				selected = append(selected, project)
+				found = true
+				break
+			}
+		}
+		if !found {
+			missing = append(missing, name)
+		}
+	}
+
+	if len(missing) > 0 {
+		return nil, notFoundError("unknown project(s): %s", strings.Join(missing, ", "))
+	}
+
+	return selected, nil
+}
@@ -0,0 +1,149 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/Knightshrestha/Secret-Injector/database"
+	"github.com/Knightshrestha/Secret-Injector/vault"
+	"github.com/spf13/cobra"
+)
+
+var restoreYes bool
+
+// restoreCmd represents the restore command
+var restoreCmd = &cobra.Command{
+	Use:   "restore FILE",
+	Short: "Replace the database with a backup",
+	Long: `Replace the database with a backup written by "backup" or by the schedule
+of "serve". It is refused while "serve" is running.
+
+The backup is decrypted if needed and must pass an integrity check and have
+a schema version this binary understands before anything changes. The
+current database is backed up next to it, then swapped for the restored one
+in a single rename and migrated to the latest schema. Secret values open
+with the master key that was in use when the backup was taken.`,
+	Example: `  secret_injector restore ~/backups/secrets.db --yes
+  SECRET_INJECTOR_BACKUP_PASSPHRASE=... secret_injector restore secrets.db.enc --yes`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		if !restoreYes {
+			fail(usageError("restoring replaces every project, secret and token, pass --yes to continue"))
+		}
+
+		// serve keeps the database open and would go on writing to the file
+		// that is swapped out
+		release, err := lockDatabase(cmd)
+		if err != nil {
+			fail(err)
+		}
+		defer release()
+
+		data, err := os.ReadFile(args[0])
+		if err != nil {
+			if os.IsNotExist(err) {
+				fail(notFoundError("backup %s not found", args[0]))
+			}
+			failf("failed to read backup: %w", err)
+		}
+
+		if vault.IsSealedBackup(data) {
+			passphrase, err := vault.LoadBackupPassphrase(false)
+			if err != nil {
+				fail(usageError("%v", err))
+			}
+			if data, err = vault.OpenBackup(data, passphrase); err != nil {
+				fail(&cliError{code: exitAuth, err: err})
+			}
+		}
+
+		stagedPath, version, err := stageBackup(data)
+		if err != nil {
+			fail(usageError("backup %s cannot be restored: %v", args[0], err))
+		}
+		defer os.Remove(stagedPath) // No-op once renamed
+
+		// Keep what is being replaced
+		backupPath, err := database.BackupCurrent("pre-restore")
+		if err != nil {
+			os.Remove(stagedPath)
+			failf("failed to back up the current database: %w", err)
+		}
+		if backupPath != "" && outputFormat == outputText {
+			fmt.Println("✓ Current database backed up to", backupPath)
+		}
+
+		if err := database.ReplaceDatabase(stagedPath); err != nil {
+			os.Remove(stagedPath)
+			fail(err)
+		}
+
+		if err := database.SetupDatabase(); err != nil {
+			failf("restored, but failed to migrate the database: %w", err)
+		}
+
+		result := restoreResult{
+			Path:           args[0],
+			SchemaVersion:  version,
+			Version:        database.LatestSchemaVersion(),
+			PreviousBackup: backupPath,
+		}
+		printResult(result, func() {
+			if version == 0 {
+				fmt.Printf("✓ Restored %s (schema from before versioning, now %d)\n", args[0], result.Version)
+				return
+			}
+			fmt.Printf("✓ Restored %s (schema version %d, now %d)\n", args[0], version, result.Version)
+		})
+	},
+}
+
+type restoreResult struct {
+	Path string `json:"path"`
+
+	// SchemaVersion is the version of the backup, 0 from before versioning
+	SchemaVersion int `json:"schema_version"`
+	Version       int `json:"version"`
+
+	// PreviousBackup is the copy of the replaced database, empty when there
+	// was none
+	PreviousBackup string `json:"previous_backup,omitempty"`
+}
+
+// stageBackup writes the backup next to the database, so the swap is a
+// rename, and validates it there. It returns the staged path and the schema
+// version of the backup.
+func stageBackup(data []byte) (string, int, error) {
+	dataDir, err := database.DataDir()
+	if err != nil {
+		return "", 0, fmt.Errorf("failed to get data directory: %w", err)
+	}
+
+	staged, err := os.CreateTemp(dataDir, ".restore-*.db")
+	if err != nil {
+		return "", 0, fmt.Errorf("failed to create temporary file: %w", err)
+	}
+	stagedPath := staged.Name()
+
+	_, err = staged.Write(data)
+	if closeErr := staged.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		os.Remove(stagedPath)
+		return "", 0, fmt.Errorf("failed to stage backup: %w", err)
+	}
+
+	version, err := database.ValidateBackup(stagedPath)
+	if err != nil {
+		os.Remove(stagedPath)
+		return "", 0, err
+	}
+	return stagedPath, version, nil
+}
+
+func init() {
+	rootCmd.AddCommand(restoreCmd)
+
+	restoreCmd.Flags().BoolVar(&restoreYes, "yes", false, "Confirm replacing the current database")
+}
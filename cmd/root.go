@@ -3,11 +3,12 @@ package cmd
 import (
 	"os"
 
+	"github.com/Knightshrestha/Secret-Injector/config"
+	"github.com/Knightshrestha/Secret-Injector/database"
+	"github.com/Knightshrestha/Secret-Injector/vault"
 	"github.com/spf13/cobra"
 )
 
-
-
 // rootCmd represents the base command when called without any subcommands
 var rootCmd = &cobra.Command{
 	Use:   "Secret-Injector",
@@ -15,15 +16,63 @@ var rootCmd = &cobra.Command{
 	// Uncomment the following line if your bare application
 	// has an action associated with it:
 	// Run: func(cmd *cobra.Command, args []string) { },
+	PersistentPreRun: func(cmd *cobra.Command, args []string) {
+		if err := checkOutputFormat(); err != nil {
+			outputFormat = outputText
+			fail(err)
+		}
+
+		file, err := config.Load()
+		if err != nil {
+			fail(&cliError{code: exitUsage, err: err})
+		}
+		if err := applySettings(cmd, file); err != nil {
+			fail(&cliError{code: exitUsage, err: err})
+		}
+
+		useVaultKeyFile()
+	},
+}
+
+// useVaultKeyFile falls back to the key file the config sets for the active
+// vault when neither --key-file nor a key in the environment is given.
+// Errors are left for the command to report when it opens the database.
+func useVaultKeyFile() {
+	if vault.KeyFile != "" || os.Getenv(vault.EnvKeyFile) != "" || os.Getenv(vault.EnvKey) != "" {
+		return
+	}
+	if loc, err := database.Locate(); err == nil {
+		vault.KeyFile = loc.KeyFile
+	}
 }
 
 // Execute adds all child commands to the root command and sets flags appropriately.
 // This is called by main.main(). It only needs to happen once to the rootCmd.
 func Execute() {
 	rootCmd.CompletionOptions.DisableDefaultCmd = true
-	err := rootCmd.Execute()
+
+	// Argument and flag errors are reported like every other error, the
+	// commands report their own
+	rootCmd.SilenceErrors = true
+	rootCmd.SilenceUsage = true
+	cmd, err := rootCmd.ExecuteC()
 	if err != nil {
-		os.Exit(1)
+		if checkOutputFormat() != nil {
+			outputFormat = outputText
+		}
+		code := reportError(&cliError{code: exitUsage, err: err})
+		if outputFormat == outputText {
+			cmd.PrintErrln(cmd.UsageString())
+		}
+		os.Exit(code)
 	}
 }
 
+func init() {
+	rootCmd.PersistentFlags().StringVar(&outputFormat, "output", outputText, "Output format: text, json or yaml")
+	rootCmd.PersistentFlags().StringVar(&config.ConfigPath, "config", "", "Config file to use (or set "+config.EnvConfig+")")
+	rootCmd.PersistentFlags().StringVar(&vault.KeyFile, "key-file", "", "Master key file for an encrypted database (or set "+vault.EnvKeyFile+")")
+	rootCmd.PersistentFlags().StringVar(&database.DBFlag, "db", "", "Database file to use (or set "+database.EnvDB+")")
+	rootCmd.PersistentFlags().StringVar(&database.VaultFlag, "vault", "", "Named vault to use (or set "+database.EnvVault+")")
+}
+
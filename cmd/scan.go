@@ -0,0 +1,211 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"text/tabwriter"
+
+	"github.com/Knightshrestha/Secret-Injector/audit"
+	"github.com/Knightshrestha/Secret-Injector/core/db_ro"
+	"github.com/Knightshrestha/Secret-Injector/database/generated"
+	"github.com/Knightshrestha/Secret-Injector/redact"
+	"github.com/Knightshrestha/Secret-Injector/scanner"
+	"github.com/Knightshrestha/Secret-Injector/utils"
+	"github.com/spf13/cobra"
+)
+
+var scanProjects []string
+var scanEnv string
+var scanRaw bool
+var scanMinLength int
+var scanHistory bool
+var scanStaged bool
+var scanInstallHook bool
+var scanForce bool
+
+// scanCmd represents the scan command
+var scanCmd = &cobra.Command{
+	Use:   "scan [flags] [path]",
+	Short: "Find secret values leaked into a repository",
+	Long: `Look for the values of the selected projects, all projects by default, in the
+files under path (default the current directory) and report the file, line
+and column of each. Values are also found base64 or URL encoded. In a git
+repository only the files git tracks or would track are read, so .gitignore
+is honoured, and --history scans the lines added by every commit as well.
+--staged scans only what the next commit would add.
+
+--install-hook writes a git pre-commit hook running scan --staged with the
+same projects, so a commit adding a secret value is refused.
+
+The command exits with code 6 when any value was found.`,
+	Example: `  secret_injector scan
+  secret_injector scan --project API --history ~/src/api
+  secret_injector scan -p API -p SHARED --install-hook`,
+	Args: cobra.MaximumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		root := "."
+		if len(args) == 1 {
+			root = args[0]
+		}
+
+		environment, err := utils.NormalizeEnvironment(scanEnv)
+		if err != nil {
+			fail(usageError("%v", err))
+		}
+
+		if scanMinLength < 1 {
+			fail(usageError("--min-length must be at least 1"))
+		}
+
+		if scanStaged && scanHistory {
+			fail(usageError("--staged and --history cannot be combined"))
+		}
+
+		projects, err := lookupProjects(scanProjects)
+		if err != nil {
+			fail(err)
+		}
+
+		if scanInstallHook {
+			installScanHook(cmd, root)
+			return
+		}
+
+		if len(projects) == 0 {
+			if projects, err = db_ro.FetchProjects(); err != nil {
+				failf("fetching projects: %w", err)
+			}
+		}
+
+		r, err := loadRedactor(projects, environment, scanRaw, scanMinLength, audit.ActionScan)
+		if err != nil {
+			fail(err)
+		}
+
+		var findings []scanner.Finding
+		if scanStaged {
+			findings, err = scanner.ScanStaged(r, root)
+		} else {
+			findings, err = scanner.ScanTree(r, root)
+			if err == nil && scanHistory {
+				var history []scanner.Finding
+				history, err = scanner.ScanHistory(r, root)
+				findings = append(findings, history...)
+			}
+		}
+		if errors.Is(err, scanner.ErrNotRepository) {
+			fail(usageError("%s is %v", root, err))
+		}
+		if err != nil {
+			failf("failed to scan %s: %w", root, err)
+		}
+
+		if findings == nil {
+			findings = []scanner.Finding{}
+		}
+		result := scanResult{
+			Projects:    projects,
+			Environment: environment,
+			Count:       len(findings),
+			Findings:    findings,
+		}
+
+		printResult(result, func() {
+			if len(findings) == 0 {
+				fmt.Println("✓ No secret values found")
+				return
+			}
+
+			w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+			fmt.Fprintln(w, "LOCATION\tKEY\tFORM\tCOMMIT")
+			for _, finding := range findings {
+				commit := "-"
+				if finding.Commit != "" {
+					commit = finding.Commit[:min(len(finding.Commit), 12)]
+				}
+				fmt.Fprintf(w, "%s:%d:%d\t%s\t%s\t%s\n",
+					finding.Path, finding.Line, finding.Column, finding.Key, finding.Form, commit)
+			}
+			w.Flush()
+		})
+
+		if len(findings) > 0 {
+			fail(leakError("found %d secret values", len(findings)))
+		}
+	},
+}
+
+type scanResult struct {
+	Projects    []generated.ProjectList `json:"projects"`
+	Environment string                  `json:"environment"`
+	Count       int                     `json:"count"`
+	Findings    []scanner.Finding       `json:"findings"`
+}
+
+type hookResult struct {
+	Path    string   `json:"path"`
+	Command []string `json:"command"`
+}
+
+func init() {
+	rootCmd.AddCommand(scanCmd)
+
+	scanCmd.Flags().StringArrayVarP(&scanProjects, "project", "p", nil, "Project name or ID whose values to look for (repeatable, default all)")
+	scanCmd.Flags().StringVarP(&scanEnv, "env", "e", utils.BaseEnvironment, "Environment to resolve, its values override the base ones")
+	scanCmd.Flags().BoolVar(&scanRaw, "raw", false, "Look for values as stored, without resolving ${KEY} references")
+	scanCmd.Flags().IntVar(&scanMinLength, "min-length", redact.DefaultMinLength, "Shortest value to look for")
+	scanCmd.Flags().BoolVar(&scanHistory, "history", false, "Also scan every commit of the git history")
+	scanCmd.Flags().BoolVar(&scanStaged, "staged", false, "Scan only the changes staged for the next commit")
+	scanCmd.Flags().BoolVar(&scanInstallHook, "install-hook", false, "Write a git pre-commit hook scanning staged changes")
+	scanCmd.Flags().BoolVar(&scanForce, "force", false, "Replace a pre-commit hook not written by --install-hook")
+}
+
+// installScanHook writes the pre-commit hook for the repository at root.
+// The hook runs this binary with the projects, environment and database
+// given now; git runs hooks from the top of the repository, so paths are
+// made absolute.
+func installScanHook(cmd *cobra.Command, root string) {
+	executable, err := os.Executable()
+	if err != nil {
+		failf("failed to locate secret_injector: %w", err)
+	}
+
+	command := []string{filepath.ToSlash(executable)}
+	for _, name := range []string{"config", "db", "vault", "key-file"} {
+		flag := cmd.Root().PersistentFlags().Lookup(name)
+		if !flag.Changed {
+			continue
+		}
+		value := flag.Value.String()
+		if name != "vault" {
+			if value, err = filepath.Abs(value); err != nil {
+				fail(err)
+			}
+		}
+		command = append(command, "--"+name, value)
+	}
+	command = append(command, "scan", "--staged", "--env", scanEnv, "--min-length", fmt.Sprint(scanMinLength))
+	if scanRaw {
+		command = append(command, "--raw")
+	}
+	for _, project := range scanProjects {
+		command = append(command, "--project", project)
+	}
+
+	path, err := scanner.InstallHook(root, command, scanForce)
+	if errors.Is(err, scanner.ErrHookExists) {
+		fail(conflictError("%v (replace it with --force)", err))
+	}
+	if errors.Is(err, scanner.ErrNotRepository) {
+		fail(usageError("%s is %v", root, err))
+	}
+	if err != nil {
+		fail(err)
+	}
+
+	printResult(hookResult{Path: path, Command: command}, func() {
+		fmt.Printf("✓ Installed pre-commit hook %s\n", path)
+	})
+}
@@ -0,0 +1,699 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/Knightshrestha/Secret-Injector/audit"
+	"github.com/Knightshrestha/Secret-Injector/core/db_rw"
+	"github.com/Knightshrestha/Secret-Injector/database"
+	"github.com/Knightshrestha/Secret-Injector/database/generated"
+	"github.com/Knightshrestha/Secret-Injector/utils"
+	"github.com/Knightshrestha/Secret-Injector/vault"
+	"github.com/spf13/cobra"
+	"golang.org/x/term"
+)
+
+var secretShowValues bool
+var secretEnv string
+var secretFromFile string
+var secretDescription string
+var secretForce bool
+var secretType string
+
+// secretCmd represents the secret command
+var secretCmd = &cobra.Command{
+	Use:   "secret",
+	Short: "Work with individual secrets",
+	Long: `List, read, set and remove secrets, inspect the history of a secret and roll
+it back to an earlier version.
+
+Keys are normalised the same way as in the UI (e.g. "db pass" becomes
+DB_PASS) and --env picks the environment. A running "serve" picks changes up
+and pushes them to the UI.`,
+}
+
+var secretListCmd = &cobra.Command{
+	Use:   "list PROJECT",
+	Short: "List the secrets of a project",
+	Long: `List the effective secrets of a project in the environment given with --env,
+including those inherited from base. Values are hidden unless --show-values
+is given.`,
+	Example: `  secret_injector secret list api
+  secret_injector secret list api --env prod --show-values`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		mainDb := openReadDatabase()
+		defer database.CloseReadDatabase(mainDb.DB)
+
+		ctx := context.Background()
+
+		environment := secretEnvironment()
+		project := lookupProject(ctx, mainDb.Queries, args[0])
+
+		stored, err := mainDb.Queries.GetSecretsByProjectID(ctx, project.ID)
+		if err != nil {
+			failf("failed to fetch secrets: %w", err)
+		}
+		secrets := utils.ResolveEnvironment(stored, environment)
+
+		if secretShowValues {
+			cipher, err := vault.Unlock(ctx, mainDb.Queries)
+			if err != nil {
+				fail(err)
+			}
+			if secrets, err = cipher.OpenSecrets(secrets); err != nil {
+				failf("failed to decrypt secrets: %w", err)
+			}
+			if err := audit.RecordLocal(ctx, audit.ActionRead, secrets); err != nil {
+				failf("failed to record audit entry: %w", err)
+			}
+		}
+
+		result := []secretResult{}
+		for _, secret := range secrets {
+			result = append(result, newSecretResult(secret, secretShowValues))
+		}
+
+		printResult(result, func() {
+			w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+			fmt.Fprintln(w, "KEY\tVALUE\tTYPE\tENVIRONMENT\tDESCRIPTION\tUPDATED")
+			for _, secret := range secrets {
+				fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\n",
+					secret.Key, displayValue(secret.Value), secret.Type, secret.Environment,
+					displayDescription(secret.Description), formatTime(secret.UpdatedAt))
+			}
+			w.Flush()
+		})
+	},
+}
+
+var secretGetCmd = &cobra.Command{
+	Use:   "get PROJECT KEY",
+	Short: "Print the value of a secret",
+	Long: `Print the value a secret has in the environment given with --env, falling
+back to base when the environment does not override it.`,
+	Example: `  secret_injector secret get api DATABASE_URL
+  secret_injector secret get api DATABASE_URL --env prod`,
+	Args: cobra.ExactArgs(2),
+	Run: func(cmd *cobra.Command, args []string) {
+		mainDb := openReadDatabase()
+		defer database.CloseReadDatabase(mainDb.DB)
+
+		ctx := context.Background()
+
+		cipher, err := vault.Unlock(ctx, mainDb.Queries)
+		if err != nil {
+			fail(err)
+		}
+
+		environment := secretEnvironment()
+		project := lookupProject(ctx, mainDb.Queries, args[0])
+
+		secret, found := findSecret(ctx, mainDb.Queries, project, args[1], environment)
+		if !found && environment != utils.BaseEnvironment {
+			secret, found = findSecret(ctx, mainDb.Queries, project, args[1], utils.BaseEnvironment)
+		}
+		if !found {
+			fail(secretNotFound(args[1], project, environment))
+		}
+
+		if secret, err = cipher.OpenSecret(secret); err != nil {
+			failf("failed to decrypt secret: %w", err)
+		}
+		if err := audit.RecordLocal(ctx, audit.ActionRead, []generated.SecretList{secret}); err != nil {
+			failf("failed to record audit entry: %w", err)
+		}
+
+		printResult(newSecretResult(secret, true), func() {
+			fmt.Println(secret.Value)
+		})
+	},
+}
+
+var secretSetCmd = &cobra.Command{
+	Use:   "set PROJECT KEY",
+	Short: "Create a secret or change its value",
+	Long: `Create a secret in the environment given with --env, or replace its value
+when it already exists there (the old value is kept as a version).
+
+The value is never taken from the command line, so it stays out of the shell
+history: it is read from --from-file, or from stdin when it is piped (one
+trailing newline is dropped), otherwise it is prompted for without echo.
+--from-file - reads stdin too.
+
+--type file makes inject pass the value as a temporary file, see inject.`,
+	Example: `  secret_injector secret set api DATABASE_URL
+  printf %s "$TOKEN" | secret_injector secret set api API_TOKEN --env prod
+  secret_injector secret set api TLS_CERT --from-file cert.pem --type file -d "Server certificate"`,
+	Args: cobra.ExactArgs(2),
+	Run: func(cmd *cobra.Command, args []string) {
+		value, err := readSecretValue(utils.ToScreamingSnakeCase(args[1]))
+		if err != nil {
+			fail(err)
+		}
+
+		mainDb := openWriteDatabase()
+		defer database.CloseWriteDatabase(mainDb.DB)
+
+		ctx := context.Background()
+
+		cipher, err := vault.Unlock(ctx, mainDb.Queries)
+		if err != nil {
+			fail(err)
+		}
+
+		environment := secretEnvironment()
+		project := lookupProject(ctx, mainDb.Queries, args[0])
+
+		var description *string
+		if cmd.Flags().Changed("description") {
+			description = &secretDescription
+		}
+
+		// A new secret is text unless told otherwise, an existing one keeps
+		// its type
+		var newType *string
+		if cmd.Flags().Changed("type") {
+			newType = &secretType
+		}
+
+		existing, found := findSecret(ctx, mainDb.Queries, project, args[1], environment)
+		if !found {
+			secret, err := db_rw.CreateSecret(ctx, mainDb.DB, mainDb.Queries, cipher, generated.CreateSecretParams{
+				ProjectID:   project.ID,
+				Environment: environment,
+				Key:         args[1],
+				Value:       value,
+				Description: description,
+				Type:        secretType,
+			}, audit.LocalOrigin())
+			if err != nil {
+				failf("failed to create secret: %w", err)
+			}
+
+			printResult(secretSetResult{newSecretResult(secret, false), true}, func() {
+				fmt.Printf("✓ %s created in %s (environment %s)\n", secret.Key, project.Name, environment)
+			})
+			return
+		}
+
+		secret := updateSecret(ctx, mainDb, cipher, generated.UpdateSecretParams{
+			ID:          existing.ID,
+			Value:       &value,
+			Description: description,
+			Type:        newType,
+		})
+		printResult(secretSetResult{newSecretResult(secret, false), false}, func() {
+			fmt.Printf("✓ %s updated in %s (environment %s)\n", secret.Key, project.Name, environment)
+		})
+	},
+}
+
+var secretUnsetCmd = &cobra.Command{
+	Use:   "unset PROJECT KEY",
+	Short: "Delete a secret",
+	Long: `Delete a secret stored in the environment given with --env. Its history
+is kept and "secret restore" brings it back. Removing an override makes the
+base value apply again. A secret
+other secrets reference with ref:// is only deleted with --force, which
+leaves those references broken.`,
+	Example: `  secret_injector secret unset api DATABASE_URL
+  secret_injector secret unset api DATABASE_URL --env prod
+  secret_injector secret unset shared_infra SENTRY_DSN --force`,
+	Args: cobra.ExactArgs(2),
+	Run: func(cmd *cobra.Command, args []string) {
+		mainDb := openWriteDatabase()
+		defer database.CloseWriteDatabase(mainDb.DB)
+
+		ctx := context.Background()
+
+		cipher, err := vault.Unlock(ctx, mainDb.Queries)
+		if err != nil {
+			fail(err)
+		}
+
+		secret := lookupSecret(ctx, mainDb.Queries, args[0], args[1])
+		if err := db_rw.DeleteSecret(ctx, mainDb.DB, mainDb.Queries, cipher, secret.ID, secretForce, audit.LocalOrigin()); err != nil {
+			var referenced *db_rw.SecretReferencedError
+			if errors.As(err, &referenced) {
+				fail(conflictError("%s is referenced by %s, use --force to delete it anyway",
+					secret.Key, describeSecrets(ctx, mainDb.Queries, referenced.Dependents)))
+			}
+			failf("failed to delete secret: %w", err)
+		}
+
+		printResult(newSecretResult(secret, false), func() {
+			fmt.Printf("✓ %s deleted (environment %s)\n", secret.Key, secret.Environment)
+		})
+	},
+}
+
+var secretDescribeCmd = &cobra.Command{
+	Use:     "describe PROJECT KEY DESCRIPTION",
+	Short:   "Set the description of a secret",
+	Example: `  secret_injector secret describe api DATABASE_URL "Primary Postgres"`,
+	Args:    cobra.ExactArgs(3),
+	Run: func(cmd *cobra.Command, args []string) {
+		mainDb := openWriteDatabase()
+		defer database.CloseWriteDatabase(mainDb.DB)
+
+		ctx := context.Background()
+
+		cipher, err := vault.Unlock(ctx, mainDb.Queries)
+		if err != nil {
+			fail(err)
+		}
+
+		existing := lookupSecret(ctx, mainDb.Queries, args[0], args[1])
+		secret := updateSecret(ctx, mainDb, cipher, generated.UpdateSecretParams{
+			ID:          existing.ID,
+			Description: &args[2],
+		})
+		printResult(newSecretResult(secret, false), func() {
+			fmt.Printf("✓ Description of %s updated\n", secret.Key)
+		})
+	},
+}
+
+var secretHistoryCmd = &cobra.Command{
+	Use:   "history PROJECT KEY",
+	Short: "Show the previous versions of a secret",
+	Long: `Show the previous versions of a secret, newest first.
+
+Every change made through the UI, the API or the secret commands keeps the
+replaced key, value and description as a version. Values are hidden unless
+--show-values is given.`,
+	Example: `  secret_injector secret history api DATABASE_URL
+  secret_injector secret history api DATABASE_URL --show-values`,
+	Args: cobra.ExactArgs(2),
+	Run: func(cmd *cobra.Command, args []string) {
+		mainDb := openReadDatabase()
+		defer database.CloseReadDatabase(mainDb.DB)
+
+		ctx := context.Background()
+
+		cipher, err := vault.Unlock(ctx, mainDb.Queries)
+		if err != nil {
+			fail(err)
+		}
+
+		secret := lookupSecret(ctx, mainDb.Queries, args[0], args[1])
+
+		versions, err := mainDb.Queries.GetSecretVersions(ctx, secret.ID)
+		if err != nil {
+			failf("failed to fetch secret versions: %w", err)
+		}
+
+		if secretShowValues {
+			if secret, err = cipher.OpenSecret(secret); err != nil {
+				failf("failed to decrypt secret: %w", err)
+			}
+			if versions, err = cipher.OpenVersions(versions); err != nil {
+				failf("failed to decrypt secret versions: %w", err)
+			}
+			if err := audit.RecordLocal(ctx, audit.ActionHistory, []generated.SecretList{secret}); err != nil {
+				failf("failed to record audit entry: %w", err)
+			}
+		}
+
+		result := secretHistoryResult{
+			Secret:   newSecretResult(secret, secretShowValues),
+			Versions: []secretVersionResult{},
+		}
+		for _, version := range versions {
+			result.Versions = append(result.Versions, newSecretVersionResult(version, secretShowValues))
+		}
+
+		printResult(result, func() {
+			w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+			fmt.Fprintln(w, "VERSION\tKEY\tVALUE\tTYPE\tDESCRIPTION\tREPLACED\tREPLACED BY")
+			fmt.Fprintf(w, "current\t%s\t%s\t%s\t%s\t%s\t%s\n",
+				secret.Key, displayValue(secret.Value), secret.Type, displayDescription(secret.Description), "-", "-")
+			for _, version := range versions {
+				fmt.Fprintf(w, "%d\t%s\t%s\t%s\t%s\t%s\t%s\n",
+					version.Version, version.Key, displayValue(version.Value), version.Type,
+					displayDescription(version.Description), formatTime(version.CreatedAt), version.Actor)
+			}
+			w.Flush()
+		})
+	},
+}
+
+var secretRollbackCmd = &cobra.Command{
+	Use:   "rollback PROJECT KEY VERSION",
+	Short: "Restore a secret to an earlier version",
+	Long: `Restore the key, value, description and type a secret had at VERSION
+(see "secret history"). The state being replaced is kept as a new version, so
+a rollback can be undone the same way.`,
+	Example: `  secret_injector secret rollback api DATABASE_URL 3`,
+	Args:    cobra.ExactArgs(3),
+	Run: func(cmd *cobra.Command, args []string) {
+		version, err := strconv.ParseInt(args[2], 10, 64)
+		if err != nil || version < 1 {
+			fail(usageError("version must be a positive number, got %q", args[2]))
+		}
+
+		mainDb := openWriteDatabase()
+		defer database.CloseWriteDatabase(mainDb.DB)
+
+		ctx := context.Background()
+
+		cipher, err := vault.Unlock(ctx, mainDb.Queries)
+		if err != nil {
+			fail(err)
+		}
+
+		secret := lookupSecret(ctx, mainDb.Queries, args[0], args[1])
+
+		restored, err := db_rw.RollbackSecret(ctx, mainDb.DB, mainDb.Queries, cipher, secret.ID, version, audit.LocalOrigin())
+		if err != nil {
+			if errors.Is(err, db_rw.ErrDuplicateKey) {
+				fail(conflictError("another secret in the environment already uses the key of this version"))
+			}
+			failf("failed to roll back secret: %w", err)
+		}
+
+		printResult(secretRollbackResult{newSecretResult(restored, false), version}, func() {
+			fmt.Printf("✓ %s rolled back to version %d\n", restored.Key, version)
+		})
+	},
+}
+
+var secretDeletedCmd = &cobra.Command{
+	Use:   "deleted PROJECT",
+	Short: "List the deleted secrets of a project",
+	Long: `List the secrets deleted from a project, most recent first, in every
+environment. "secret restore" brings them back with their history.`,
+	Example: `  secret_injector secret deleted api`,
+	Args:    cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		mainDb := openReadDatabase()
+		defer database.CloseReadDatabase(mainDb.DB)
+
+		ctx := context.Background()
+
+		project := lookupProject(ctx, mainDb.Queries, args[0])
+
+		deleted, err := mainDb.Queries.GetDeletedSecretsByProjectID(ctx, project.ID)
+		if err != nil {
+			failf("failed to fetch deleted secrets: %w", err)
+		}
+
+		result := []deletedSecretResult{}
+		for _, secret := range deleted {
+			result = append(result, deletedSecretResult(secret))
+		}
+
+		printResult(result, func() {
+			if len(deleted) == 0 {
+				fmt.Printf("No deleted secrets in %s\n", project.Name)
+				return
+			}
+			w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+			fmt.Fprintln(w, "KEY\tENVIRONMENT\tDELETED\tDELETED BY")
+			for _, secret := range deleted {
+				fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", secret.Key, secret.Environment, formatTime(secret.DeletedAt), secret.Actor)
+			}
+			w.Flush()
+		})
+	},
+}
+
+var secretRestoreCmd = &cobra.Command{
+	Use:   "restore PROJECT KEY",
+	Short: "Restore a deleted secret",
+	Long: `Restore the secret last deleted under KEY in the environment given with
+--env (see "secret deleted"), with the value it had and its history. KEY may
+also be the ID of the deleted secret. It fails when the key is in use again.`,
+	Example: `  secret_injector secret restore api DATABASE_URL
+  secret_injector secret restore api DATABASE_URL --env prod`,
+	Args: cobra.ExactArgs(2),
+	Run: func(cmd *cobra.Command, args []string) {
+		mainDb := openWriteDatabase()
+		defer database.CloseWriteDatabase(mainDb.DB)
+
+		ctx := context.Background()
+
+		cipher, err := vault.Unlock(ctx, mainDb.Queries)
+		if err != nil {
+			fail(err)
+		}
+
+		environment := secretEnvironment()
+		project := lookupProject(ctx, mainDb.Queries, args[0])
+
+		deleted, err := mainDb.Queries.GetDeletedSecretsByProjectID(ctx, project.ID)
+		if err != nil {
+			failf("failed to fetch deleted secrets: %w", err)
+		}
+
+		// Most recent first, a key deleted more than once comes back as it
+		// was last
+		normalized := utils.ToScreamingSnakeCase(args[1])
+		var target *generated.DeletedSecret
+		for i, secret := range deleted {
+			if secret.ID == args[1] || (secret.Key == normalized && secret.Environment == environment) {
+				target = &deleted[i]
+				break
+			}
+		}
+		if target == nil {
+			fail(notFoundError("no deleted secret %s in project %s (environment %s)", normalized, project.Name, environment))
+		}
+
+		restored, err := db_rw.RestoreSecret(ctx, mainDb.DB, mainDb.Queries, cipher, target.ID, audit.LocalOrigin())
+		if err != nil {
+			if errors.Is(err, db_rw.ErrDuplicateKey) {
+				fail(conflictError("%s is in use again in the environment, delete or rename it first", target.Key))
+			}
+			failf("failed to restore secret: %w", err)
+		}
+
+		printResult(newSecretResult(restored, false), func() {
+			fmt.Printf("✓ %s restored (environment %s)\n", restored.Key, restored.Environment)
+		})
+	},
+}
+
+func updateSecret(ctx context.Context, mainDb database.DB_Struct, cipher *vault.Cipher, params generated.UpdateSecretParams) generated.SecretList {
+	secret, err := db_rw.UpdateSecret(ctx, mainDb.DB, mainDb.Queries, cipher, params, audit.LocalOrigin())
+	if err != nil {
+		failf("failed to update secret: %w", err)
+	}
+	return secret
+}
+
+// readSecretValue reads the value for "secret set" from --from-file, piped
+// stdin or a prompt without echo
+func readSecretValue(key string) (string, error) {
+	var data []byte
+	var err error
+
+	switch {
+	case secretFromFile != "" && secretFromFile != "-":
+		data, err = os.ReadFile(secretFromFile)
+		if err != nil {
+			return "", fmt.Errorf("failed to read value: %w", err)
+		}
+		return string(data), nil
+
+	case secretFromFile == "" && term.IsTerminal(int(os.Stdin.Fd())):
+		fmt.Fprintf(os.Stderr, "Value for %s: ", key)
+		data, err = term.ReadPassword(int(os.Stdin.Fd()))
+		fmt.Fprintln(os.Stderr)
+
+	default:
+		data, err = io.ReadAll(os.Stdin)
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to read value: %w", err)
+	}
+
+	value := strings.TrimSuffix(string(data), "\n")
+	return strings.TrimSuffix(value, "\r"), nil
+}
+
+// lookupSecret finds a secret by project name or ID and key, in the
+// environment given with --env
+func lookupSecret(ctx context.Context, queries *generated.Queries, projectName string, key string) generated.SecretList {
+	environment := secretEnvironment()
+	project := lookupProject(ctx, queries, projectName)
+
+	secret, found := findSecret(ctx, queries, project, key, environment)
+	if !found {
+		fail(secretNotFound(key, project, environment))
+	}
+	return secret
+}
+
+// describeSecrets lists secrets as PROJECT/KEY (environment)
+func describeSecrets(ctx context.Context, queries *generated.Queries, secrets []generated.SecretList) string {
+	projects, err := queries.GetAllProjects(ctx)
+	if err != nil {
+		failf("failed to fetch projects: %w", err)
+	}
+	names := make(map[string]string, len(projects))
+	for _, project := range projects {
+		names[project.ID] = project.Name
+	}
+
+	described := make([]string, 0, len(secrets))
+	for _, secret := range secrets {
+		described = append(described, fmt.Sprintf("%s/%s (%s)", names[secret.ProjectID], secret.Key, secret.Environment))
+	}
+	return strings.Join(described, ", ")
+}
+
+func secretNotFound(key string, project generated.ProjectList, environment string) error {
+	return notFoundError("secret %s not found in project %s (environment %s)", utils.ToScreamingSnakeCase(key), project.Name, environment)
+}
+
+// findSecret looks a secret up by ID, or by key among the rows stored in
+// the environment
+func findSecret(ctx context.Context, queries *generated.Queries, project generated.ProjectList, key string, environment string) (generated.SecretList, bool) {
+	secrets, err := queries.GetSecretsByProjectID(ctx, project.ID)
+	if err != nil {
+		failf("failed to fetch secrets: %w", err)
+	}
+
+	normalized := utils.ToScreamingSnakeCase(key)
+	for _, secret := range secrets {
+		if secret.ID == key || (secret.Key == normalized && secret.Environment == environment) {
+			return secret, true
+		}
+	}
+	return generated.SecretList{}, false
+}
+
+func secretEnvironment() string {
+	environment, err := utils.NormalizeEnvironment(secretEnv)
+	if err != nil {
+		fail(usageError("%v", err))
+	}
+	return environment
+}
+
+// secretResult is a secret in --output json and yaml. The value is left out
+// unless it was asked for, the stored one is encrypted anyway.
+type secretResult struct {
+	ID          string     `json:"id"`
+	ProjectID   string     `json:"project_id"`
+	Environment string     `json:"environment"`
+	Key         string     `json:"key"`
+	Value       *string    `json:"value,omitempty"`
+	Type        string     `json:"type"`
+	Description *string    `json:"description"`
+	CreatedAt   *time.Time `json:"created_at"`
+	UpdatedAt   *time.Time `json:"updated_at"`
+}
+
+func newSecretResult(secret generated.SecretList, withValue bool) secretResult {
+	result := secretResult{
+		ID:          secret.ID,
+		ProjectID:   secret.ProjectID,
+		Environment: secret.Environment,
+		Key:         secret.Key,
+		Type:        secret.Type,
+		Description: secret.Description,
+		CreatedAt:   secret.CreatedAt,
+		UpdatedAt:   secret.UpdatedAt,
+	}
+	if withValue {
+		result.Value = &secret.Value
+	}
+	return result
+}
+
+type secretSetResult struct {
+	secretResult
+	Created bool `json:"created"`
+}
+
+type secretRollbackResult struct {
+	secretResult
+	Version int64 `json:"version"`
+}
+
+type secretVersionResult struct {
+	Version     int64      `json:"version"`
+	Key         string     `json:"key"`
+	Value       *string    `json:"value,omitempty"`
+	Type        string     `json:"type"`
+	Description *string    `json:"description"`
+	Actor       string     `json:"actor"`
+	CreatedAt   *time.Time `json:"created_at"`
+}
+
+func newSecretVersionResult(version generated.SecretVersion, withValue bool) secretVersionResult {
+	result := secretVersionResult{
+		Version:     version.Version,
+		Key:         version.Key,
+		Type:        version.Type,
+		Description: version.Description,
+		Actor:       version.Actor,
+		CreatedAt:   version.CreatedAt,
+	}
+	if withValue {
+		result.Value = &version.Value
+	}
+	return result
+}
+
+// deletedSecretResult is a deleted secret in --output json and yaml
+type deletedSecretResult struct {
+	ID          string     `json:"id"`
+	ProjectID   string     `json:"project_id"`
+	Environment string     `json:"environment"`
+	Key         string     `json:"key"`
+	Version     int64      `json:"version"`
+	Actor       string     `json:"actor"`
+	DeletedAt   *time.Time `json:"deleted_at"`
+}
+
+type secretHistoryResult struct {
+	Secret   secretResult          `json:"secret"`
+	Versions []secretVersionResult `json:"versions"`
+}
+
+func displayValue(value string) string {
+	if !secretShowValues {
+		return "********"
+	}
+	return strings.ReplaceAll(value, "\n", `\n`)
+}
+
+func displayDescription(description *string) string {
+	if description == nil || *description == "" {
+		return "-"
+	}
+	return *description
+}
+
+func init() {
+	rootCmd.AddCommand(secretCmd)
+	secretCmd.AddCommand(secretListCmd)
+	secretCmd.AddCommand(secretGetCmd)
+	secretCmd.AddCommand(secretSetCmd)
+	secretCmd.AddCommand(secretUnsetCmd)
+	secretCmd.AddCommand(secretDescribeCmd)
+	secretCmd.AddCommand(secretHistoryCmd)
+	secretCmd.AddCommand(secretRollbackCmd)
+	secretCmd.AddCommand(secretDeletedCmd)
+	secretCmd.AddCommand(secretRestoreCmd)
+
+	secretCmd.PersistentFlags().StringVarP(&secretEnv, "env", "e", utils.BaseEnvironment, "Environment the secret is stored in")
+	secretListCmd.Flags().BoolVar(&secretShowValues, "show-values", false, "Print secret values instead of masking them")
+	secretSetCmd.Flags().StringVarP(&secretFromFile, "from-file", "f", "", "Read the value from this file, - for stdin")
+	secretSetCmd.Flags().StringVarP(&secretDescription, "description", "d", "", "Description of the secret")
+	secretSetCmd.Flags().StringVarP(&secretType, "type", "t", utils.SecretTypeText, "Secret type: text, or file to inject the value as a temporary file")
+	secretUnsetCmd.Flags().BoolVar(&secretForce, "force", false, "Delete the secret even if other secrets reference it")
+	secretHistoryCmd.Flags().BoolVar(&secretShowValues, "show-values", false, "Print secret values instead of masking them")
+}
@@ -1,8 +1,7 @@
 package cmd
 
 import (
-	"fmt"
-	"os"
+	"time"
 
 	"github.com/Knightshrestha/Secret-Injector/core"
 	"github.com/spf13/cobra"
@@ -10,18 +9,64 @@ import (
 
 var port int
 var logging bool
+var bindAddress string
+var tlsCert string
+var tlsKey string
+var tlsSelfSigned bool
+var socketPath string
+var backupInterval time.Duration
+var backupKeep int
+var corsOrigins []string
 
 // serveCmd represents the serve command
 var serveCmd = &cobra.Command{
 	Use:   "serve",
 	Short: "Start the Secret Injector UI Server",
-	Long:  `This starts the main server`,
+	Long: `This starts the main server
+
+By default it listens on 127.0.0.1 over plain HTTP. Use --bind to listen on
+another interface, --tls-cert/--tls-key or --tls-self-signed for HTTPS, and
+--socket to listen on a Unix domain socket instead of a TCP port.
+
+With --backup-interval a backup is written next to the database on that
+schedule and only the newest --backup-keep are kept.`,
 	Run: func(cmd *cobra.Command, args []string) {
 		if port < 1024 || port > 65535 {
-			fmt.Fprintf(os.Stderr, "Error: port must be between 1024 and 65535\n")
-			os.Exit(1)
+			fail(usageError("port must be between 1024 and 65535"))
+		}
+		if (tlsCert == "") != (tlsKey == "") {
+			fail(usageError("--tls-cert and --tls-key must be used together"))
+		}
+		if tlsSelfSigned && tlsCert != "" {
+			fail(usageError("--tls-self-signed cannot be combined with --tls-cert"))
+		}
+		if socketPath != "" && cmd.Flags().Changed("bind") {
+			fail(usageError("--socket cannot be combined with --bind"))
+		}
+		if backupInterval < 0 || (backupInterval > 0 && backupInterval < time.Minute) {
+			fail(usageError("--backup-interval must be at least 1m"))
+		}
+		if backupKeep < 1 {
+			fail(usageError("--backup-keep must be at least 1"))
+		}
+
+		err := core.StartServer(core.ServerOptions{
+			Port:       port,
+			Bind:       bindAddress,
+			Logging:    logging,
+			TLSCert:    tlsCert,
+			TLSKey:     tlsKey,
+			SelfSigned: tlsSelfSigned,
+			Socket:     socketPath,
+
+			CORSOrigins: corsOrigins,
+
+			BackupInterval: backupInterval,
+			BackupKeep:     backupKeep,
+		})
+		if err != nil {
+			fail(err)
 		}
-		core.StartServer(port, logging)
 	},
 }
 
@@ -30,4 +75,12 @@ func init() {
 
 	serveCmd.Flags().IntVarP(&port, "port", "p", 5544, "Port to run the server on")
 	serveCmd.Flags().BoolVarP(&logging, "debug", "d", false, "Enable Logging")
+	serveCmd.Flags().StringVar(&bindAddress, "bind", "127.0.0.1", "Address to listen on (0.0.0.0 for all interfaces)")
+	serveCmd.Flags().StringVar(&tlsCert, "tls-cert", "", "TLS certificate file (PEM)")
+	serveCmd.Flags().StringVar(&tlsKey, "tls-key", "", "TLS private key file (PEM)")
+	serveCmd.Flags().BoolVar(&tlsSelfSigned, "tls-self-signed", false, "Serve HTTPS with a self-signed certificate kept next to the database")
+	serveCmd.Flags().StringVar(&socketPath, "socket", "", "Listen on a Unix domain socket (0600) instead of a TCP port")
+	serveCmd.Flags().StringSliceVar(&corsOrigins, "cors-origins", []string{"http://localhost:5173"}, "Origins allowed to call the API from a browser")
+	serveCmd.Flags().DurationVar(&backupInterval, "backup-interval", 0, "Back up the database this often, e.g. 6h (0 disables)")
+	serveCmd.Flags().IntVar(&backupKeep, "backup-keep", 7, "Number of scheduled backups to keep")
 }
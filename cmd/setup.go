@@ -2,7 +2,6 @@ package cmd
 
 import (
 	"fmt"
-	"log"
 	
 	"github.com/Knightshrestha/Secret-Injector/database"
 	"github.com/spf13/cobra"
@@ -15,13 +14,24 @@ var setupCmd = &cobra.Command{
 	Long:  `Create the database file and setup tables.`,
 	Run: func(cmd *cobra.Command, args []string) {
 		if err := database.SetupDatabase(); err != nil {
-			log.Fatal("Failed to setup database:", err)
+			failf("failed to setup database: %w", err)
 		}
 		
-		fmt.Println("✓ Database schema initialized")
+		_, version, err := database.MigrationStatus()
+		if err != nil {
+			failf("failed to read schema version: %w", err)
+		}
+
+		printResult(setupResult{SchemaVersion: version}, func() {
+			fmt.Printf("✓ Database schema initialized (version %d)\n", version)
+		})
 	},
 }
 
+type setupResult struct {
+	SchemaVersion int `json:"schema_version"`
+}
+
 func init() {
 	rootCmd.AddCommand(setupCmd)
 }
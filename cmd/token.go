@@ -0,0 +1,259 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/Knightshrestha/Secret-Injector/auth"
+	"github.com/Knightshrestha/Secret-Injector/database"
+	"github.com/Knightshrestha/Secret-Injector/database/generated"
+	"github.com/spf13/cobra"
+)
+
+var tokenScope string
+var tokenProjects []string
+var tokenExpires string
+
+// tokenCmd represents the token command
+var tokenCmd = &cobra.Command{
+	Use:   "token",
+	Short: "Manage API tokens for the server",
+	Long:  `Create, list and revoke the bearer tokens accepted by the HTTP API and event streams of "serve".`,
+}
+
+var tokenCreateCmd = &cobra.Command{
+	Use:   "create NAME",
+	Short: "Create a new API token",
+	Long: `Create a new API token.
+
+read tokens can fetch projects and secrets, write tokens can also change them
+and admin tokens can do everything. With --project the token only sees the
+listed projects (not allowed for admin tokens).`,
+	Example: `  secret_injector token create ci --scope read --project API --expires 30d
+  secret_injector token create alice --scope write`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		scope, err := auth.ParseScope(tokenScope)
+		if err != nil {
+			fail(usageError("%v", err))
+		}
+		if scope == auth.ScopeAdmin && len(tokenProjects) > 0 {
+			fail(usageError("admin tokens cannot be limited to projects"))
+		}
+
+		var expiresAt *time.Time
+		if tokenExpires != "" {
+			lifetime, err := parseLifetime(tokenExpires)
+			if err != nil {
+				fail(usageError("%v", err))
+			}
+			t := time.Now().UTC().Add(lifetime)
+			expiresAt = &t
+		}
+
+		projects, err := lookupProjects(tokenProjects)
+		if err != nil {
+			fail(err)
+		}
+		var projectIDs []string
+		for _, project := range projects {
+			projectIDs = append(projectIDs, project.ID)
+		}
+
+		mainDb := openWriteDatabase()
+		defer database.CloseWriteDatabase(mainDb.DB)
+
+		token, row, err := auth.CreateToken(context.Background(), mainDb.DB, mainDb.Queries, auth.TokenParams{
+			Name:       args[0],
+			Scope:      scope,
+			ProjectIDs: projectIDs,
+			ExpiresAt:  expiresAt,
+		})
+		if err != nil {
+			fail(err)
+		}
+
+		result := newTokenResult(row, projects)
+		result.Token = token
+		printResult(result, func() {
+			fmt.Fprintf(os.Stderr, "✓ Token %q created (ID: %s), it is shown only once:\n", row.Name, row.ID)
+			fmt.Println(token)
+		})
+	},
+}
+
+var tokenListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List API tokens",
+	Args:  cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		mainDb := openWriteDatabase()
+		defer database.CloseWriteDatabase(mainDb.DB)
+
+		ctx := context.Background()
+
+		tokens, err := mainDb.Queries.GetAllApiTokens(ctx)
+		if err != nil {
+			failf("failed to fetch tokens: %w", err)
+		}
+
+		projects, err := mainDb.Queries.GetAllProjects(ctx)
+		if err != nil {
+			failf("failed to fetch projects: %w", err)
+		}
+		projectsByID := make(map[string]generated.ProjectList, len(projects))
+		for _, project := range projects {
+			projectsByID[project.ID] = project
+		}
+
+		results := []tokenResult{}
+		for _, token := range tokens {
+			var scoped []generated.ProjectList
+			if token.Restricted {
+				ids, err := mainDb.Queries.GetApiTokenProjectIDs(ctx, token.ID)
+				if err != nil {
+					failf("failed to fetch token projects: %w", err)
+				}
+				for _, id := range ids {
+					scoped = append(scoped, projectsByID[id])
+				}
+			}
+			results = append(results, newTokenResult(token, scoped))
+		}
+
+		printResult(results, func() {
+			if len(results) == 0 {
+				fmt.Println("No tokens")
+				return
+			}
+
+			w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+			fmt.Fprintln(w, "ID\tNAME\tSCOPE\tPROJECTS\tEXPIRES\tCREATED\tLAST USED")
+			for _, token := range results {
+				scopeProjects := "all"
+				if token.Restricted {
+					scopeProjects = strings.Join(token.Projects, ",")
+					if scopeProjects == "" {
+						scopeProjects = "none"
+					}
+				}
+
+				expires := "never"
+				if token.ExpiresAt != nil {
+					expires = formatTime(token.ExpiresAt)
+				}
+
+				fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\t%s\n",
+					token.ID, token.Name, token.Scope, scopeProjects, expires,
+					formatTime(token.CreatedAt), formatTime(token.LastUsedAt))
+			}
+			w.Flush()
+		})
+	},
+}
+
+var tokenRevokeCmd = &cobra.Command{
+	Use:   "revoke NAME|ID",
+	Short: "Revoke an API token",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		mainDb := openWriteDatabase()
+		defer database.CloseWriteDatabase(mainDb.DB)
+
+		ctx := context.Background()
+
+		tokens, err := mainDb.Queries.GetAllApiTokens(ctx)
+		if err != nil {
+			failf("failed to fetch tokens: %w", err)
+		}
+
+		for _, token := range tokens {
+			if token.ID != args[0] && token.Name != args[0] {
+				continue
+			}
+			if err := mainDb.Queries.DeleteApiTokenProjects(ctx, token.ID); err != nil {
+				failf("failed to revoke token: %w", err)
+			}
+			if err := mainDb.Queries.DeleteApiToken(ctx, token.ID); err != nil {
+				failf("failed to revoke token: %w", err)
+			}
+			printResult(newTokenResult(token, nil), func() {
+				fmt.Printf("✓ Token %q revoked\n", token.Name)
+			})
+			return
+		}
+
+		fail(notFoundError("no token named or with ID %q", args[0]))
+	},
+}
+
+// tokenResult is a token in --output json and yaml. The token itself is only
+// known when it is created.
+type tokenResult struct {
+	ID         string     `json:"id"`
+	Name       string     `json:"name"`
+	Scope      string     `json:"scope"`
+	Restricted bool       `json:"restricted"`
+	Projects   []string   `json:"projects"`
+	ExpiresAt  *time.Time `json:"expires_at"`
+	CreatedAt  *time.Time `json:"created_at"`
+	LastUsedAt *time.Time `json:"last_used_at"`
+	Token      string     `json:"token,omitempty"`
+}
+
+func newTokenResult(token generated.ApiToken, projects []generated.ProjectList) tokenResult {
+	result := tokenResult{
+		ID:         token.ID,
+		Name:       token.Name,
+		Scope:      token.Scope,
+		Restricted: token.Restricted,
+		Projects:   []string{},
+		ExpiresAt:  token.ExpiresAt,
+		CreatedAt:  token.CreatedAt,
+		LastUsedAt: token.LastUsedAt,
+	}
+	for _, project := range projects {
+		result.Projects = append(result.Projects, project.Name)
+	}
+	return result
+}
+
+// parseLifetime accepts Go durations plus a "d" suffix for days, e.g. 30d
+func parseLifetime(s string) (time.Duration, error) {
+	if days, ok := strings.CutSuffix(s, "d"); ok {
+		n, err := strconv.Atoi(days)
+		if err != nil || n <= 0 {
+			return 0, fmt.Errorf("invalid lifetime %q", s)
+		}
+		return time.Duration(n) * 24 * time.Hour, nil
+	}
+
+	d, err := time.ParseDuration(s)
+	if err != nil || d <= 0 {
+		return 0, fmt.Errorf("invalid lifetime %q (use e.g. 12h or 30d)", s)
+	}
+	return d, nil
+}
+
+func formatTime(t *time.Time) string {
+	if t == nil {
+		return "never"
+	}
+	return t.Local().Format("2006-01-02 15:04")
+}
+
+func init() {
+	rootCmd.AddCommand(tokenCmd)
+	tokenCmd.AddCommand(tokenCreateCmd)
+	tokenCmd.AddCommand(tokenListCmd)
+	tokenCmd.AddCommand(tokenRevokeCmd)
+
+	tokenCreateCmd.Flags().StringVar(&tokenScope, "scope", "read", "Token scope: read, write or admin")
+	tokenCreateCmd.Flags().StringArrayVarP(&tokenProjects, "project", "p", nil, "Limit the token to this project (repeatable)")
+	tokenCreateCmd.Flags().StringVar(&tokenExpires, "expires", "", "Lifetime of the token, e.g. 12h or 30d (default never)")
+}
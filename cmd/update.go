@@ -4,7 +4,6 @@ Copyright © 2025 NAME HERE <EMAIL ADDRESS>
 package cmd
 
 import (
-	"fmt"
 	"os"
 
 	"github.com/Knightshrestha/Secret-Injector/config"
@@ -12,6 +11,9 @@ import (
 	"github.com/spf13/cobra"
 )
 
+var updateOwner string
+var updateRepo string
+
 // updateCmd represents the update command
 var updateCmd = &cobra.Command{
 	Use:   "update",
@@ -19,19 +21,27 @@ var updateCmd = &cobra.Command{
 	Long: `This command will fetch the latest release from github, check if new version is available and if so, download and update the app version. The old file is appended with ".old" so that we can go back in case something has gone wrong.`,
 	Run: func(cmd *cobra.Command, args []string) {
 		updateStruct := &updater.Updater{
-			Owner:      config.Owner, // Replace with your GitHub username
-			Repo:       config.Repo,  // Replace with your repo name
+			Owner:      updateOwner,
+			Repo:       updateRepo,
 			CurrentVer: config.AppVersion,
 			ExeName:    "secret_injector", // Your exe name without .exe
 		}
+		// Keep stdout for the result with --output json or yaml
+		if outputFormat != outputText {
+			updateStruct.Out = os.Stderr
+		}
 
-		if err := updateStruct.Update(); err != nil {
-			fmt.Fprintf(os.Stderr, "Update failed: %v\n", err)
-			os.Exit(1)
+		result, err := updateStruct.Update()
+		if err != nil {
+			failf("update failed: %w", err)
 		}
+		printResult(result, func() {})
 	},
 }
 
 func init() {
 	rootCmd.AddCommand(updateCmd)
+
+	updateCmd.Flags().StringVar(&updateOwner, "owner", config.Owner, "GitHub owner to fetch releases from")
+	updateCmd.Flags().StringVar(&updateRepo, "repo", config.Repo, "GitHub repository to fetch releases from")
 }
@@ -16,10 +16,16 @@ var versionCmd = &cobra.Command{
 	Short: "A method to get app version",
 	Long: `When you run this command, it will return the current version of app in format on v0.0.0.`,
 	Run: func(cmd *cobra.Command, args []string) {
-		fmt.Printf("Version: v%s", config.AppVersion)
+		printResult(versionResult{Version: config.AppVersion}, func() {
+			fmt.Printf("Version: v%s\n", config.AppVersion)
+		})
 	},
 }
 
+type versionResult struct {
+	Version string `json:"version"`
+}
+
 func init() {
 	rootCmd.AddCommand(versionCmd)
 
@@ -0,0 +1,122 @@
+package config
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// ErrNotAllowed is a .secretinjector.toml that was never allowed, or that
+// changed since it was. A checked-in file comes with the repository, so it
+// only selects secrets once its user has read and allowed it.
+var ErrNotAllowed = errors.New("not allowed")
+
+// allowedPath is the per-user list of allowed directory files, mapping the
+// absolute path of each to the SHA-256 of the content that was allowed
+func allowedPath() (string, error) {
+	dataHome, err := DataHome()
+	if err != nil {
+		return "", fmt.Errorf("cannot find data directory: %w", err)
+	}
+	return filepath.Join(dataHome, AppDir, "allowed.json"), nil
+}
+
+func loadAllowed() (map[string]string, error) {
+	path, err := allowedPath()
+	if err != nil {
+		return nil, err
+	}
+
+	allowed := map[string]string{}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return allowed, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("cannot read %s: %w", path, err)
+	}
+	if err := json.Unmarshal(data, &allowed); err != nil {
+		return nil, fmt.Errorf("cannot read %s: %w", path, err)
+	}
+	return allowed, nil
+}
+
+func saveAllowed(allowed map[string]string) error {
+	path, err := allowedPath()
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return err
+	}
+
+	data, err := json.MarshalIndent(allowed, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	// Written aside and renamed, so a crash never leaves half a list
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".allowed-*.json")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
+
+// CheckAllowed returns ErrNotAllowed unless f was allowed with its current
+// content
+func (f *DirectoryFile) CheckAllowed() error {
+	allowed, err := loadAllowed()
+	if err != nil {
+		return err
+	}
+
+	hash, ok := allowed[f.Path]
+	switch {
+	case !ok:
+		return fmt.Errorf("%s is %w", f.Path, ErrNotAllowed)
+	case hash != f.Hash:
+		return fmt.Errorf("%s is %w, it changed since it was allowed", f.Path, ErrNotAllowed)
+	}
+	return nil
+}
+
+// Allow records the current content of f as allowed
+func (f *DirectoryFile) Allow() error {
+	allowed, err := loadAllowed()
+	if err != nil {
+		return err
+	}
+	allowed[f.Path] = f.Hash
+	return saveAllowed(allowed)
+}
+
+// Deny forgets the file at path, allowed or not. It reports whether the
+// file had been allowed.
+func Deny(path string) (bool, error) {
+	path, err := filepath.Abs(path)
+	if err != nil {
+		return false, err
+	}
+
+	allowed, err := loadAllowed()
+	if err != nil {
+		return false, err
+	}
+	if _, ok := allowed[path]; !ok {
+		return false, nil
+	}
+	delete(allowed, path)
+	return true, saveAllowed(allowed)
+}
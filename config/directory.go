@@ -0,0 +1,156 @@
+package config
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+)
+
+// DirectoryFileName is the per-directory file selecting what inject and
+// export use, usually checked in at the top of a repository
+const DirectoryFileName = ".secretinjector.toml"
+
+// DirectoryFile is a .secretinjector.toml, e.g.
+//
+//	projects = ["API", "SHARED"]
+//	env = "dev"
+//	include = ["DATABASE_*", "SENTRY_DSN"]
+//	exclude = ["*_ADMIN_*"]
+//
+//	[rename]
+//	SENTRY_DSN = "NEXT_PUBLIC_SENTRY_DSN"
+type DirectoryFile struct {
+	// Projects are used in order, later ones win like repeated --project
+	Projects []string `toml:"projects" json:"projects"`
+
+	// Env is the environment to resolve, base when empty
+	Env string `toml:"env" json:"env"`
+
+	// Include keeps only the keys matching one of these patterns (all when
+	// empty), Exclude then drops the keys matching one of its patterns.
+	// Patterns use * and ? as in path.Match.
+	Include []string `toml:"include" json:"include"`
+	Exclude []string `toml:"exclude" json:"exclude"`
+
+	// Rename maps a key to the variable name it is passed as
+	Rename map[string]string `toml:"rename" json:"rename"`
+
+	// Path is the file that was read, Hash the SHA-256 of its content
+	Path string `toml:"-" json:"path"`
+	Hash string `toml:"-" json:"hash"`
+}
+
+// FindDirectoryFile looks for a .secretinjector.toml in dir and its
+// parents and returns its absolute path, or "" when there is none
+func FindDirectoryFile(dir string) (string, error) {
+	dir, err := filepath.Abs(dir)
+	if err != nil {
+		return "", err
+	}
+
+	for {
+		candidate := filepath.Join(dir, DirectoryFileName)
+		info, err := os.Stat(candidate)
+		if err == nil && info.Mode().IsRegular() {
+			return candidate, nil
+		}
+		if err != nil && !errors.Is(err, os.ErrNotExist) {
+			return "", err
+		}
+
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return "", nil
+		}
+		dir = parent
+	}
+}
+
+// LoadDirectoryFile reads a .secretinjector.toml. Unknown keys are an error
+// so a typo does not silently widen what is injected.
+func LoadDirectoryFile(file string) (*DirectoryFile, error) {
+	file, err := filepath.Abs(file)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return nil, fmt.Errorf("cannot read %s: %w", file, err)
+	}
+
+	dirFile := &DirectoryFile{Path: file}
+	meta, err := toml.Decode(string(data), dirFile)
+	if err != nil {
+		return nil, fmt.Errorf("cannot read %s: %w", file, err)
+	}
+	if undecoded := meta.Undecoded(); len(undecoded) > 0 {
+		return nil, fmt.Errorf("cannot read %s: unknown setting %q", file, undecoded[0].String())
+	}
+
+	for _, pattern := range slices.Concat(dirFile.Include, dirFile.Exclude) {
+		if _, err := path.Match(pattern, ""); err != nil {
+			return nil, fmt.Errorf("cannot read %s: invalid pattern %q", file, pattern)
+		}
+	}
+	for key, name := range dirFile.Rename {
+		if name == "" || strings.ContainsAny(name, "=\x00") {
+			return nil, fmt.Errorf("cannot read %s: invalid name %q for %s", file, name, key)
+		}
+	}
+
+	sum := sha256.Sum256(data)
+	dirFile.Hash = hex.EncodeToString(sum[:])
+	return dirFile, nil
+}
+
+// Apply filters and renames values (key -> value) as the file says
+func (f *DirectoryFile) Apply(values map[string]string) map[string]string {
+	result := make(map[string]string, len(values))
+	for key, value := range values {
+		if len(f.Include) > 0 && !matchAny(f.Include, key) {
+			continue
+		}
+		if matchAny(f.Exclude, key) {
+			continue
+		}
+		result[key] = value
+	}
+
+	// Sorted so two keys renamed to one name always end the same way
+	keys := make([]string, 0, len(f.Rename))
+	for key := range f.Rename {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	renamed := make(map[string]string)
+	for _, key := range keys {
+		if value, ok := result[key]; ok {
+			delete(result, key)
+			renamed[f.Rename[key]] = value
+		}
+	}
+	for name, value := range renamed {
+		result[name] = value
+	}
+	return result
+}
+
+func matchAny(patterns []string, key string) bool {
+	for _, pattern := range patterns {
+		if ok, _ := path.Match(pattern, key); ok {
+			return true
+		}
+	}
+	return false
+}
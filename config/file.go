@@ -0,0 +1,188 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+
+	"github.com/BurntSushi/toml"
+)
+
+// AppDir names the folder used under the config and data directories
+const AppDir = "secret_injector"
+
+// EnvConfig points at a config file other than the default one
+const EnvConfig = "SECRET_INJECTOR_CONFIG"
+
+// File is the TOML config file, e.g.
+//
+//	db = "/srv/secrets/secrets.db"
+//
+//	[vaults.work]
+//	db = "~/work/secrets.db"
+//	key_file = "~/work/secret_injector.key"
+//
+//	[server]
+//	port = 5544
+//	cors_origins = ["http://localhost:5173"]
+type File struct {
+	// DB is the database of the default vault
+	DB string `toml:"db"`
+
+	// Vaults are the named vaults selected with --vault
+	Vaults map[string]Vault `toml:"vaults"`
+
+	// Values holds every other setting by its dotted key, e.g.
+	// "server.port", with lists kept as one entry per item
+	Values map[string][]string `toml:"-"`
+
+	// Path is the file that was read, Found is false when it does not exist
+	Path  string `toml:"-"`
+	Found bool   `toml:"-"`
+}
+
+// Vault points a named vault at its database and, optionally, master key
+type Vault struct {
+	DB      string `toml:"db"`
+	KeyFile string `toml:"key_file"`
+}
+
+// ConfigPath is set from the --config flag and takes precedence over the
+// environment
+var ConfigPath string
+
+// FilePath returns the config file in use and whether it was chosen
+// explicitly (--config or SECRET_INJECTOR_CONFIG) rather than being the
+// default in the user config directory, e.g.
+// ~/.config/secret_injector/config.toml
+func FilePath() (string, bool, error) {
+	if ConfigPath != "" {
+		return ExpandHome(ConfigPath), true, nil
+	}
+	if path := os.Getenv(EnvConfig); path != "" {
+		return ExpandHome(path), true, nil
+	}
+
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", false, fmt.Errorf("cannot find config directory: %w", err)
+	}
+	return filepath.Join(dir, AppDir, "config.toml"), false, nil
+}
+
+// Load reads the config file. A missing default file is the same as an
+// empty one, a missing file that was asked for is an error.
+func Load() (File, error) {
+	path, explicit, err := FilePath()
+	if err != nil {
+		return File{}, err
+	}
+
+	file := File{Path: path, Values: map[string][]string{}}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) && !explicit {
+			return file, nil
+		}
+		return File{}, fmt.Errorf("cannot read config %s: %w", path, err)
+	}
+	file.Found = true
+
+	if err := toml.Unmarshal(data, &file); err != nil {
+		return File{}, fmt.Errorf("cannot read config %s: %w", path, err)
+	}
+
+	var raw map[string]any
+	if err := toml.Unmarshal(data, &raw); err != nil {
+		return File{}, fmt.Errorf("cannot read config %s: %w", path, err)
+	}
+	if err := flatten("", raw, file.Values); err != nil {
+		return File{}, fmt.Errorf("cannot read config %s: %w", path, err)
+	}
+
+	file.DB = ExpandHome(file.DB)
+	for name, v := range file.Vaults {
+		v.DB = ExpandHome(v.DB)
+		v.KeyFile = ExpandHome(v.KeyFile)
+		file.Vaults[name] = v
+	}
+	return file, nil
+}
+
+// flatten turns nested tables into dotted keys. db and vaults are decoded
+// into File itself and left out.
+func flatten(prefix string, table map[string]any, out map[string][]string) error {
+	for name, value := range table {
+		key := prefix + name
+		if key == "db" || key == "vaults" {
+			continue
+		}
+
+		switch v := value.(type) {
+		case map[string]any:
+			if err := flatten(key+".", v, out); err != nil {
+				return err
+			}
+		case []any:
+			items := []string{}
+			for _, item := range v {
+				if _, ok := item.(map[string]any); ok {
+					return fmt.Errorf("%s: expected a list of values", key)
+				}
+				items = append(items, fmt.Sprint(item))
+			}
+			out[key] = items
+		default:
+			out[key] = []string{fmt.Sprint(v)}
+		}
+	}
+	return nil
+}
+
+// ExpandHome replaces a leading ~ with the home directory
+func ExpandHome(path string) string {
+	if path != "~" && !hasHomePrefix(path) {
+		return path
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return path
+	}
+	return filepath.Join(home, path[1:])
+}
+
+func hasHomePrefix(path string) bool {
+	return len(path) > 1 && path[0] == '~' && (path[1] == '/' || path[1] == '\\')
+}
+
+// DataHome is the per-user data directory: $XDG_DATA_HOME, otherwise the
+// platform default (~/.local/share, ~/Library/Application Support or
+// %LocalAppData%)
+func DataHome() (string, error) {
+	if dir := os.Getenv("XDG_DATA_HOME"); dir != "" {
+		return dir, nil
+	}
+
+	switch runtime.GOOS {
+	case "windows":
+		if dir := os.Getenv("LocalAppData"); dir != "" {
+			return dir, nil
+		}
+		return "", errors.New("%LocalAppData% is not set")
+	case "darwin":
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return "", err
+		}
+		return filepath.Join(home, "Library", "Application Support"), nil
+	default:
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return "", err
+		}
+		return filepath.Join(home, ".local", "share"), nil
+	}
+}
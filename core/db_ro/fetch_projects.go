@@ -8,7 +8,7 @@ import (
 )
 
 func FetchProjects() ([]generated.ProjectList, error) {
-	mainDb, err := database.OpenReadDatabase()
+	mainDb, err := database.OpenMigratedReadDatabase()
 	if err != nil {
 		return nil, err
 	}
@@ -3,18 +3,30 @@ package db_ro
 import (
 	"context"
 	"fmt"
+	"slices"
 
+	"github.com/Knightshrestha/Secret-Injector/audit"
 	"github.com/Knightshrestha/Secret-Injector/database"
 	"github.com/Knightshrestha/Secret-Injector/database/generated"
+	"github.com/Knightshrestha/Secret-Injector/utils"
+	"github.com/Knightshrestha/Secret-Injector/vault"
 )
 
-func FetchSecrets(projectIds []string) ([]generated.SecretList, error) {
-	mainDb, err := database.OpenReadDatabase()
+// FetchSecrets returns the decrypted effective secrets of the given
+// projects in an environment, with references to other secrets resolved,
+// and records the read in the audit log under action
+func FetchSecrets(projectIds []string, environment string, action audit.Action) ([]generated.SecretList, error) {
+	mainDb, err := database.OpenMigratedReadDatabase()
 	if err != nil {
 		return nil, err
 	}
 	defer database.CloseReadDatabase(mainDb.DB)
 
+	cipher, err := vault.Unlock(context.Background(), mainDb.Queries)
+	if err != nil {
+		return nil, fmt.Errorf("failed to unlock database: %w", err)
+	}
+
 	var allSecrets []generated.SecretList
 
 	for _, projectId := range projectIds {
@@ -23,9 +35,40 @@ func FetchSecrets(projectIds []string) ([]generated.SecretList, error) {
 			return nil, fmt.Errorf("failed to fetch secrets for project %s: %w", projectId, err)
 		}
 
+		secrets, err = cipher.OpenSecrets(utils.ResolveEnvironment(secrets, environment))
+		if err != nil {
+			return nil, err
+		}
+
 		allSecrets = append(allSecrets, secrets...)
 	}
 
+	// ref:// values are replaced by the secret they point at, which is
+	// audited as read too
+	refs := NewReferences(context.Background(), mainDb.Queries, cipher)
+	read := make(map[string]bool, len(allSecrets))
+	for _, secret := range allSecrets {
+		read[secret.ID] = true
+	}
+	var sources []generated.SecretList
+	for i, secret := range allSecrets {
+		value, from, err := refs.Resolve(secret, environment)
+		if err != nil {
+			return nil, err
+		}
+		allSecrets[i].Value = value
+		for _, source := range from {
+			if !read[source.ID] {
+				read[source.ID] = true
+				sources = append(sources, source)
+			}
+		}
+	}
+
+	if err := audit.RecordLocal(context.Background(), action, append(slices.Clone(allSecrets), sources...)); err != nil {
+		return nil, fmt.Errorf("failed to record audit entry: %w", err)
+	}
+
 	return allSecrets, nil
 }
 
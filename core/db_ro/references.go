@@ -0,0 +1,211 @@
+package db_ro
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/Knightshrestha/Secret-Injector/database/generated"
+	"github.com/Knightshrestha/Secret-Injector/utils"
+	"github.com/Knightshrestha/Secret-Injector/vault"
+)
+
+// ErrBrokenReference is a ref:// value that leads to a missing project or
+// secret, or back to itself
+var ErrBrokenReference = errors.New("broken secret reference")
+
+// ErrReferenceDenied is a ref:// value that leads into a project the reader
+// is not allowed to read
+var ErrReferenceDenied = errors.New("secret reference to a project that cannot be read")
+
+var errNoProject = errors.New("project does not exist")
+
+// References resolves ref://PROJECT/KEY values. Projects and decrypted
+// secrets are loaded on first use and kept, so a resolver should not outlive
+// the request or command it serves.
+type References struct {
+	ctx     context.Context
+	queries *generated.Queries
+	cipher  *vault.Cipher
+	canRead func(projectID string) bool
+
+	projects  []generated.ProjectList
+	byProject map[string][]generated.SecretList
+	all       []generated.SecretList
+}
+
+func NewReferences(ctx context.Context, queries *generated.Queries, cipher *vault.Cipher) *References {
+	return &References{
+		ctx:       ctx,
+		queries:   queries,
+		cipher:    cipher,
+		byProject: make(map[string][]generated.SecretList),
+	}
+}
+
+// Restrict limits the references that resolve to those whose projects
+// canRead allows, every step of a chain is checked. It returns r.
+func (r *References) Restrict(canRead func(projectID string) bool) *References {
+	r.canRead = canRead
+	return r
+}
+
+// Check refuses a value that is a reference into a project the resolver may
+// not read. References to missing projects are left to Resolve.
+func (r *References) Check(value string) error {
+	ref, ok := utils.ParseReference(value)
+	if !ok || r.canRead == nil {
+		return nil
+	}
+	project, err := r.project(ref.Project)
+	if errors.Is(err, errNoProject) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if !r.canRead(project.ID) {
+		return fmt.Errorf("%w: %s", ErrReferenceDenied, ref.String())
+	}
+	return nil
+}
+
+// Resolve returns the value secret stands for in environment, or in its own
+// environment when empty: its value, or for a reference the value at the
+// end of the chain of references. The secrets the value was taken from are
+// returned with it.
+func (r *References) Resolve(secret generated.SecretList, environment string) (string, []generated.SecretList, error) {
+	if environment == "" {
+		environment = secret.Environment
+	}
+
+	value := secret.Value
+	seen := map[string]bool{secret.ID: true}
+	chain := []string{secret.Key}
+	var sources []generated.SecretList
+
+	for {
+		ref, ok := utils.ParseReference(value)
+		if !ok {
+			return value, sources, nil
+		}
+		chain = append(chain, ref.String())
+
+		target, err := r.lookup(ref, environment)
+		if errors.Is(err, ErrReferenceDenied) {
+			return "", nil, fmt.Errorf("%w: %s", ErrReferenceDenied, strings.Join(chain, " -> "))
+		}
+		if err != nil {
+			return "", nil, fmt.Errorf("%w: %s: %v", ErrBrokenReference, strings.Join(chain, " -> "), err)
+		}
+		if seen[target.ID] {
+			return "", nil, fmt.Errorf("%w: cycle %s", ErrBrokenReference, strings.Join(chain, " -> "))
+		}
+		seen[target.ID] = true
+
+		sources = append(sources, target)
+		value = target.Value
+	}
+}
+
+// Dependents returns the secrets whose references lead to secret, directly
+// or through other references. A reference is resolved in the environment
+// it is read in, so one stored in base may reach a secret of any
+// environment.
+func (r *References) Dependents(secret generated.SecretList) ([]generated.SecretList, error) {
+	if r.all == nil {
+		stored, err := r.queries.GetAllSecrets(r.ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to fetch secrets: %w", err)
+		}
+		if r.all, err = r.cipher.OpenSecrets(stored); err != nil {
+			return nil, err
+		}
+	}
+
+	var dependents []generated.SecretList
+	found := map[string]bool{secret.ID: true}
+	queue := []generated.SecretList{secret}
+
+	for len(queue) > 0 {
+		target := queue[0]
+		queue = queue[1:]
+
+		for _, candidate := range r.all {
+			if found[candidate.ID] || !r.pointsAt(candidate, target) {
+				continue
+			}
+			found[candidate.ID] = true
+			dependents = append(dependents, candidate)
+			queue = append(queue, candidate)
+		}
+	}
+	return dependents, nil
+}
+
+func (r *References) pointsAt(candidate generated.SecretList, target generated.SecretList) bool {
+	ref, ok := utils.ParseReference(candidate.Value)
+	if !ok || utils.ToScreamingSnakeCase(ref.Key) != target.Key {
+		return false
+	}
+
+	project, err := r.project(ref.Project)
+	if err != nil || project.ID != target.ProjectID {
+		return false
+	}
+
+	return target.Environment == utils.BaseEnvironment ||
+		candidate.Environment == utils.BaseEnvironment ||
+		candidate.Environment == target.Environment
+}
+
+// lookup returns the effective secret a reference points at in environment
+func (r *References) lookup(ref utils.Reference, environment string) (generated.SecretList, error) {
+	project, err := r.project(ref.Project)
+	if err != nil {
+		return generated.SecretList{}, err
+	}
+	if r.canRead != nil && !r.canRead(project.ID) {
+		return generated.SecretList{}, ErrReferenceDenied
+	}
+
+	secrets, ok := r.byProject[project.ID]
+	if !ok {
+		stored, err := r.queries.GetSecretsByProjectID(r.ctx, project.ID)
+		if err != nil {
+			return generated.SecretList{}, fmt.Errorf("failed to fetch secrets of %s: %w", project.Name, err)
+		}
+		if secrets, err = r.cipher.OpenSecrets(stored); err != nil {
+			return generated.SecretList{}, err
+		}
+		r.byProject[project.ID] = secrets
+	}
+
+	key := utils.ToScreamingSnakeCase(ref.Key)
+	for _, secret := range utils.ResolveEnvironment(secrets, environment) {
+		if secret.Key == key {
+			return secret, nil
+		}
+	}
+	return generated.SecretList{}, fmt.Errorf("%s is not set in %s (environment %s)", key, project.Name, environment)
+}
+
+// project finds a project by ID or by name, normalised like project names
+func (r *References) project(name string) (generated.ProjectList, error) {
+	if r.projects == nil {
+		projects, err := r.queries.GetAllProjects(r.ctx)
+		if err != nil {
+			return generated.ProjectList{}, fmt.Errorf("failed to fetch projects: %w", err)
+		}
+		r.projects = projects
+	}
+
+	normalized := utils.ToScreamingSnakeCase(name)
+	for _, project := range r.projects {
+		if project.ID == name || project.Name == normalized {
+			return project, nil
+		}
+	}
+	return generated.ProjectList{}, fmt.Errorf("%w: %s", errNoProject, name)
+}
@@ -0,0 +1,222 @@
+package db_rw
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/Knightshrestha/Secret-Injector/audit"
+	"github.com/Knightshrestha/Secret-Injector/core/db_ro"
+	"github.com/Knightshrestha/Secret-Injector/database/generated"
+	"github.com/Knightshrestha/Secret-Injector/vault"
+)
+
+// MaxBatchOperations bounds a batch so a single request cannot hold the
+// write transaction for long. A batch that is refused as a whole, over the
+// limit or for a missing project, reports no results.
+const MaxBatchOperations = 500
+
+type BatchOp string
+
+const (
+	BatchCreate BatchOp = "create"
+	BatchUpdate BatchOp = "update"
+	BatchDelete BatchOp = "delete"
+)
+
+// BatchOperation is one change of a batch. Create uses Environment, Key,
+// Value, Description and Type, update changes the non-nil fields of the
+// secret ID and delete only needs ID, plus Force to remove a referenced
+// secret. Value is plaintext. When one operation fails, the ones before it
+// are reported rolled_back: they ran, but nothing of the batch is kept.
+type BatchOperation struct {
+	Op          BatchOp `json:"op"`
+	ID          string  `json:"id"`
+	Environment string  `json:"environment"`
+	Key         *string `json:"key"`
+	Value       *string `json:"value"`
+	Description *string `json:"description"`
+	Type        *string `json:"type"`
+	Force       bool    `json:"force"`
+}
+
+type BatchStatus string
+
+const (
+	BatchApplied    BatchStatus = "applied"
+	BatchFailed     BatchStatus = "failed"
+	BatchRolledBack BatchStatus = "rolled_back" // Succeeded, undone because a later operation failed
+	BatchNotRun     BatchStatus = "not_run"     // Came after the failed operation
+)
+
+// BatchResult is what happened to one operation. Secret is set for applied
+// operations, decrypted, and holds the removed secret for a delete.
+type BatchResult struct {
+	Index  int                   `json:"index"`
+	Op     BatchOp               `json:"op"`
+	Status BatchStatus           `json:"status"`
+	Secret *generated.SecretList `json:"secret,omitempty"`
+	Error  string                `json:"error,omitempty"`
+}
+
+// BatchError is returned when an operation fails the batch. Err is the
+// error the operation would have returned on its own.
+type BatchError struct {
+	Index int
+	Err   error
+}
+
+func (e *BatchError) Error() string {
+	return fmt.Sprintf("operation %d: %v", e.Index, e.Err)
+}
+
+func (e *BatchError) Unwrap() error {
+	return e.Err
+}
+
+// ApplySecretBatch applies the operations in order to the secrets of one
+// project in a single transaction. Either every operation is committed or
+// none is, the results tell what happened to each of them. When canRead is
+// not nil, a value referencing a project it does not allow fails the batch.
+// Every operation is audited as origin.
+func ApplySecretBatch(ctx context.Context, db *sql.DB, queries *generated.Queries, cipher *vault.Cipher, projectID string, operations []BatchOperation, canRead func(projectID string) bool, origin audit.Origin) ([]BatchResult, error) {
+	if projectID == "" {
+		return nil, invalid("Project ID is required")
+	}
+	if len(operations) == 0 {
+		return nil, invalid("At least one operation is required")
+	}
+	if len(operations) > MaxBatchOperations {
+		return nil, invalid(fmt.Sprintf("A batch can hold at most %d operations", MaxBatchOperations))
+	}
+
+	results := make([]BatchResult, len(operations))
+	for i, op := range operations {
+		results[i] = BatchResult{Index: i, Op: op.Op, Status: BatchNotRun}
+	}
+
+	txn, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer txn.Rollback()
+	queriesTx := queries.WithTx(txn)
+
+	if _, err := queriesTx.GetProjectByID(ctx, projectID); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, ErrProjectNotFound
+		}
+		return nil, fmt.Errorf("failed to verify project: %w", err)
+	}
+
+	var refs *db_ro.References
+	if canRead != nil {
+		refs = db_ro.NewReferences(ctx, queriesTx, cipher).Restrict(canRead)
+	}
+
+	for i, op := range operations {
+		secret, err := applyBatchOperation(ctx, queriesTx, cipher, projectID, op, refs, origin)
+		if err != nil {
+			for j := range i {
+				results[j].Status = BatchRolledBack
+				results[j].Secret = nil
+			}
+			results[i].Status = BatchFailed
+			return results, &BatchError{Index: i, Err: err}
+		}
+		results[i].Secret = &secret
+	}
+
+	if err := txn.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	for i := range results {
+		results[i].Status = BatchApplied
+	}
+	return results, nil
+}
+
+func applyBatchOperation(ctx context.Context, queriesTx *generated.Queries, cipher *vault.Cipher, projectID string, op BatchOperation, refs *db_ro.References, origin audit.Origin) (generated.SecretList, error) {
+	if refs != nil && op.Value != nil && op.Op != BatchDelete {
+		if err := refs.Check(*op.Value); err != nil {
+			return generated.SecretList{}, err
+		}
+	}
+
+	switch op.Op {
+	case BatchCreate:
+		if op.Key == nil {
+			return generated.SecretList{}, invalid("Secret key is required")
+		}
+		if op.Value == nil {
+			return generated.SecretList{}, invalid("Secret value is required")
+		}
+		var secretType string
+		if op.Type != nil {
+			secretType = *op.Type
+		}
+		return createSecretTx(ctx, queriesTx, cipher, generated.CreateSecretParams{
+			ProjectID:   projectID,
+			Environment: op.Environment,
+			Key:         *op.Key,
+			Value:       *op.Value,
+			Description: op.Description,
+			Type:        secretType,
+		}, origin)
+
+	case BatchUpdate:
+		if _, err := secretInProject(ctx, queriesTx, projectID, op.ID); err != nil {
+			return generated.SecretList{}, err
+		}
+		secret, err := updateSecretTx(ctx, queriesTx, cipher, generated.UpdateSecretParams{
+			ID:          op.ID,
+			Key:         op.Key,
+			Value:       op.Value,
+			Description: op.Description,
+			Type:        op.Type,
+		}, origin)
+		if err != nil {
+			return generated.SecretList{}, err
+		}
+		return cipher.OpenSecret(secret)
+
+	case BatchDelete:
+		secret, err := secretInProject(ctx, queriesTx, projectID, op.ID)
+		if err != nil {
+			return generated.SecretList{}, err
+		}
+		if !op.Force {
+			if err := checkDependents(ctx, queriesTx, cipher, secret); err != nil {
+				return generated.SecretList{}, err
+			}
+		}
+		if err := deleteSecretTx(ctx, queriesTx, secret, origin); err != nil {
+			return generated.SecretList{}, err
+		}
+		return cipher.OpenSecret(secret)
+
+	default:
+		return generated.SecretList{}, invalid(fmt.Sprintf("Unknown operation %q (expected create, update or delete)", op.Op))
+	}
+}
+
+// secretInProject fetches a secret, treating one of another project as
+// missing
+func secretInProject(ctx context.Context, queries *generated.Queries, projectID string, secretID string) (generated.SecretList, error) {
+	if secretID == "" {
+		return generated.SecretList{}, invalid("Secret ID is required")
+	}
+
+	secret, err := queries.GetSecretByID(ctx, secretID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return generated.SecretList{}, ErrSecretNotFound
+		}
+		return generated.SecretList{}, fmt.Errorf("failed to fetch secret: %w", err)
+	}
+	if secret.ProjectID != projectID {
+		return generated.SecretList{}, ErrSecretNotFound
+	}
+	return secret, nil
+}
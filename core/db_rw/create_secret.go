@@ -0,0 +1,101 @@
+package db_rw
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"github.com/Knightshrestha/Secret-Injector/audit"
+	"github.com/Knightshrestha/Secret-Injector/database/generated"
+	"github.com/Knightshrestha/Secret-Injector/utils"
+	"github.com/Knightshrestha/Secret-Injector/vault"
+	"github.com/google/uuid"
+)
+
+// CreateSecret validates and normalises a new secret and stores it sealed,
+// audited as origin. params.Value is plaintext and params.ID is assigned
+// here. The returned secret is decrypted.
+func CreateSecret(ctx context.Context, db *sql.DB, queries *generated.Queries, cipher *vault.Cipher, params generated.CreateSecretParams, origin audit.Origin) (generated.SecretList, error) {
+	txn, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return generated.SecretList{}, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer txn.Rollback()
+
+	secret, err := createSecretTx(ctx, queries.WithTx(txn), cipher, params, origin)
+	if err != nil {
+		return generated.SecretList{}, err
+	}
+
+	if err := txn.Commit(); err != nil {
+		return generated.SecretList{}, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return secret, nil
+}
+
+// createSecretTx is CreateSecret inside the caller's transaction
+func createSecretTx(ctx context.Context, queriesTx *generated.Queries, cipher *vault.Cipher, params generated.CreateSecretParams, origin audit.Origin) (generated.SecretList, error) {
+	if strings.TrimSpace(params.ProjectID) == "" {
+		return generated.SecretList{}, invalid("Project ID is required")
+	}
+	if strings.TrimSpace(params.Key) == "" {
+		return generated.SecretList{}, invalid("Secret key is required")
+	}
+	if strings.TrimSpace(params.Value) == "" {
+		return generated.SecretList{}, invalid("Secret value is required")
+	}
+
+	environment, err := utils.NormalizeEnvironment(params.Environment)
+	if err != nil {
+		return generated.SecretList{}, invalid(err.Error())
+	}
+
+	secretType, err := utils.NormalizeSecretType(params.Type)
+	if err != nil {
+		return generated.SecretList{}, invalid(err.Error())
+	}
+
+	if _, err := queriesTx.GetProjectByID(ctx, params.ProjectID); err != nil {
+		if err == sql.ErrNoRows {
+			return generated.SecretList{}, ErrProjectNotFound
+		}
+		return generated.SecretList{}, fmt.Errorf("failed to verify project: %w", err)
+	}
+
+	plaintext := params.Value
+	params.ID = uuid.New().String()
+	params.Environment = environment
+	params.Type = secretType
+	params.Key = utils.ToScreamingSnakeCase(params.Key)
+
+	if secretType == utils.SecretTypeFile {
+		if err := utils.CheckFileKey(params.Key); err != nil {
+			return generated.SecretList{}, invalid(err.Error())
+		}
+	}
+
+	params.Value, err = cipher.Seal(params.ID, plaintext)
+	if err != nil {
+		return generated.SecretList{}, fmt.Errorf("failed to encrypt secret: %w", err)
+	}
+
+	secret, err := queriesTx.CreateSecret(ctx, params)
+	if err != nil {
+		if strings.Contains(err.Error(), "UNIQUE constraint failed") {
+			return generated.SecretList{}, ErrDuplicateKey
+		}
+		if strings.Contains(err.Error(), "FOREIGN KEY constraint failed") {
+			return generated.SecretList{}, ErrProjectNotFound
+		}
+		return generated.SecretList{}, fmt.Errorf("failed to create secret: %w", err)
+	}
+
+	if err := audit.RecordTx(ctx, queriesTx, origin.SecretEntry(audit.ActionCreate, secret)); err != nil {
+		return generated.SecretList{}, err
+	}
+
+	// Return the plaintext that was just submitted
+	secret.Value = plaintext
+	return secret, nil
+}
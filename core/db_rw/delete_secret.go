@@ -0,0 +1,83 @@
+package db_rw
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/Knightshrestha/Secret-Injector/audit"
+	"github.com/Knightshrestha/Secret-Injector/core/db_ro"
+	"github.com/Knightshrestha/Secret-Injector/database/generated"
+	"github.com/Knightshrestha/Secret-Injector/vault"
+)
+
+// DeleteSecret removes a secret, audited as origin. Its state at deletion
+// becomes a last version and the secret a deleted secret, so it can be
+// restored with its history. A secret other secrets reference is only removed with force,
+// its dependents are left broken.
+func DeleteSecret(ctx context.Context, db *sql.DB, queries *generated.Queries, cipher *vault.Cipher, secretID string, force bool, origin audit.Origin) error {
+	txn, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer txn.Rollback()
+	queriesTx := queries.WithTx(txn)
+
+	secret, err := queriesTx.GetSecretByID(ctx, secretID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return ErrSecretNotFound
+		}
+		return fmt.Errorf("failed to fetch secret: %w", err)
+	}
+	if !force {
+		if err := checkDependents(ctx, queriesTx, cipher, secret); err != nil {
+			return err
+		}
+	}
+
+	if err := deleteSecretTx(ctx, queriesTx, secret, origin); err != nil {
+		return err
+	}
+
+	return txn.Commit()
+}
+
+// checkDependents fails with a SecretReferencedError when other secrets
+// reference secret
+func checkDependents(ctx context.Context, queriesTx *generated.Queries, cipher *vault.Cipher, secret generated.SecretList) error {
+	dependents, err := db_ro.NewReferences(ctx, queriesTx, cipher).Dependents(secret)
+	if err != nil {
+		return fmt.Errorf("failed to look up references: %w", err)
+	}
+	if len(dependents) > 0 {
+		return &SecretReferencedError{Dependents: dependents}
+	}
+	return nil
+}
+
+// deleteSecretTx is DeleteSecret inside the caller's transaction
+func deleteSecretTx(ctx context.Context, queriesTx *generated.Queries, secret generated.SecretList, origin audit.Origin) error {
+	version, err := snapshotSecret(ctx, queriesTx, secret.ID, origin.Actor)
+	if err != nil {
+		return err
+	}
+
+	if err := queriesTx.DeleteSecret(ctx, secret.ID); err != nil {
+		return fmt.Errorf("failed to delete secret: %w", err)
+	}
+
+	err = queriesTx.CreateDeletedSecret(ctx, generated.CreateDeletedSecretParams{
+		ID:          secret.ID,
+		ProjectID:   secret.ProjectID,
+		Environment: secret.Environment,
+		Key:         secret.Key,
+		Version:     version,
+		Actor:       origin.Actor,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to record deleted secret: %w", err)
+	}
+
+	return audit.RecordTx(ctx, queriesTx, origin.SecretEntry(audit.ActionDelete, secret))
+}
@@ -0,0 +1,47 @@
+package db_rw
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/Knightshrestha/Secret-Injector/database/generated"
+)
+
+var (
+	ErrSecretNotFound   = errors.New("secret not found")
+	ErrVersionNotFound  = errors.New("secret version not found")
+	ErrDeletedNotFound  = errors.New("deleted secret not found")
+	ErrDuplicateKey     = errors.New("secret with this name already exists in the environment")
+	ErrProjectNotFound  = errors.New("project not found")
+	ErrDuplicateProject = errors.New("project with this name already exists")
+	ErrImportConflict   = errors.New("import would change existing secrets")
+	ErrSecretReferenced = errors.New("secret is referenced by other secrets")
+)
+
+// InvalidInputError is a change rejected by validation. Its message is meant
+// for the user, the HTTP handlers answer it with 400.
+type InvalidInputError struct {
+	Message string
+}
+
+func (e *InvalidInputError) Error() string {
+	return e.Message
+}
+
+func invalid(message string) error {
+	return &InvalidInputError{Message: message}
+}
+
+// SecretReferencedError refuses to delete a secret other secrets still
+// reference. Dependents are decrypted, their values are the references.
+type SecretReferencedError struct {
+	Dependents []generated.SecretList
+}
+
+func (e *SecretReferencedError) Error() string {
+	return fmt.Sprintf("%v (%d)", ErrSecretReferenced, len(e.Dependents))
+}
+
+func (e *SecretReferencedError) Is(target error) bool {
+	return target == ErrSecretReferenced
+}
@@ -0,0 +1,246 @@
+package db_rw
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"github.com/Knightshrestha/Secret-Injector/audit"
+	"github.com/Knightshrestha/Secret-Injector/database/generated"
+	"github.com/Knightshrestha/Secret-Injector/importer"
+	"github.com/Knightshrestha/Secret-Injector/utils"
+	"github.com/Knightshrestha/Secret-Injector/vault"
+)
+
+// ConflictPolicy decides what an import does with keys that already hold a
+// different value
+type ConflictPolicy string
+
+const (
+	ConflictSkip      ConflictPolicy = "skip"
+	ConflictOverwrite ConflictPolicy = "overwrite"
+	ConflictFail      ConflictPolicy = "fail"
+)
+
+// ParseConflictPolicy validates a conflict policy, empty means skip
+func ParseConflictPolicy(s string) (ConflictPolicy, error) {
+	switch policy := ConflictPolicy(strings.ToLower(s)); policy {
+	case "":
+		return ConflictSkip, nil
+	case ConflictSkip, ConflictOverwrite, ConflictFail:
+		return policy, nil
+	}
+	return "", invalid(fmt.Sprintf("Unknown conflict policy %q (expected skip, overwrite or fail)", s))
+}
+
+type ImportAction string
+
+const (
+	ImportAdd       ImportAction = "add"
+	ImportChange    ImportAction = "change"
+	ImportUnchanged ImportAction = "unchanged"
+	ImportSkip      ImportAction = "skip"     // Changed value kept by the skip policy
+	ImportConflict  ImportAction = "conflict" // Changed value refused by the fail policy
+)
+
+// ImportItem is what an import does with one key
+type ImportItem struct {
+	Key    string       `json:"key"`
+	Action ImportAction `json:"action"`
+	ID     string       `json:"id,omitempty"` // Empty for keys a dry run would add
+}
+
+type ImportParams struct {
+	ProjectID   string
+	Environment string
+	Entries     []importer.Entry
+	Policy      ConflictPolicy
+	DryRun      bool
+}
+
+type ImportResult struct {
+	ProjectID   string       `json:"project_id"`
+	Environment string       `json:"environment"`
+	DryRun      bool         `json:"dry_run"`
+	Added       int          `json:"added"`
+	Changed     int          `json:"changed"`
+	Unchanged   int          `json:"unchanged"`
+	Skipped     int          `json:"skipped"`
+	Conflicts   int          `json:"conflicts"`
+	Items       []ImportItem `json:"items"`
+
+	// Created and Updated hold the decrypted secrets that were written
+	Created []generated.SecretList `json:"-"`
+	Updated []generated.SecretList `json:"-"`
+}
+
+// ImportSecrets compares the entries with the secrets stored in the
+// environment and, unless it is a dry run, adds and changes them in a single
+// transaction audited as origin. With the fail policy nothing is written
+// when a key would change, the returned result still shows the diff.
+func ImportSecrets(ctx context.Context, db *sql.DB, queries *generated.Queries, cipher *vault.Cipher, params ImportParams, origin audit.Origin) (ImportResult, error) {
+	if strings.TrimSpace(params.ProjectID) == "" {
+		return ImportResult{}, invalid("Project ID is required")
+	}
+	environment, err := utils.NormalizeEnvironment(params.Environment)
+	if err != nil {
+		return ImportResult{}, invalid(err.Error())
+	}
+	policy, err := ParseConflictPolicy(string(params.Policy))
+	if err != nil {
+		return ImportResult{}, err
+	}
+	entries, err := normalizeEntries(params.Entries)
+	if err != nil {
+		return ImportResult{}, err
+	}
+
+	result := ImportResult{
+		ProjectID:   params.ProjectID,
+		Environment: environment,
+		DryRun:      params.DryRun,
+		Items:       []ImportItem{},
+	}
+
+	txn, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return ImportResult{}, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer txn.Rollback()
+	queriesTx := queries.WithTx(txn)
+
+	if _, err := queriesTx.GetProjectByID(ctx, params.ProjectID); err != nil {
+		if err == sql.ErrNoRows {
+			return ImportResult{}, ErrProjectNotFound
+		}
+		return ImportResult{}, fmt.Errorf("failed to verify project: %w", err)
+	}
+
+	stored, err := queriesTx.GetSecretsByProjectID(ctx, params.ProjectID)
+	if err != nil {
+		return ImportResult{}, fmt.Errorf("failed to fetch secrets: %w", err)
+	}
+	existing := make(map[string]generated.SecretList)
+	for _, secret := range stored {
+		if secret.Environment == environment {
+			existing[secret.Key] = secret
+		}
+	}
+
+	var conflicts []string
+	for _, entry := range entries {
+		item := ImportItem{Key: entry.Key, Action: ImportAdd}
+
+		if secret, ok := existing[entry.Key]; ok {
+			item.ID = secret.ID
+			current, err := cipher.Open(secret.ID, secret.Value)
+			if err != nil {
+				return ImportResult{}, fmt.Errorf("failed to decrypt %s: %w", secret.Key, err)
+			}
+
+			switch {
+			case current == entry.Value:
+				item.Action = ImportUnchanged
+			case policy == ConflictOverwrite:
+				item.Action = ImportChange
+			case policy == ConflictFail:
+				item.Action = ImportConflict
+				conflicts = append(conflicts, entry.Key)
+			default:
+				item.Action = ImportSkip
+			}
+		}
+
+		result.Items = append(result.Items, item)
+	}
+
+	if len(conflicts) > 0 {
+		countItems(&result)
+		return result, fmt.Errorf("%w: %s", ErrImportConflict, strings.Join(conflicts, ", "))
+	}
+
+	if !params.DryRun {
+		for i, entry := range entries {
+			item := &result.Items[i]
+			switch item.Action {
+			case ImportAdd:
+				secret, err := createSecretTx(ctx, queriesTx, cipher, generated.CreateSecretParams{
+					ProjectID:   params.ProjectID,
+					Environment: environment,
+					Key:         entry.Key,
+					Value:       entry.Value,
+				}, origin)
+				if err != nil {
+					return ImportResult{}, fmt.Errorf("%s: %w", entry.Key, err)
+				}
+				item.ID = secret.ID
+				result.Created = append(result.Created, secret)
+
+			case ImportChange:
+				value := entry.Value
+				secret, err := updateSecretTx(ctx, queriesTx, cipher, generated.UpdateSecretParams{
+					ID:    item.ID,
+					Value: &value,
+				}, origin)
+				if err != nil {
+					return ImportResult{}, fmt.Errorf("%s: %w", entry.Key, err)
+				}
+				secret.Value = entry.Value
+				result.Updated = append(result.Updated, secret)
+			}
+		}
+
+		if err := txn.Commit(); err != nil {
+			return ImportResult{}, fmt.Errorf("failed to commit transaction: %w", err)
+		}
+	}
+
+	countItems(&result)
+	return result, nil
+}
+
+// normalizeEntries validates the entries and normalises their keys. Keys
+// that only differ before normalisation are merged, the later value wins.
+func normalizeEntries(entries []importer.Entry) ([]importer.Entry, error) {
+	if len(entries) == 0 {
+		return nil, invalid("No secrets to import")
+	}
+
+	index := make(map[string]int)
+	var normalized []importer.Entry
+	for _, entry := range entries {
+		if strings.TrimSpace(entry.Key) == "" {
+			return nil, invalid("Secret key cannot be empty")
+		}
+		key := utils.ToScreamingSnakeCase(entry.Key)
+		if strings.TrimSpace(entry.Value) == "" {
+			return nil, invalid(fmt.Sprintf("Secret value of %s cannot be empty", key))
+		}
+
+		if i, ok := index[key]; ok {
+			normalized[i].Value = entry.Value
+			continue
+		}
+		index[key] = len(normalized)
+		normalized = append(normalized, importer.Entry{Key: key, Value: entry.Value})
+	}
+	return normalized, nil
+}
+
+func countItems(result *ImportResult) {
+	for _, item := range result.Items {
+		switch item.Action {
+		case ImportAdd:
+			result.Added++
+		case ImportChange:
+			result.Changed++
+		case ImportUnchanged:
+			result.Unchanged++
+		case ImportSkip:
+			result.Skipped++
+		case ImportConflict:
+			result.Conflicts++
+		}
+	}
+}
@@ -0,0 +1,128 @@
+package db_rw
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"github.com/Knightshrestha/Secret-Injector/audit"
+	"github.com/Knightshrestha/Secret-Injector/database/generated"
+	"github.com/Knightshrestha/Secret-Injector/utils"
+	"github.com/google/uuid"
+)
+
+// CreateProject validates and normalises the name and creates the project,
+// audited as origin
+func CreateProject(ctx context.Context, db *sql.DB, queries *generated.Queries, name string, description *string, origin audit.Origin) (generated.ProjectList, error) {
+	if strings.TrimSpace(name) == "" {
+		return generated.ProjectList{}, invalid("Project name is required")
+	}
+
+	txn, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return generated.ProjectList{}, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer txn.Rollback()
+	queriesTx := queries.WithTx(txn)
+
+	project, err := queriesTx.CreateProject(ctx, generated.CreateProjectParams{
+		ID:          uuid.New().String(),
+		Name:        utils.ToScreamingSnakeCase(name),
+		Description: description,
+	})
+	if err != nil {
+		if strings.Contains(err.Error(), "UNIQUE constraint failed") {
+			return generated.ProjectList{}, ErrDuplicateProject
+		}
+		return generated.ProjectList{}, fmt.Errorf("failed to create project: %w", err)
+	}
+
+	if err := audit.RecordTx(ctx, queriesTx, origin.ProjectEntry(audit.ActionCreate, project.ID)); err != nil {
+		return generated.ProjectList{}, err
+	}
+
+	if err := txn.Commit(); err != nil {
+		return generated.ProjectList{}, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return project, nil
+}
+
+// UpdateProject renames a project or changes its description, audited as
+// origin. nil fields are kept.
+func UpdateProject(ctx context.Context, db *sql.DB, queries *generated.Queries, params generated.UpdateProjectParams, origin audit.Origin) (generated.ProjectList, error) {
+	if params.Name == nil && params.Description == nil {
+		return generated.ProjectList{}, invalid("At least one field (name or description) must be provided")
+	}
+	if params.Name != nil && strings.TrimSpace(*params.Name) == "" {
+		return generated.ProjectList{}, invalid("Project name cannot be empty")
+	}
+	if params.Description != nil && strings.TrimSpace(*params.Description) == "" {
+		return generated.ProjectList{}, invalid("Project description cannot be empty")
+	}
+	params.Name = utils.ToScreamingSnakeCasePtr(params.Name)
+
+	txn, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return generated.ProjectList{}, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer txn.Rollback()
+	queriesTx := queries.WithTx(txn)
+
+	project, err := queriesTx.UpdateProject(ctx, params)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return generated.ProjectList{}, ErrProjectNotFound
+		}
+		if strings.Contains(err.Error(), "UNIQUE constraint failed") {
+			return generated.ProjectList{}, ErrDuplicateProject
+		}
+		return generated.ProjectList{}, fmt.Errorf("failed to update project: %w", err)
+	}
+
+	if err := audit.RecordTx(ctx, queriesTx, origin.ProjectEntry(audit.ActionUpdate, project.ID)); err != nil {
+		return generated.ProjectList{}, err
+	}
+
+	if err := txn.Commit(); err != nil {
+		return generated.ProjectList{}, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return project, nil
+}
+
+// DeleteProject removes a project with its secrets, deleted secrets, their
+// history and the token grants pointing at it, audited as origin
+func DeleteProject(ctx context.Context, db *sql.DB, queries *generated.Queries, projectID string, origin audit.Origin) error {
+	txn, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer txn.Rollback()
+	queriesTx := queries.WithTx(txn)
+
+	// Delete the history of the secrets, then the secrets
+	if err := queriesTx.DeleteSecretVersionsInProject(ctx, projectID); err != nil {
+		return fmt.Errorf("failed to delete secret versions: %w", err)
+	}
+	if err := queriesTx.DeleteDeletedSecretsInProject(ctx, projectID); err != nil {
+		return fmt.Errorf("failed to delete deleted secrets: %w", err)
+	}
+	if err := queriesTx.DeleteAllSecretsInProjects(ctx, projectID); err != nil {
+		return fmt.Errorf("failed to delete secrets: %w", err)
+	}
+
+	// Drop the project from token project lists
+	if err := queriesTx.DeleteApiTokenProjectsByProject(ctx, projectID); err != nil {
+		return fmt.Errorf("failed to delete token projects: %w", err)
+	}
+
+	if err := queriesTx.DeleteProject(ctx, projectID); err != nil {
+		return fmt.Errorf("failed to delete project: %w", err)
+	}
+
+	if err := audit.RecordTx(ctx, queriesTx, origin.ProjectEntry(audit.ActionDelete, projectID)); err != nil {
+		return err
+	}
+
+	return txn.Commit()
+}
@@ -0,0 +1,51 @@
+package db_rw
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"github.com/Knightshrestha/Secret-Injector/audit"
+	"github.com/Knightshrestha/Secret-Injector/database/generated"
+	"github.com/Knightshrestha/Secret-Injector/vault"
+)
+
+// RestoreSecret brings a deleted secret back under its old ID, with the
+// state it had when it was deleted and its version history. It is audited as
+// origin. The returned secret is decrypted.
+func RestoreSecret(ctx context.Context, db *sql.DB, queries *generated.Queries, cipher *vault.Cipher, secretID string, origin audit.Origin) (generated.SecretList, error) {
+	txn, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return generated.SecretList{}, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer txn.Rollback()
+	queriesTx := queries.WithTx(txn)
+
+	// Versions are sealed against the secret ID, so the value is restored
+	// without decrypting it
+	secret, err := queriesTx.RestoreDeletedSecret(ctx, secretID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return generated.SecretList{}, ErrDeletedNotFound
+		}
+		if strings.Contains(err.Error(), "UNIQUE constraint failed") {
+			return generated.SecretList{}, ErrDuplicateKey
+		}
+		return generated.SecretList{}, fmt.Errorf("failed to restore secret: %w", err)
+	}
+
+	if err := queriesTx.DeleteDeletedSecret(ctx, secretID); err != nil {
+		return generated.SecretList{}, fmt.Errorf("failed to delete deleted secret: %w", err)
+	}
+
+	if err := audit.RecordTx(ctx, queriesTx, origin.SecretEntry(audit.ActionRestore, secret)); err != nil {
+		return generated.SecretList{}, err
+	}
+
+	if err := txn.Commit(); err != nil {
+		return generated.SecretList{}, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return cipher.OpenSecret(secret)
+}
@@ -0,0 +1,69 @@
+package db_rw
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"github.com/Knightshrestha/Secret-Injector/audit"
+	"github.com/Knightshrestha/Secret-Injector/database/generated"
+	"github.com/Knightshrestha/Secret-Injector/vault"
+)
+
+// RollbackSecret restores the key, value, description and type a secret had
+// at the given version. The state being replaced becomes a new version, so a
+// rollback can itself be rolled back. It is audited as origin. The returned
+// secret is decrypted.
+func RollbackSecret(ctx context.Context, db *sql.DB, queries *generated.Queries, cipher *vault.Cipher, secretID string, version int64, origin audit.Origin) (generated.SecretList, error) {
+	txn, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return generated.SecretList{}, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer txn.Rollback()
+	queriesTx := queries.WithTx(txn)
+
+	target, err := queriesTx.GetSecretVersion(ctx, generated.GetSecretVersionParams{
+		SecretID: secretID,
+		Version:  version,
+	})
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return generated.SecretList{}, ErrVersionNotFound
+		}
+		return generated.SecretList{}, fmt.Errorf("failed to fetch secret version: %w", err)
+	}
+
+	if _, err := snapshotSecret(ctx, queriesTx, secretID, origin.Actor); err != nil {
+		return generated.SecretList{}, err
+	}
+
+	// Versions are sealed against the secret ID, so the value is restored
+	// without decrypting it
+	secret, err := queriesTx.RestoreSecret(ctx, generated.RestoreSecretParams{
+		Key:         target.Key,
+		Value:       target.Value,
+		Description: target.Description,
+		Type:        target.Type,
+		ID:          secretID,
+	})
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return generated.SecretList{}, ErrSecretNotFound
+		}
+		if strings.Contains(err.Error(), "UNIQUE constraint failed") {
+			return generated.SecretList{}, ErrDuplicateKey
+		}
+		return generated.SecretList{}, fmt.Errorf("failed to restore secret: %w", err)
+	}
+
+	if err := audit.RecordTx(ctx, queriesTx, origin.SecretEntry(audit.ActionRollback, secret)); err != nil {
+		return generated.SecretList{}, err
+	}
+
+	if err := txn.Commit(); err != nil {
+		return generated.SecretList{}, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return cipher.OpenSecret(secret)
+}
@@ -0,0 +1,111 @@
+package db_rw
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"github.com/Knightshrestha/Secret-Injector/audit"
+	"github.com/Knightshrestha/Secret-Injector/database/generated"
+	"github.com/Knightshrestha/Secret-Injector/utils"
+	"github.com/Knightshrestha/Secret-Injector/vault"
+	"github.com/google/uuid"
+)
+
+// UpdateSecret stores the current state of a secret as a new version and
+// applies the changes, audited as origin. params.Value is plaintext, nil
+// fields are kept. The returned secret is decrypted.
+func UpdateSecret(ctx context.Context, db *sql.DB, queries *generated.Queries, cipher *vault.Cipher, params generated.UpdateSecretParams, origin audit.Origin) (generated.SecretList, error) {
+	txn, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return generated.SecretList{}, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer txn.Rollback()
+
+	secret, err := updateSecretTx(ctx, queries.WithTx(txn), cipher, params, origin)
+	if err != nil {
+		return generated.SecretList{}, err
+	}
+
+	if err := txn.Commit(); err != nil {
+		return generated.SecretList{}, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return cipher.OpenSecret(secret)
+}
+
+// updateSecretTx is UpdateSecret inside the caller's transaction, the
+// returned secret is still sealed
+func updateSecretTx(ctx context.Context, queriesTx *generated.Queries, cipher *vault.Cipher, params generated.UpdateSecretParams, origin audit.Origin) (generated.SecretList, error) {
+	if params.Key == nil && params.Value == nil && params.Description == nil && params.Type == nil {
+		return generated.SecretList{}, invalid("At least one field (key or value or description or type) must be provided")
+	}
+	if params.Key != nil && strings.TrimSpace(*params.Key) == "" {
+		return generated.SecretList{}, invalid("Secret key cannot be empty")
+	}
+	if params.Value != nil && strings.TrimSpace(*params.Value) == "" {
+		return generated.SecretList{}, invalid("Secret value cannot be empty")
+	}
+	params.Key = utils.ToScreamingSnakeCasePtr(params.Key)
+
+	if params.Type != nil {
+		secretType, err := utils.NormalizeSecretType(*params.Type)
+		if err != nil {
+			return generated.SecretList{}, invalid(err.Error())
+		}
+		params.Type = &secretType
+	}
+
+	if params.Value != nil {
+		sealed, err := cipher.Seal(params.ID, *params.Value)
+		if err != nil {
+			return generated.SecretList{}, fmt.Errorf("failed to encrypt secret: %w", err)
+		}
+		params.Value = &sealed
+	}
+
+	if _, err := snapshotSecret(ctx, queriesTx, params.ID, origin.Actor); err != nil {
+		return generated.SecretList{}, err
+	}
+
+	secret, err := queriesTx.UpdateSecret(ctx, params)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return generated.SecretList{}, ErrSecretNotFound
+		}
+		if strings.Contains(err.Error(), "UNIQUE constraint failed") {
+			return generated.SecretList{}, ErrDuplicateKey
+		}
+		return generated.SecretList{}, fmt.Errorf("failed to update secret: %w", err)
+	}
+
+	// The key and type may change separately, check where they end up
+	if secret.Type == utils.SecretTypeFile {
+		if err := utils.CheckFileKey(secret.Key); err != nil {
+			return generated.SecretList{}, invalid(err.Error())
+		}
+	}
+
+	if err := audit.RecordTx(ctx, queriesTx, origin.SecretEntry(audit.ActionUpdate, secret)); err != nil {
+		return generated.SecretList{}, err
+	}
+	return secret, nil
+}
+
+// snapshotSecret copies the current row of a secret into secret_versions and
+// returns the version it became
+func snapshotSecret(ctx context.Context, queries *generated.Queries, secretID string, actor string) (int64, error) {
+	version, err := queries.SnapshotSecretVersion(ctx, generated.SnapshotSecretVersionParams{
+		ID:       uuid.New().String(),
+		SecretID: secretID,
+		Actor:    actor,
+	})
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return 0, ErrSecretNotFound
+		}
+		return 0, fmt.Errorf("failed to save secret version: %w", err)
+	}
+	return version.Version, nil
+}
@@ -0,0 +1,46 @@
+package core
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/Knightshrestha/Secret-Injector/auth"
+	"github.com/Knightshrestha/Secret-Injector/database"
+)
+
+// EnsureAdminToken creates the admin token on first start and prints it
+// once, together with a login link for the UI when the server is reachable
+// over TCP
+func EnsureAdminToken(db database.CustomDB, baseURL string) error {
+	ctx := context.Background()
+
+	count, err := db.WriteQueries.CountApiTokens(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to count API tokens: %w", err)
+	}
+	if count > 0 {
+		return nil
+	}
+
+	token, _, err := auth.CreateToken(ctx, db.WriteDB, db.WriteQueries, auth.TokenParams{
+		Name:  auth.AdminTokenName,
+		Scope: auth.ScopeAdmin,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to create admin token: %w", err)
+	}
+
+	fmt.Println("──────────────────────────────────────────────────────────────")
+	fmt.Println("First start: an admin API token was created. It is shown only")
+	fmt.Println("once, store it somewhere safe.")
+	fmt.Println()
+	fmt.Println("  Token:", token)
+	if baseURL != "" {
+		fmt.Printf("  UI:    %s/ui?token=%s\n", baseURL, token)
+	}
+	fmt.Println()
+	fmt.Println("Send it as `Authorization: Bearer <token>`. Manage tokens with")
+	fmt.Println("`secret_injector token`.")
+	fmt.Println("──────────────────────────────────────────────────────────────")
+	return nil
+}
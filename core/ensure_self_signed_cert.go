@@ -0,0 +1,135 @@
+package core
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"fmt"
+	"math/big"
+	"net"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/Knightshrestha/Secret-Injector/database"
+)
+
+const selfSignedLifetime = 365 * 24 * time.Hour
+
+// EnsureSelfSignedCert returns the paths of a self-signed certificate and
+// key stored in tls/ next to the database. A new pair is generated when none exists yet,
+// when it expires within a week or when it does not cover bindHost
+func EnsureSelfSignedCert(bindHost string) (certFile, keyFile string, err error) {
+	dataDir, err := database.DataDir()
+	if err != nil {
+		return "", "", fmt.Errorf("cannot get data directory: %w", err)
+	}
+
+	tlsDir := filepath.Join(dataDir, "tls")
+	if err := os.MkdirAll(tlsDir, 0700); err != nil {
+		return "", "", fmt.Errorf("cannot create %s: %w", tlsDir, err)
+	}
+
+	certFile = filepath.Join(tlsDir, "cert.pem")
+	keyFile = filepath.Join(tlsDir, "key.pem")
+	hosts := certHosts(bindHost)
+
+	if selfSignedCertValid(certFile, keyFile, hosts) {
+		return certFile, keyFile, nil
+	}
+
+	if err := generateSelfSignedCert(certFile, keyFile, hosts); err != nil {
+		return "", "", err
+	}
+	fmt.Printf("✓ Generated self-signed TLS certificate at %s\n", certFile)
+
+	return certFile, keyFile, nil
+}
+
+// certHosts lists the names the certificate is issued for: always the
+// loopback names, plus the bind host when it is a specific address
+func certHosts(bindHost string) []string {
+	hosts := []string{"localhost", "127.0.0.1", "::1"}
+	if bindHost == "" || bindHost == "0.0.0.0" || bindHost == "::" {
+		return hosts
+	}
+	for _, h := range hosts {
+		if h == bindHost {
+			return hosts
+		}
+	}
+	return append(hosts, bindHost)
+}
+
+func selfSignedCertValid(certFile, keyFile string, hosts []string) bool {
+	pair, err := tls.LoadX509KeyPair(certFile, keyFile)
+	if err != nil {
+		return false
+	}
+
+	cert, err := x509.ParseCertificate(pair.Certificate[0])
+	if err != nil {
+		return false
+	}
+	if time.Now().Add(7 * 24 * time.Hour).After(cert.NotAfter) {
+		return false
+	}
+	for _, h := range hosts {
+		if cert.VerifyHostname(h) != nil {
+			return false
+		}
+	}
+	return true
+}
+
+func generateSelfSignedCert(certFile, keyFile string, hosts []string) error {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return fmt.Errorf("cannot generate TLS key: %w", err)
+	}
+
+	serial, err := rand.Int(rand.Reader, new(big.Int).Lsh(big.NewInt(1), 128))
+	if err != nil {
+		return fmt.Errorf("cannot generate certificate serial: %w", err)
+	}
+
+	now := time.Now()
+	template := x509.Certificate{
+		SerialNumber:          serial,
+		Subject:               pkix.Name{CommonName: "Secret Injector"},
+		NotBefore:             now.Add(-time.Hour),
+		NotAfter:              now.Add(selfSignedLifetime),
+		KeyUsage:              x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+		BasicConstraintsValid: true,
+	}
+	for _, h := range hosts {
+		if ip := net.ParseIP(h); ip != nil {
+			template.IPAddresses = append(template.IPAddresses, ip)
+		} else {
+			template.DNSNames = append(template.DNSNames, h)
+		}
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, &template, &template, &key.PublicKey, key)
+	if err != nil {
+		return fmt.Errorf("cannot create certificate: %w", err)
+	}
+
+	keyDer, err := x509.MarshalPKCS8PrivateKey(key)
+	if err != nil {
+		return fmt.Errorf("cannot encode TLS key: %w", err)
+	}
+
+	if err := os.WriteFile(keyFile, pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: keyDer}), 0600); err != nil {
+		return fmt.Errorf("cannot write %s: %w", keyFile, err)
+	}
+	if err := os.WriteFile(certFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), 0644); err != nil {
+		return fmt.Errorf("cannot write %s: %w", certFile, err)
+	}
+	return nil
+}
@@ -0,0 +1,136 @@
+package core
+
+import (
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"strconv"
+	"time"
+)
+
+// ServerOptions configures where and how `serve` listens
+type ServerOptions struct {
+	Port    int
+	Bind    string
+	Logging bool
+
+	// TLSCert and TLSKey serve HTTPS with the given certificate files
+	TLSCert string
+	TLSKey  string
+
+	// SelfSigned serves HTTPS with a certificate generated in tls/ next to
+	// the database
+	SelfSigned bool
+
+	// Socket listens on a Unix domain socket instead of a TCP port
+	Socket string
+
+	// CORSOrigins may call the API from a browser, e.g. the Vite dev server
+	CORSOrigins []string
+
+	// BackupInterval writes a backup next to the database this often, 0
+	// disables it. Only the newest BackupKeep of them are kept.
+	BackupInterval time.Duration
+	BackupKeep     int
+}
+
+// TLS reports whether the server speaks HTTPS
+func (o ServerOptions) TLS() bool {
+	return o.SelfSigned || o.TLSCert != ""
+}
+
+// URL is the base address clients use to reach the server, empty when it
+// only listens on a socket
+func (o ServerOptions) URL() string {
+	if o.Socket != "" {
+		return ""
+	}
+
+	scheme := "http"
+	if o.TLS() {
+		scheme = "https"
+	}
+
+	host := o.Bind
+	if host == "" || host == "0.0.0.0" || host == "::" {
+		host = "localhost"
+	}
+	return fmt.Sprintf("%s://%s", scheme, net.JoinHostPort(host, strconv.Itoa(o.Port)))
+}
+
+// listen opens the TCP or Unix socket listener and wraps it in TLS when
+// requested
+func listen(opts ServerOptions) (net.Listener, error) {
+	var ln net.Listener
+	var err error
+
+	if opts.Socket != "" {
+		ln, err = listenSocket(opts.Socket)
+	} else {
+		ln, err = net.Listen("tcp", net.JoinHostPort(opts.Bind, strconv.Itoa(opts.Port)))
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if !opts.TLS() {
+		return ln, nil
+	}
+
+	certFile, keyFile := opts.TLSCert, opts.TLSKey
+	if opts.SelfSigned {
+		certFile, keyFile, err = EnsureSelfSignedCert(opts.Bind)
+		if err != nil {
+			ln.Close()
+			return nil, err
+		}
+	}
+
+	cert, err := tls.LoadX509KeyPair(certFile, keyFile)
+	if err != nil {
+		ln.Close()
+		return nil, fmt.Errorf("cannot load TLS certificate: %w", err)
+	}
+
+	return tls.NewListener(ln, &tls.Config{
+		Certificates: []tls.Certificate{cert},
+		MinVersion:   tls.VersionTLS12,
+	}), nil
+}
+
+// listenSocket listens on a Unix domain socket only the current user can
+// connect to. A socket left behind by a crashed server is replaced, one
+// that still answers is not
+func listenSocket(path string) (net.Listener, error) {
+	if _, err := os.Stat(path); err == nil {
+		if conn, err := net.DialTimeout("unix", path, time.Second); err == nil {
+			conn.Close()
+			return nil, fmt.Errorf("socket %s is already in use", path)
+		}
+		if err := os.Remove(path); err != nil {
+			return nil, fmt.Errorf("cannot remove stale socket %s: %w", path, err)
+		}
+	} else if !errors.Is(err, os.ErrNotExist) {
+		return nil, err
+	}
+
+	// Created 0600 rather than restricted afterwards, so nobody else can
+	// connect in between
+	var ln net.Listener
+	err := withUmask(0177, func() error {
+		var err error
+		ln, err = net.Listen("unix", path)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if err := os.Chmod(path, 0600); err != nil {
+		ln.Close()
+		return nil, fmt.Errorf("cannot restrict socket permissions: %w", err)
+	}
+	return ln, nil
+}
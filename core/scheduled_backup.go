@@ -0,0 +1,44 @@
+package core
+
+import (
+	"database/sql"
+	"fmt"
+	"log"
+	"os"
+	"time"
+
+	"github.com/Knightshrestha/Secret-Injector/database"
+)
+
+const scheduledBackupLabel = "auto"
+
+// StartBackupSchedule writes a backup next to the database every interval
+// while the server runs and keeps only the newest keep of them. Failures
+// are printed to stderr and retried at the next tick.
+func StartBackupSchedule(db *sql.DB, interval time.Duration, keep int) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for range ticker.C {
+		if err := scheduledBackup(db, keep); err != nil {
+			fmt.Fprintf(os.Stderr, "Scheduled backup failed: %v\n", err)
+		}
+	}
+}
+
+func scheduledBackup(db *sql.DB, keep int) error {
+	backupPath, err := database.BackupPath(scheduledBackupLabel)
+	if err != nil {
+		return err
+	}
+	if err := database.BackupTo(db, backupPath); err != nil {
+		return err
+	}
+	log.Println("✓ Backup written to", backupPath)
+
+	removed, err := database.PruneBackups(scheduledBackupLabel, keep)
+	for _, path := range removed {
+		log.Println("✓ Removed old backup", path)
+	}
+	return err
+}
@@ -6,6 +6,7 @@ import (
 	"log"
 	"os"
 	"os/signal"
+	"strings"
 	"syscall"
 
 	"github.com/Knightshrestha/Secret-Injector/database"
@@ -17,11 +18,39 @@ import (
 	"github.com/gofiber/fiber/v2/middleware/healthcheck"
 )
 
-func StartServer(port int, logging bool) {
+// StartServer runs the server until SIGINT or SIGTERM. It returns early
+// when the database cannot be opened or the address cannot be listened on.
+func StartServer(opts ServerOptions) error {
 	log.Println("Starting Novel Server...")
+	database.ConnectionLog = log.Default()
+
+	// Held until exit, so rekey --reencrypt cannot swap the data key the
+	// server has unlocked
+	release, err := database.Lock("serve")
+	if err != nil {
+		return err
+	}
+	defer release()
 
 	// Open DB
-	mainDb := database.OpenDatabase()
+	mainDb, err := database.OpenDatabase()
+	if err != nil {
+		return err
+	}
+
+	// Bind before printing anything so a busy port fails early
+	ln, err := listen(opts)
+	if err != nil {
+		database.CloseDatabase(mainDb)
+		return fmt.Errorf("failed to listen: %w", err)
+	}
+
+	// Make sure someone can log in
+	if err := EnsureAdminToken(mainDb, opts.URL()); err != nil {
+		ln.Close()
+		database.CloseDatabase(mainDb)
+		return err
+	}
 
 	// Start SSE hub
 	go server_sse.SSE_ProjectHub.Run()
@@ -29,6 +58,14 @@ func StartServer(port int, logging bool) {
 
 	log.Println("SSE Hub started")
 
+	// Broadcast what CLI commands change while the server runs
+	go server.WatchLocalChanges(mainDb)
+
+	if opts.BackupInterval > 0 {
+		go StartBackupSchedule(mainDb.WriteDB, opts.BackupInterval, opts.BackupKeep)
+		log.Printf("Backing up every %s, keeping %d", opts.BackupInterval, opts.BackupKeep)
+	}
+
 	// Create Fiber app
 	app := fiber.New(fiber.Config{
 		DisableStartupMessage: false,
@@ -36,8 +73,8 @@ func StartServer(port int, logging bool) {
 
 	// Fiber Middleware
 	app.Use(cors.New(cors.Config{
-		AllowOrigins: "http://localhost:5173", // Vite's default port
-		AllowHeaders: "Origin, Content-Type, Accept",
+		AllowOrigins: strings.Join(opts.CORSOrigins, ","),
+		AllowHeaders: "Origin, Content-Type, Accept, Authorization",
 	}))
 	app.Use(compress.New())
 	app.Use(healthcheck.New(healthcheck.Config{
@@ -45,6 +82,9 @@ func StartServer(port int, logging bool) {
 		ReadinessEndpoint: "/ready",
 	}))
 
+	// Exchange `/ui?token=` login links for a cookie
+	app.Use("/ui", server.UILogin(mainDb.ReadQueries))
+
 	EmbedWebsite(app)
 
 	// Routes
@@ -56,20 +96,18 @@ func StartServer(port int, logging bool) {
 
 	// Start server in a goroutine
 	go func() {
-		if err := app.Listen(
-			fmt.Sprintf(":%d", port),
-		); err != nil {
+		if err := app.Listener(ln); err != nil {
 			log.Fatal("Fiber server error:", err)
 		}
 	}()
 
-	if !logging {
+	if !opts.Logging {
 		log.SetOutput(io.Discard)
 	}
 
 	// Wait for shutdown signal
 	<-shutdownChan
-	if !logging {
+	if !opts.Logging {
 		log.SetOutput(os.Stdout)
 	}
 
@@ -77,4 +115,5 @@ func StartServer(port int, logging bool) {
 
 	// Perform graceful shutdown
 	Shutdown(mainDb, app)
+	return nil
 }
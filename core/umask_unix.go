@@ -0,0 +1,13 @@
+//go:build !windows
+
+package core
+
+import "syscall"
+
+// withUmask runs f with the process umask set to mask, so files f creates
+// never have wider permissions, even for a moment
+func withUmask(mask int, f func() error) error {
+	old := syscall.Umask(mask)
+	defer syscall.Umask(old)
+	return f()
+}
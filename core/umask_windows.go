@@ -0,0 +1,6 @@
+package core
+
+// withUmask runs f, Windows has no umask
+func withUmask(mask int, f func() error) error {
+	return f()
+}
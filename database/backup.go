@@ -0,0 +1,70 @@
+package database
+
+import (
+	"database/sql"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+)
+
+// BackupTo writes a consistent snapshot of the database to dest with
+// VACUUM INTO, which is safe while other connections are writing in WAL mode
+func BackupTo(db *sql.DB, dest string) error {
+	if _, err := os.Stat(dest); err == nil {
+		return fmt.Errorf("backup file already exists: %s", dest)
+	}
+
+	if _, err := db.Exec(`VACUUM INTO ?`, dest); err != nil {
+		return fmt.Errorf("failed to write backup: %w", err)
+	}
+
+	if err := os.Chmod(dest, 0600); err != nil {
+		return fmt.Errorf("failed to set backup permissions: %w", err)
+	}
+
+	return nil
+}
+
+// BackupPath returns a timestamped backup path next to the database file,
+// e.g. secrets.db.pre-rekey-20250101-120000.000.bak
+func BackupPath(label string) (string, error) {
+	dbPath, err := getDBPath()
+	if err != nil {
+		return "", fmt.Errorf("cannot get database path: %w", err)
+	}
+
+	name := fmt.Sprintf("%s.%s-%s.bak", filepath.Base(dbPath), label, time.Now().Format("20060102-150405.000"))
+	return filepath.Join(filepath.Dir(dbPath), name), nil
+}
+
+// PruneBackups deletes all but the newest keep backups written with label
+// and returns the removed paths
+func PruneBackups(label string, keep int) ([]string, error) {
+	dbPath, err := getDBPath()
+	if err != nil {
+		return nil, fmt.Errorf("cannot get database path: %w", err)
+	}
+
+	pattern := fmt.Sprintf("%s.%s-*.bak", filepath.Base(dbPath), label)
+	matches, err := filepath.Glob(filepath.Join(filepath.Dir(dbPath), pattern))
+	if err != nil {
+		return nil, err
+	}
+	if len(matches) <= keep {
+		return nil, nil
+	}
+
+	// The timestamp in the name sorts chronologically
+	sort.Strings(matches)
+
+	var removed []string
+	for _, path := range matches[:len(matches)-keep] {
+		if err := os.Remove(path); err != nil {
+			return removed, fmt.Errorf("failed to remove old backup: %w", err)
+		}
+		removed = append(removed, path)
+	}
+	return removed, nil
+}
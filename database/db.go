@@ -3,44 +3,25 @@ package database
 import (
 	"context"
 	"database/sql"
-	_ "embed"
 	"fmt"
+	"io"
 	"log"
-	"os"
-	"path/filepath"
 	"time"
 
 	"github.com/Knightshrestha/Secret-Injector/database/generated"
+	"github.com/Knightshrestha/Secret-Injector/vault"
 	_ "modernc.org/sqlite"
 )
 
-//go:embed src/schema.sql
-var ddl string
-
 type CustomDB struct {
 	ReadDB  *sql.DB
 	WriteDB *sql.DB
 
 	ReadQueries  *generated.Queries
 	WriteQueries *generated.Queries
-}
-
-func getDBPath() (string, error) {
-	exePath, err := os.Executable()
-	if err != nil {
-		return "", err
-	}
 
-	exeDir := filepath.Dir(exePath)
-	dataDir := filepath.Join(exeDir, "si_data")
-
-	// Create folder if missing
-	if err := os.MkdirAll(dataDir, os.ModePerm); err != nil {
-		return "", err
-	}
-
-	dbPath := filepath.Join(dataDir, "secrets.db")
-	return dbPath, nil
+	// Cipher is nil while the database is not encrypted
+	Cipher *vault.Cipher
 }
 
 type DB_Struct struct {
@@ -48,25 +29,16 @@ type DB_Struct struct {
 	Queries *generated.Queries
 }
 
-func SetupDatabase() error {
-	dbPath, err := getDBPath()
-	if err != nil {
-		return fmt.Errorf("cannot get database path: %w", err)
-	}
-
-	// Open temporary connection for setup
-	database, err := sql.Open("sqlite", dbPath)
-	if err != nil {
-		return fmt.Errorf("cannot open database for setup: %w", err)
-	}
-	defer database.Close()
+// ConnectionLog receives the messages about opening and closing the write
+// connection. Only `serve` shows them, CLI commands open one on every run.
+var ConnectionLog = log.New(io.Discard, "", 0)
 
-	// Create tables
-	ctx := context.Background()
-	if _, err := database.ExecContext(ctx, ddl); err != nil {
-		return fmt.Errorf("failed to create tables: %w", err)
+// SetupDatabase creates the database file if needed and applies any
+// pending migrations
+func SetupDatabase() error {
+	if _, err := MigrateUp(0); err != nil {
+		return fmt.Errorf("failed to migrate database: %w", err)
 	}
-
 	return nil
 }
 
@@ -90,6 +62,11 @@ func OpenWriteDatabase() (DB_Struct, error) {
 	dbWrite.SetMaxIdleConns(1)
 	dbWrite.SetConnMaxLifetime(time.Hour)
 
+	if err := checkSchemaVersion(dbWrite); err != nil {
+		dbWrite.Close()
+		return DB_Struct{}, err
+	}
+
 	// Enable WAL mode
 	if _, err := dbWrite.Exec("PRAGMA journal_mode=WAL"); err != nil {
 		dbWrite.Close()
@@ -106,7 +83,7 @@ func OpenWriteDatabase() (DB_Struct, error) {
 		dbWrite.Close()
 		return DB_Struct{}, fmt.Errorf("WAL mode not enabled, got: %s", mode)
 	}
-	fmt.Println("✓ WAL mode enabled:", mode)
+	ConnectionLog.Println("✓ WAL mode enabled:", mode)
 
 	return DB_Struct{
 		DB:      dbWrite,
@@ -134,30 +111,65 @@ func OpenReadDatabase() (DB_Struct, error) {
 	dbRead.SetMaxIdleConns(10)
 	dbRead.SetConnMaxLifetime(time.Hour)
 
+	if err := checkSchemaVersion(dbRead); err != nil {
+		dbRead.Close()
+		return DB_Struct{}, err
+	}
+
 	return DB_Struct{
 		DB:      dbRead,
 		Queries: generated.New(dbRead),
 	}, nil
 }
 
+// OpenMigratedWriteDatabase applies pending migrations, then opens the
+// write connection. Commands open the database through it, so a newer
+// release works on the database an older one left behind.
+func OpenMigratedWriteDatabase() (DB_Struct, error) {
+	if err := SetupDatabase(); err != nil {
+		return DB_Struct{}, fmt.Errorf("failed to setup database: %w", err)
+	}
+	return OpenWriteDatabase()
+}
+
+// OpenMigratedReadDatabase is OpenMigratedWriteDatabase for the read
+// connections, which cannot migrate the schema themselves
+func OpenMigratedReadDatabase() (DB_Struct, error) {
+	if err := SetupDatabase(); err != nil {
+		return DB_Struct{}, fmt.Errorf("failed to setup database: %w", err)
+	}
+	return OpenReadDatabase()
+}
+
 // OpenDatabase opens both read and write database connections
-func OpenDatabase() CustomDB {
+func OpenDatabase() (CustomDB, error) {
 	// Setup database schema first (if needed)
 	if err := SetupDatabase(); err != nil {
-		log.Fatal("Failed to setup database:", err)
+		return CustomDB{}, fmt.Errorf("failed to setup database: %w", err)
 	}
-	
+
 	// Open write database
 	writableDatabase, err := OpenWriteDatabase()
 	if err != nil {
-		log.Fatal("Failed to open write database:", err)
+		return CustomDB{}, fmt.Errorf("failed to open write database: %w", err)
 	}
 
 	// Open read database
 	readOnlyDatabase, err := OpenReadDatabase()
 	if err != nil {
 		writableDatabase.DB.Close()
-		log.Fatal("Failed to open read database:", err)
+		return CustomDB{}, fmt.Errorf("failed to open read database: %w", err)
+	}
+
+	// Unlock encrypted values
+	cipher, err := vault.Unlock(context.Background(), writableDatabase.Queries)
+	if err != nil {
+		readOnlyDatabase.DB.Close()
+		writableDatabase.DB.Close()
+		return CustomDB{}, fmt.Errorf("failed to unlock database: %w", err)
+	}
+	if cipher == nil {
+		log.Println("Warning: secret values are stored unencrypted, run `migrate-encrypt` to encrypt them")
 	}
 
 	return CustomDB{
@@ -165,7 +177,8 @@ func OpenDatabase() CustomDB {
 		WriteDB:      writableDatabase.DB,
 		ReadQueries:  readOnlyDatabase.Queries,
 		WriteQueries: writableDatabase.Queries,
-	}
+		Cipher:       cipher,
+	}, nil
 }
 
 func CloseWriteDatabase(dbWrite *sql.DB) error {
@@ -183,7 +196,7 @@ func CloseWriteDatabase(dbWrite *sql.DB) error {
 		return fmt.Errorf("failed to close write database: %w", err)
 	}
 
-	log.Println("Write database connection closed successfully.")
+	ConnectionLog.Println("Write database connection closed successfully.")
 	return nil
 }
 
@@ -24,6 +24,24 @@ func New(db DBTX) *Queries {
 func Prepare(ctx context.Context, db DBTX) (*Queries, error) {
 	q := Queries{db: db}
 	var err error
+	if q.addApiTokenProjectStmt, err = db.PrepareContext(ctx, addApiTokenProject); err != nil {
+		return nil, fmt.Errorf("error preparing query AddApiTokenProject: %w", err)
+	}
+	if q.countApiTokensStmt, err = db.PrepareContext(ctx, countApiTokens); err != nil {
+		return nil, fmt.Errorf("error preparing query CountApiTokens: %w", err)
+	}
+	if q.createApiTokenStmt, err = db.PrepareContext(ctx, createApiToken); err != nil {
+		return nil, fmt.Errorf("error preparing query CreateApiToken: %w", err)
+	}
+	if q.createAuditEntryStmt, err = db.PrepareContext(ctx, createAuditEntry); err != nil {
+		return nil, fmt.Errorf("error preparing query CreateAuditEntry: %w", err)
+	}
+	if q.createDeletedSecretStmt, err = db.PrepareContext(ctx, createDeletedSecret); err != nil {
+		return nil, fmt.Errorf("error preparing query CreateDeletedSecret: %w", err)
+	}
+	if q.createEncryptionKeyStmt, err = db.PrepareContext(ctx, createEncryptionKey); err != nil {
+		return nil, fmt.Errorf("error preparing query CreateEncryptionKey: %w", err)
+	}
 	if q.createProjectStmt, err = db.PrepareContext(ctx, createProject); err != nil {
 		return nil, fmt.Errorf("error preparing query CreateProject: %w", err)
 	}
@@ -33,27 +51,108 @@ func Prepare(ctx context.Context, db DBTX) (*Queries, error) {
 	if q.deleteAllSecretsInProjectsStmt, err = db.PrepareContext(ctx, deleteAllSecretsInProjects); err != nil {
 		return nil, fmt.Errorf("error preparing query DeleteAllSecretsInProjects: %w", err)
 	}
+	if q.deleteApiTokenStmt, err = db.PrepareContext(ctx, deleteApiToken); err != nil {
+		return nil, fmt.Errorf("error preparing query DeleteApiToken: %w", err)
+	}
+	if q.deleteApiTokenProjectsStmt, err = db.PrepareContext(ctx, deleteApiTokenProjects); err != nil {
+		return nil, fmt.Errorf("error preparing query DeleteApiTokenProjects: %w", err)
+	}
+	if q.deleteApiTokenProjectsByProjectStmt, err = db.PrepareContext(ctx, deleteApiTokenProjectsByProject); err != nil {
+		return nil, fmt.Errorf("error preparing query DeleteApiTokenProjectsByProject: %w", err)
+	}
+	if q.deleteDeletedSecretStmt, err = db.PrepareContext(ctx, deleteDeletedSecret); err != nil {
+		return nil, fmt.Errorf("error preparing query DeleteDeletedSecret: %w", err)
+	}
+	if q.deleteDeletedSecretsInProjectStmt, err = db.PrepareContext(ctx, deleteDeletedSecretsInProject); err != nil {
+		return nil, fmt.Errorf("error preparing query DeleteDeletedSecretsInProject: %w", err)
+	}
+	if q.deleteEncryptionKeyStmt, err = db.PrepareContext(ctx, deleteEncryptionKey); err != nil {
+		return nil, fmt.Errorf("error preparing query DeleteEncryptionKey: %w", err)
+	}
 	if q.deleteProjectStmt, err = db.PrepareContext(ctx, deleteProject); err != nil {
 		return nil, fmt.Errorf("error preparing query DeleteProject: %w", err)
 	}
 	if q.deleteSecretStmt, err = db.PrepareContext(ctx, deleteSecret); err != nil {
 		return nil, fmt.Errorf("error preparing query DeleteSecret: %w", err)
 	}
+	if q.deleteSecretVersionsInProjectStmt, err = db.PrepareContext(ctx, deleteSecretVersionsInProject); err != nil {
+		return nil, fmt.Errorf("error preparing query DeleteSecretVersionsInProject: %w", err)
+	}
+	if q.getActiveEncryptionKeyStmt, err = db.PrepareContext(ctx, getActiveEncryptionKey); err != nil {
+		return nil, fmt.Errorf("error preparing query GetActiveEncryptionKey: %w", err)
+	}
+	if q.getAllApiTokensStmt, err = db.PrepareContext(ctx, getAllApiTokens); err != nil {
+		return nil, fmt.Errorf("error preparing query GetAllApiTokens: %w", err)
+	}
+	if q.getAllAuditEntriesStmt, err = db.PrepareContext(ctx, getAllAuditEntries); err != nil {
+		return nil, fmt.Errorf("error preparing query GetAllAuditEntries: %w", err)
+	}
 	if q.getAllProjectsStmt, err = db.PrepareContext(ctx, getAllProjects); err != nil {
 		return nil, fmt.Errorf("error preparing query GetAllProjects: %w", err)
 	}
+	if q.getAllSecretVersionsStmt, err = db.PrepareContext(ctx, getAllSecretVersions); err != nil {
+		return nil, fmt.Errorf("error preparing query GetAllSecretVersions: %w", err)
+	}
 	if q.getAllSecretsStmt, err = db.PrepareContext(ctx, getAllSecrets); err != nil {
 		return nil, fmt.Errorf("error preparing query GetAllSecrets: %w", err)
 	}
+	if q.getApiTokenByHashStmt, err = db.PrepareContext(ctx, getApiTokenByHash); err != nil {
+		return nil, fmt.Errorf("error preparing query GetApiTokenByHash: %w", err)
+	}
+	if q.getApiTokenProjectIDsStmt, err = db.PrepareContext(ctx, getApiTokenProjectIDs); err != nil {
+		return nil, fmt.Errorf("error preparing query GetApiTokenProjectIDs: %w", err)
+	}
+	if q.getAuditEntriesStmt, err = db.PrepareContext(ctx, getAuditEntries); err != nil {
+		return nil, fmt.Errorf("error preparing query GetAuditEntries: %w", err)
+	}
+	if q.getAuditEntriesAfterStmt, err = db.PrepareContext(ctx, getAuditEntriesAfter); err != nil {
+		return nil, fmt.Errorf("error preparing query GetAuditEntriesAfter: %w", err)
+	}
+	if q.getDeletedSecretByIDStmt, err = db.PrepareContext(ctx, getDeletedSecretByID); err != nil {
+		return nil, fmt.Errorf("error preparing query GetDeletedSecretByID: %w", err)
+	}
+	if q.getDeletedSecretsByProjectIDStmt, err = db.PrepareContext(ctx, getDeletedSecretsByProjectID); err != nil {
+		return nil, fmt.Errorf("error preparing query GetDeletedSecretsByProjectID: %w", err)
+	}
+	if q.getLastAuditEntryStmt, err = db.PrepareContext(ctx, getLastAuditEntry); err != nil {
+		return nil, fmt.Errorf("error preparing query GetLastAuditEntry: %w", err)
+	}
 	if q.getProjectByIDStmt, err = db.PrepareContext(ctx, getProjectByID); err != nil {
 		return nil, fmt.Errorf("error preparing query GetProjectByID: %w", err)
 	}
 	if q.getSecretByIDStmt, err = db.PrepareContext(ctx, getSecretByID); err != nil {
 		return nil, fmt.Errorf("error preparing query GetSecretByID: %w", err)
 	}
+	if q.getSecretVersionStmt, err = db.PrepareContext(ctx, getSecretVersion); err != nil {
+		return nil, fmt.Errorf("error preparing query GetSecretVersion: %w", err)
+	}
+	if q.getSecretVersionsStmt, err = db.PrepareContext(ctx, getSecretVersions); err != nil {
+		return nil, fmt.Errorf("error preparing query GetSecretVersions: %w", err)
+	}
 	if q.getSecretsByProjectIDStmt, err = db.PrepareContext(ctx, getSecretsByProjectID); err != nil {
 		return nil, fmt.Errorf("error preparing query GetSecretsByProjectID: %w", err)
 	}
+	if q.restoreDeletedSecretStmt, err = db.PrepareContext(ctx, restoreDeletedSecret); err != nil {
+		return nil, fmt.Errorf("error preparing query RestoreDeletedSecret: %w", err)
+	}
+	if q.restoreSecretStmt, err = db.PrepareContext(ctx, restoreSecret); err != nil {
+		return nil, fmt.Errorf("error preparing query RestoreSecret: %w", err)
+	}
+	if q.setSecretValueStmt, err = db.PrepareContext(ctx, setSecretValue); err != nil {
+		return nil, fmt.Errorf("error preparing query SetSecretValue: %w", err)
+	}
+	if q.setSecretVersionValueStmt, err = db.PrepareContext(ctx, setSecretVersionValue); err != nil {
+		return nil, fmt.Errorf("error preparing query SetSecretVersionValue: %w", err)
+	}
+	if q.snapshotSecretVersionStmt, err = db.PrepareContext(ctx, snapshotSecretVersion); err != nil {
+		return nil, fmt.Errorf("error preparing query SnapshotSecretVersion: %w", err)
+	}
+	if q.touchApiTokenStmt, err = db.PrepareContext(ctx, touchApiToken); err != nil {
+		return nil, fmt.Errorf("error preparing query TouchApiToken: %w", err)
+	}
+	if q.updateEncryptionKeyWrappingStmt, err = db.PrepareContext(ctx, updateEncryptionKeyWrapping); err != nil {
+		return nil, fmt.Errorf("error preparing query UpdateEncryptionKeyWrapping: %w", err)
+	}
 	if q.updateProjectStmt, err = db.PrepareContext(ctx, updateProject); err != nil {
 		return nil, fmt.Errorf("error preparing query UpdateProject: %w", err)
 	}
@@ -65,6 +164,36 @@ func Prepare(ctx context.Context, db DBTX) (*Queries, error) {
 
 func (q *Queries) Close() error {
 	var err error
+	if q.addApiTokenProjectStmt != nil {
+		if cerr := q.addApiTokenProjectStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing addApiTokenProjectStmt: %w", cerr)
+		}
+	}
+	if q.countApiTokensStmt != nil {
+		if cerr := q.countApiTokensStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing countApiTokensStmt: %w", cerr)
+		}
+	}
+	if q.createApiTokenStmt != nil {
+		if cerr := q.createApiTokenStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing createApiTokenStmt: %w", cerr)
+		}
+	}
+	if q.createAuditEntryStmt != nil {
+		if cerr := q.createAuditEntryStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing createAuditEntryStmt: %w", cerr)
+		}
+	}
+	if q.createDeletedSecretStmt != nil {
+		if cerr := q.createDeletedSecretStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing createDeletedSecretStmt: %w", cerr)
+		}
+	}
+	if q.createEncryptionKeyStmt != nil {
+		if cerr := q.createEncryptionKeyStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing createEncryptionKeyStmt: %w", cerr)
+		}
+	}
 	if q.createProjectStmt != nil {
 		if cerr := q.createProjectStmt.Close(); cerr != nil {
 			err = fmt.Errorf("error closing createProjectStmt: %w", cerr)
@@ -80,6 +209,36 @@ func (q *Queries) Close() error {
 			err = fmt.Errorf("error closing deleteAllSecretsInProjectsStmt: %w", cerr)
 		}
 	}
+	if q.deleteApiTokenStmt != nil {
+		if cerr := q.deleteApiTokenStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing deleteApiTokenStmt: %w", cerr)
+		}
+	}
+	if q.deleteApiTokenProjectsStmt != nil {
+		if cerr := q.deleteApiTokenProjectsStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing deleteApiTokenProjectsStmt: %w", cerr)
+		}
+	}
+	if q.deleteApiTokenProjectsByProjectStmt != nil {
+		if cerr := q.deleteApiTokenProjectsByProjectStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing deleteApiTokenProjectsByProjectStmt: %w", cerr)
+		}
+	}
+	if q.deleteDeletedSecretStmt != nil {
+		if cerr := q.deleteDeletedSecretStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing deleteDeletedSecretStmt: %w", cerr)
+		}
+	}
+	if q.deleteDeletedSecretsInProjectStmt != nil {
+		if cerr := q.deleteDeletedSecretsInProjectStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing deleteDeletedSecretsInProjectStmt: %w", cerr)
+		}
+	}
+	if q.deleteEncryptionKeyStmt != nil {
+		if cerr := q.deleteEncryptionKeyStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing deleteEncryptionKeyStmt: %w", cerr)
+		}
+	}
 	if q.deleteProjectStmt != nil {
 		if cerr := q.deleteProjectStmt.Close(); cerr != nil {
 			err = fmt.Errorf("error closing deleteProjectStmt: %w", cerr)
@@ -90,16 +249,76 @@ func (q *Queries) Close() error {
 			err = fmt.Errorf("error closing deleteSecretStmt: %w", cerr)
 		}
 	}
+	if q.deleteSecretVersionsInProjectStmt != nil {
+		if cerr := q.deleteSecretVersionsInProjectStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing deleteSecretVersionsInProjectStmt: %w", cerr)
+		}
+	}
+	if q.getActiveEncryptionKeyStmt != nil {
+		if cerr := q.getActiveEncryptionKeyStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing getActiveEncryptionKeyStmt: %w", cerr)
+		}
+	}
+	if q.getAllApiTokensStmt != nil {
+		if cerr := q.getAllApiTokensStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing getAllApiTokensStmt: %w", cerr)
+		}
+	}
+	if q.getAllAuditEntriesStmt != nil {
+		if cerr := q.getAllAuditEntriesStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing getAllAuditEntriesStmt: %w", cerr)
+		}
+	}
 	if q.getAllProjectsStmt != nil {
 		if cerr := q.getAllProjectsStmt.Close(); cerr != nil {
 			err = fmt.Errorf("error closing getAllProjectsStmt: %w", cerr)
 		}
 	}
+	if q.getAllSecretVersionsStmt != nil {
+		if cerr := q.getAllSecretVersionsStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing getAllSecretVersionsStmt: %w", cerr)
+		}
+	}
 	if q.getAllSecretsStmt != nil {
 		if cerr := q.getAllSecretsStmt.Close(); cerr != nil {
 			err = fmt.Errorf("error closing getAllSecretsStmt: %w", cerr)
 		}
 	}
+	if q.getApiTokenByHashStmt != nil {
+		if cerr := q.getApiTokenByHashStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing getApiTokenByHashStmt: %w", cerr)
+		}
+	}
+	if q.getApiTokenProjectIDsStmt != nil {
+		if cerr := q.getApiTokenProjectIDsStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing getApiTokenProjectIDsStmt: %w", cerr)
+		}
+	}
+	if q.getAuditEntriesStmt != nil {
+		if cerr := q.getAuditEntriesStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing getAuditEntriesStmt: %w", cerr)
+		}
+	}
+	if q.getAuditEntriesAfterStmt != nil {
+		if cerr := q.getAuditEntriesAfterStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing getAuditEntriesAfterStmt: %w", cerr)
+		}
+	}
+	if q.getDeletedSecretByIDStmt != nil {
+		if cerr := q.getDeletedSecretByIDStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing getDeletedSecretByIDStmt: %w", cerr)
+		}
+	}
+	if q.getDeletedSecretsByProjectIDStmt != nil {
+		if cerr := q.getDeletedSecretsByProjectIDStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing getDeletedSecretsByProjectIDStmt: %w", cerr)
+		}
+	}
+	if q.getLastAuditEntryStmt != nil {
+		if cerr := q.getLastAuditEntryStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing getLastAuditEntryStmt: %w", cerr)
+		}
+	}
 	if q.getProjectByIDStmt != nil {
 		if cerr := q.getProjectByIDStmt.Close(); cerr != nil {
 			err = fmt.Errorf("error closing getProjectByIDStmt: %w", cerr)
@@ -110,11 +329,56 @@ func (q *Queries) Close() error {
 			err = fmt.Errorf("error closing getSecretByIDStmt: %w", cerr)
 		}
 	}
+	if q.getSecretVersionStmt != nil {
+		if cerr := q.getSecretVersionStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing getSecretVersionStmt: %w", cerr)
+		}
+	}
+	if q.getSecretVersionsStmt != nil {
+		if cerr := q.getSecretVersionsStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing getSecretVersionsStmt: %w", cerr)
+		}
+	}
 	if q.getSecretsByProjectIDStmt != nil {
 		if cerr := q.getSecretsByProjectIDStmt.Close(); cerr != nil {
 			err = fmt.Errorf("error closing getSecretsByProjectIDStmt: %w", cerr)
 		}
 	}
+	if q.restoreDeletedSecretStmt != nil {
+		if cerr := q.restoreDeletedSecretStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing restoreDeletedSecretStmt: %w", cerr)
+		}
+	}
+	if q.restoreSecretStmt != nil {
+		if cerr := q.restoreSecretStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing restoreSecretStmt: %w", cerr)
+		}
+	}
+	if q.setSecretValueStmt != nil {
+		if cerr := q.setSecretValueStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing setSecretValueStmt: %w", cerr)
+		}
+	}
+	if q.setSecretVersionValueStmt != nil {
+		if cerr := q.setSecretVersionValueStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing setSecretVersionValueStmt: %w", cerr)
+		}
+	}
+	if q.snapshotSecretVersionStmt != nil {
+		if cerr := q.snapshotSecretVersionStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing snapshotSecretVersionStmt: %w", cerr)
+		}
+	}
+	if q.touchApiTokenStmt != nil {
+		if cerr := q.touchApiTokenStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing touchApiTokenStmt: %w", cerr)
+		}
+	}
+	if q.updateEncryptionKeyWrappingStmt != nil {
+		if cerr := q.updateEncryptionKeyWrappingStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing updateEncryptionKeyWrappingStmt: %w", cerr)
+		}
+	}
 	if q.updateProjectStmt != nil {
 		if cerr := q.updateProjectStmt.Close(); cerr != nil {
 			err = fmt.Errorf("error closing updateProjectStmt: %w", cerr)
@@ -162,37 +426,103 @@ func (q *Queries) queryRow(ctx context.Context, stmt *sql.Stmt, query string, ar
 }
 
 type Queries struct {
-	db                             DBTX
-	tx                             *sql.Tx
-	createProjectStmt              *sql.Stmt
-	createSecretStmt               *sql.Stmt
-	deleteAllSecretsInProjectsStmt *sql.Stmt
-	deleteProjectStmt              *sql.Stmt
-	deleteSecretStmt               *sql.Stmt
-	getAllProjectsStmt             *sql.Stmt
-	getAllSecretsStmt              *sql.Stmt
-	getProjectByIDStmt             *sql.Stmt
-	getSecretByIDStmt              *sql.Stmt
-	getSecretsByProjectIDStmt      *sql.Stmt
-	updateProjectStmt              *sql.Stmt
-	updateSecretStmt               *sql.Stmt
+	db                                  DBTX
+	tx                                  *sql.Tx
+	addApiTokenProjectStmt              *sql.Stmt
+	countApiTokensStmt                  *sql.Stmt
+	createApiTokenStmt                  *sql.Stmt
+	createAuditEntryStmt                *sql.Stmt
+	createDeletedSecretStmt             *sql.Stmt
+	createEncryptionKeyStmt             *sql.Stmt
+	createProjectStmt                   *sql.Stmt
+	createSecretStmt                    *sql.Stmt
+	deleteAllSecretsInProjectsStmt      *sql.Stmt
+	deleteApiTokenStmt                  *sql.Stmt
+	deleteApiTokenProjectsStmt          *sql.Stmt
+	deleteApiTokenProjectsByProjectStmt *sql.Stmt
+	deleteDeletedSecretStmt             *sql.Stmt
+	deleteDeletedSecretsInProjectStmt   *sql.Stmt
+	deleteEncryptionKeyStmt             *sql.Stmt
+	deleteProjectStmt                   *sql.Stmt
+	deleteSecretStmt                    *sql.Stmt
+	deleteSecretVersionsInProjectStmt   *sql.Stmt
+	getActiveEncryptionKeyStmt          *sql.Stmt
+	getAllApiTokensStmt                 *sql.Stmt
+	getAllAuditEntriesStmt              *sql.Stmt
+	getAllProjectsStmt                  *sql.Stmt
+	getAllSecretVersionsStmt            *sql.Stmt
+	getAllSecretsStmt                   *sql.Stmt
+	getApiTokenByHashStmt               *sql.Stmt
+	getApiTokenProjectIDsStmt           *sql.Stmt
+	getAuditEntriesStmt                 *sql.Stmt
+	getAuditEntriesAfterStmt            *sql.Stmt
+	getDeletedSecretByIDStmt            *sql.Stmt
+	getDeletedSecretsByProjectIDStmt    *sql.Stmt
+	getLastAuditEntryStmt               *sql.Stmt
+	getProjectByIDStmt                  *sql.Stmt
+	getSecretByIDStmt                   *sql.Stmt
+	getSecretVersionStmt                *sql.Stmt
+	getSecretVersionsStmt               *sql.Stmt
+	getSecretsByProjectIDStmt           *sql.Stmt
+	restoreDeletedSecretStmt            *sql.Stmt
+	restoreSecretStmt                   *sql.Stmt
+	setSecretValueStmt                  *sql.Stmt
+	setSecretVersionValueStmt           *sql.Stmt
+	snapshotSecretVersionStmt           *sql.Stmt
+	touchApiTokenStmt                   *sql.Stmt
+	updateEncryptionKeyWrappingStmt     *sql.Stmt
+	updateProjectStmt                   *sql.Stmt
+	updateSecretStmt                    *sql.Stmt
 }
 
 func (q *Queries) WithTx(tx *sql.Tx) *Queries {
 	return &Queries{
-		db:                             tx,
-		tx:                             tx,
-		createProjectStmt:              q.createProjectStmt,
-		createSecretStmt:               q.createSecretStmt,
-		deleteAllSecretsInProjectsStmt: q.deleteAllSecretsInProjectsStmt,
-		deleteProjectStmt:              q.deleteProjectStmt,
-		deleteSecretStmt:               q.deleteSecretStmt,
-		getAllProjectsStmt:             q.getAllProjectsStmt,
-		getAllSecretsStmt:              q.getAllSecretsStmt,
-		getProjectByIDStmt:             q.getProjectByIDStmt,
-		getSecretByIDStmt:              q.getSecretByIDStmt,
-		getSecretsByProjectIDStmt:      q.getSecretsByProjectIDStmt,
-		updateProjectStmt:              q.updateProjectStmt,
-		updateSecretStmt:               q.updateSecretStmt,
+		db:                                  tx,
+		tx:                                  tx,
+		addApiTokenProjectStmt:              q.addApiTokenProjectStmt,
+		countApiTokensStmt:                  q.countApiTokensStmt,
+		createApiTokenStmt:                  q.createApiTokenStmt,
+		createAuditEntryStmt:                q.createAuditEntryStmt,
+		createDeletedSecretStmt:             q.createDeletedSecretStmt,
+		createEncryptionKeyStmt:             q.createEncryptionKeyStmt,
+		createProjectStmt:                   q.createProjectStmt,
+		createSecretStmt:                    q.createSecretStmt,
+		deleteAllSecretsInProjectsStmt:      q.deleteAllSecretsInProjectsStmt,
+		deleteApiTokenStmt:                  q.deleteApiTokenStmt,
+		deleteApiTokenProjectsStmt:          q.deleteApiTokenProjectsStmt,
+		deleteApiTokenProjectsByProjectStmt: q.deleteApiTokenProjectsByProjectStmt,
+		deleteDeletedSecretStmt:             q.deleteDeletedSecretStmt,
+		deleteDeletedSecretsInProjectStmt:   q.deleteDeletedSecretsInProjectStmt,
+		deleteEncryptionKeyStmt:             q.deleteEncryptionKeyStmt,
+		deleteProjectStmt:                   q.deleteProjectStmt,
+		deleteSecretStmt:                    q.deleteSecretStmt,
+		deleteSecretVersionsInProjectStmt:   q.deleteSecretVersionsInProjectStmt,
+		getActiveEncryptionKeyStmt:          q.getActiveEncryptionKeyStmt,
+		getAllApiTokensStmt:                 q.getAllApiTokensStmt,
+		getAllAuditEntriesStmt:              q.getAllAuditEntriesStmt,
+		getAllProjectsStmt:                  q.getAllProjectsStmt,
+		getAllSecretVersionsStmt:            q.getAllSecretVersionsStmt,
+		getAllSecretsStmt:                   q.getAllSecretsStmt,
+		getApiTokenByHashStmt:               q.getApiTokenByHashStmt,
+		getApiTokenProjectIDsStmt:           q.getApiTokenProjectIDsStmt,
+		getAuditEntriesStmt:                 q.getAuditEntriesStmt,
+		getAuditEntriesAfterStmt:            q.getAuditEntriesAfterStmt,
+		getDeletedSecretByIDStmt:            q.getDeletedSecretByIDStmt,
+		getDeletedSecretsByProjectIDStmt:    q.getDeletedSecretsByProjectIDStmt,
+		getLastAuditEntryStmt:               q.getLastAuditEntryStmt,
+		getProjectByIDStmt:                  q.getProjectByIDStmt,
+		getSecretByIDStmt:                   q.getSecretByIDStmt,
+		getSecretVersionStmt:                q.getSecretVersionStmt,
+		getSecretVersionsStmt:               q.getSecretVersionsStmt,
+		getSecretsByProjectIDStmt:           q.getSecretsByProjectIDStmt,
+		restoreDeletedSecretStmt:            q.restoreDeletedSecretStmt,
+		restoreSecretStmt:                   q.restoreSecretStmt,
+		setSecretValueStmt:                  q.setSecretValueStmt,
+		setSecretVersionValueStmt:           q.setSecretVersionValueStmt,
+		snapshotSecretVersionStmt:           q.snapshotSecretVersionStmt,
+		touchApiTokenStmt:                   q.touchApiTokenStmt,
+		updateEncryptionKeyWrappingStmt:     q.updateEncryptionKeyWrappingStmt,
+		updateProjectStmt:                   q.updateProjectStmt,
+		updateSecretStmt:                    q.updateSecretStmt,
 	}
 }
@@ -8,6 +8,56 @@ import (
 	"time"
 )
 
+type ApiToken struct {
+	ID         string     `json:"id"`
+	Name       string     `json:"name"`
+	TokenHash  string     `json:"token_hash"`
+	CreatedAt  *time.Time `json:"created_at"`
+	LastUsedAt *time.Time `json:"last_used_at"`
+	Scope      string     `json:"scope"`
+	Restricted bool       `json:"restricted"`
+	ExpiresAt  *time.Time `json:"expires_at"`
+}
+
+type ApiTokenProject struct {
+	TokenID   string `json:"token_id"`
+	ProjectID string `json:"project_id"`
+}
+
+type AuditLog struct {
+	ID        int64     `json:"id"`
+	CreatedAt time.Time `json:"created_at"`
+	Actor     string    `json:"actor"`
+	Source    string    `json:"source"`
+	Action    string    `json:"action"`
+	ProjectID *string   `json:"project_id"`
+	SecretID  *string   `json:"secret_id"`
+	SecretKey *string   `json:"secret_key"`
+	PrevHash  string    `json:"prev_hash"`
+	Hash      string    `json:"hash"`
+}
+
+type DeletedSecret struct {
+	ID          string     `json:"id"`
+	ProjectID   string     `json:"project_id"`
+	Environment string     `json:"environment"`
+	Key         string     `json:"key"`
+	Version     int64      `json:"version"`
+	Actor       string     `json:"actor"`
+	DeletedAt   *time.Time `json:"deleted_at"`
+}
+
+type EncryptionKey struct {
+	ID         string     `json:"id"`
+	WrappedKey string     `json:"wrapped_key"`
+	Kdf        string     `json:"kdf"`
+	Salt       string     `json:"salt"`
+	KdfTime    int64      `json:"kdf_time"`
+	KdfMemory  int64      `json:"kdf_memory"`
+	KdfThreads int64      `json:"kdf_threads"`
+	CreatedAt  *time.Time `json:"created_at"`
+}
+
 type ProjectList struct {
 	ID          string     `json:"id"`
 	Name        string     `json:"name"`
@@ -19,9 +69,23 @@ type ProjectList struct {
 type SecretList struct {
 	ID          string     `json:"id"`
 	ProjectID   string     `json:"project_id"`
+	Environment string     `json:"environment"`
 	Key         string     `json:"key"`
 	Value       string     `json:"value"`
 	Description *string    `json:"description"`
 	CreatedAt   *time.Time `json:"created_at"`
 	UpdatedAt   *time.Time `json:"updated_at"`
+	Type        string     `json:"type"`
+}
+
+type SecretVersion struct {
+	ID          string     `json:"id"`
+	SecretID    string     `json:"secret_id"`
+	Version     int64      `json:"version"`
+	Key         string     `json:"key"`
+	Value       string     `json:"value"`
+	Description *string    `json:"description"`
+	Actor       string     `json:"actor"`
+	CreatedAt   *time.Time `json:"created_at"`
+	Type        string     `json:"type"`
 }
@@ -11,42 +11,50 @@ import (
 
 const createSecret = `-- name: CreateSecret :one
 INSERT INTO
-    secret_list (id, project_id, key, value, description)
+    secret_list (id, project_id, environment, key, value, description, type)
 VALUES
     (
         ?1,
         ?2,
         ?3,
         ?4,
-        ?5
-    ) RETURNING id, project_id, "key", value, description, created_at, updated_at
+        ?5,
+        ?6,
+        ?7
+    ) RETURNING id, project_id, environment, "key", value, description, created_at, updated_at, type
 `
 
 type CreateSecretParams struct {
 	ID          string  `json:"id"`
 	ProjectID   string  `json:"project_id"`
+	Environment string  `json:"environment"`
 	Key         string  `json:"key"`
 	Value       string  `json:"value"`
 	Description *string `json:"description"`
+	Type        string  `json:"type"`
 }
 
 func (q *Queries) CreateSecret(ctx context.Context, arg CreateSecretParams) (SecretList, error) {
 	row := q.queryRow(ctx, q.createSecretStmt, createSecret,
 		arg.ID,
 		arg.ProjectID,
+		arg.Environment,
 		arg.Key,
 		arg.Value,
 		arg.Description,
+		arg.Type,
 	)
 	var i SecretList
 	err := row.Scan(
 		&i.ID,
 		&i.ProjectID,
+		&i.Environment,
 		&i.Key,
 		&i.Value,
 		&i.Description,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.Type,
 	)
 	return i, err
 }
@@ -75,7 +83,7 @@ func (q *Queries) DeleteSecret(ctx context.Context, id string) error {
 
 const getAllSecrets = `-- name: GetAllSecrets :many
 SELECT
-    id, project_id, "key", value, description, created_at, updated_at
+    id, project_id, environment, "key", value, description, created_at, updated_at, type
 FROM
     secret_list
 `
@@ -92,11 +100,13 @@ func (q *Queries) GetAllSecrets(ctx context.Context) ([]SecretList, error) {
 		if err := rows.Scan(
 			&i.ID,
 			&i.ProjectID,
+			&i.Environment,
 			&i.Key,
 			&i.Value,
 			&i.Description,
 			&i.CreatedAt,
 			&i.UpdatedAt,
+			&i.Type,
 		); err != nil {
 			return nil, err
 		}
@@ -113,7 +123,7 @@ func (q *Queries) GetAllSecrets(ctx context.Context) ([]SecretList, error) {
 
 const getSecretByID = `-- name: GetSecretByID :one
 SELECT
-    id, project_id, "key", value, description, created_at, updated_at
+    id, project_id, environment, "key", value, description, created_at, updated_at, type
 FROM
     secret_list
 WHERE
@@ -126,18 +136,20 @@ func (q *Queries) GetSecretByID(ctx context.Context, id string) (SecretList, err
 	err := row.Scan(
 		&i.ID,
 		&i.ProjectID,
+		&i.Environment,
 		&i.Key,
 		&i.Value,
 		&i.Description,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.Type,
 	)
 	return i, err
 }
 
 const getSecretsByProjectID = `-- name: GetSecretsByProjectID :many
 SELECT
-    id, project_id, "key", value, description, created_at, updated_at
+    id, project_id, environment, "key", value, description, created_at, updated_at, type
 FROM
     secret_list
 WHERE
@@ -156,11 +168,13 @@ func (q *Queries) GetSecretsByProjectID(ctx context.Context, projectID string) (
 		if err := rows.Scan(
 			&i.ID,
 			&i.ProjectID,
+			&i.Environment,
 			&i.Key,
 			&i.Value,
 			&i.Description,
 			&i.CreatedAt,
 			&i.UpdatedAt,
+			&i.Type,
 		); err != nil {
 			return nil, err
 		}
@@ -175,21 +189,84 @@ func (q *Queries) GetSecretsByProjectID(ctx context.Context, projectID string) (
 	return items, nil
 }
 
+const restoreSecret = `-- name: RestoreSecret :one
+UPDATE secret_list
+SET
+    key = ?1,
+    value = ?2,
+    description = ?3,
+    type = ?4,
+    updated_at = CURRENT_TIMESTAMP
+WHERE
+    id = ?5 RETURNING id, project_id, environment, "key", value, description, created_at, updated_at, type
+`
+
+type RestoreSecretParams struct {
+	Key         string  `json:"key"`
+	Value       string  `json:"value"`
+	Description *string `json:"description"`
+	Type        string  `json:"type"`
+	ID          string  `json:"id"`
+}
+
+func (q *Queries) RestoreSecret(ctx context.Context, arg RestoreSecretParams) (SecretList, error) {
+	row := q.queryRow(ctx, q.restoreSecretStmt, restoreSecret,
+		arg.Key,
+		arg.Value,
+		arg.Description,
+		arg.Type,
+		arg.ID,
+	)
+	var i SecretList
+	err := row.Scan(
+		&i.ID,
+		&i.ProjectID,
+		&i.Environment,
+		&i.Key,
+		&i.Value,
+		&i.Description,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.Type,
+	)
+	return i, err
+}
+
+const setSecretValue = `-- name: SetSecretValue :exec
+UPDATE secret_list
+SET
+    value = ?1
+WHERE
+    id = ?2
+`
+
+type SetSecretValueParams struct {
+	Value string `json:"value"`
+	ID    string `json:"id"`
+}
+
+func (q *Queries) SetSecretValue(ctx context.Context, arg SetSecretValueParams) error {
+	_, err := q.exec(ctx, q.setSecretValueStmt, setSecretValue, arg.Value, arg.ID)
+	return err
+}
+
 const updateSecret = `-- name: UpdateSecret :one
 UPDATE secret_list
 SET
     key = COALESCE(?1, key),
     description = COALESCE(?2, description),
     value = COALESCE(?3, value),
+    type = COALESCE(?4, type),
     updated_at = CURRENT_TIMESTAMP
 WHERE
-    id = ?4 RETURNING id, project_id, "key", value, description, created_at, updated_at
+    id = ?5 RETURNING id, project_id, environment, "key", value, description, created_at, updated_at, type
 `
 
 type UpdateSecretParams struct {
 	Key         *string `json:"key"`
 	Description *string `json:"description"`
 	Value       *string `json:"value"`
+	Type        *string `json:"type"`
 	ID          string  `json:"id"`
 }
 
@@ -198,17 +275,20 @@ func (q *Queries) UpdateSecret(ctx context.Context, arg UpdateSecretParams) (Sec
 		arg.Key,
 		arg.Description,
 		arg.Value,
+		arg.Type,
 		arg.ID,
 	)
 	var i SecretList
 	err := row.Scan(
 		&i.ID,
 		&i.ProjectID,
+		&i.Environment,
 		&i.Key,
 		&i.Value,
 		&i.Description,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.Type,
 	)
 	return i, err
 }
@@ -0,0 +1,46 @@
+package database
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// ErrInUse is returned by Lock while another process holds the database.
+// `serve` holds it for as long as it runs because it keeps the data key it
+// unlocked at start, so commands replacing that key must not run under it.
+var ErrInUse = errors.New("database is in use")
+
+// Lock marks the database as used by command until the process exits or
+// release is called. The lock is taken by the operating system on a file
+// next to the database, so a crashed process never leaves it behind.
+func Lock(command string) (release func(), err error) {
+	dbPath, err := getDBPath()
+	if err != nil {
+		return nil, fmt.Errorf("cannot get database path: %w", err)
+	}
+	path := dbPath + ".lock"
+
+	f, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE, 0600)
+	if err != nil {
+		return nil, fmt.Errorf("cannot open %s: %w", path, err)
+	}
+	if err := lockFile(f); err != nil {
+		f.Close()
+		if !errors.Is(err, errLocked) {
+			return nil, fmt.Errorf("cannot lock %s: %w", path, err)
+		}
+		// The holder is only known where locked files can be read
+		if holder, err := os.ReadFile(path); err == nil && len(holder) > 0 {
+			return nil, fmt.Errorf("%w by %s", ErrInUse, strings.TrimSpace(string(holder)))
+		}
+		return nil, ErrInUse
+	}
+
+	// Only for the error above, the lock itself is what counts
+	f.Truncate(0)
+	fmt.Fprintf(f, "%s (pid %d)\n", command, os.Getpid())
+
+	return func() { f.Close() }, nil
+}
@@ -0,0 +1,412 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"embed"
+	"errors"
+	"fmt"
+	"io/fs"
+	"log"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Migrations live in migrations/NNNN_name.up.sql and NNNN_name.down.sql.
+// They are applied in order, each in its own transaction, and recorded in
+// schema_migrations. Never edit a released migration, add a new one.
+//
+//go:embed migrations/*.sql
+var migrationFiles embed.FS
+
+// ErrSchemaTooNew is returned for a database migrated by a newer binary
+var ErrSchemaTooNew = errors.New("database schema is newer than this binary supports, upgrade secret_injector")
+
+type Migration struct {
+	Version int
+	Name    string
+	Up      string
+	Down    string
+}
+
+type MigrationState struct {
+	Migration
+	AppliedAt *time.Time
+}
+
+const createSchemaMigrations = `CREATE TABLE IF NOT EXISTS schema_migrations (
+	version INTEGER PRIMARY KEY,
+	name TEXT NOT NULL,
+	applied_at DATETIME DEFAULT CURRENT_TIMESTAMP
+)`
+
+// legacyProbes tell which migrations a database created before
+// schema_migrations existed already has. Each older release brought its
+// database fully up to date, so the probes hold for a prefix of the list.
+// Only needed for those databases, do not extend it.
+var legacyProbes = []string{
+	1: `SELECT COUNT(*) FROM sqlite_master WHERE type = 'table' AND name = 'project_list'`,
+	2: `SELECT COUNT(*) FROM sqlite_master WHERE type = 'table' AND name = 'encryption_keys'`,
+	3: `SELECT COUNT(*) FROM sqlite_master WHERE type = 'table' AND name = 'api_tokens'`,
+	4: `SELECT COUNT(*) FROM pragma_table_info('api_tokens') WHERE name = 'expires_at'`,
+	5: `SELECT COUNT(*) FROM sqlite_master WHERE type = 'table' AND name = 'secret_versions'`,
+	6: `SELECT COUNT(*) FROM sqlite_master WHERE type = 'table' AND name = 'audit_log'`,
+	7: `SELECT COUNT(*) FROM pragma_table_info('secret_list') WHERE name = 'environment'`,
+}
+
+// Migrations returns the embedded migrations ordered by version
+func Migrations() ([]Migration, error) {
+	entries, err := fs.ReadDir(migrationFiles, "migrations")
+	if err != nil {
+		return nil, err
+	}
+
+	byVersion := map[int]*Migration{}
+	for _, entry := range entries {
+		file := entry.Name()
+		base, direction, ok := strings.Cut(strings.TrimSuffix(file, ".sql"), ".")
+		if !ok || (direction != "up" && direction != "down") {
+			return nil, fmt.Errorf("unexpected migration file %s", file)
+		}
+		number, name, ok := strings.Cut(base, "_")
+		if !ok {
+			return nil, fmt.Errorf("unexpected migration file %s", file)
+		}
+		version, err := strconv.Atoi(number)
+		if err != nil || version < 1 {
+			return nil, fmt.Errorf("unexpected migration file %s", file)
+		}
+
+		body, err := migrationFiles.ReadFile(path.Join("migrations", file))
+		if err != nil {
+			return nil, err
+		}
+
+		m := byVersion[version]
+		if m == nil {
+			m = &Migration{Version: version, Name: name}
+			byVersion[version] = m
+		}
+		if direction == "up" {
+			m.Up = string(body)
+		} else {
+			m.Down = string(body)
+		}
+	}
+
+	migrations := make([]Migration, 0, len(byVersion))
+	for _, m := range byVersion {
+		migrations = append(migrations, *m)
+	}
+	sort.Slice(migrations, func(i, j int) bool {
+		return migrations[i].Version < migrations[j].Version
+	})
+
+	for i, m := range migrations {
+		if m.Version != i+1 {
+			return nil, fmt.Errorf("migration %04d is missing", i+1)
+		}
+		if m.Up == "" || m.Down == "" {
+			return nil, fmt.Errorf("migration %04d_%s needs both an up and a down file", m.Version, m.Name)
+		}
+	}
+	return migrations, nil
+}
+
+// LatestSchemaVersion is the schema version this binary creates
+func LatestSchemaVersion() int {
+	migrations, err := Migrations()
+	if err != nil {
+		return 0
+	}
+	return len(migrations)
+}
+
+// MigrationStatus lists every known migration with the time it was applied
+// and returns the version of the database
+func MigrationStatus() ([]MigrationState, int, error) {
+	var states []MigrationState
+	var current int
+
+	err := withMigrationConn(func(ctx context.Context, database *sql.DB, conn *sql.Conn, migrations []Migration) error {
+		applied, err := appliedMigrations(ctx, conn)
+		if err != nil {
+			return err
+		}
+
+		for _, m := range migrations {
+			state := MigrationState{Migration: m}
+			if t, ok := applied[m.Version]; ok {
+				state.AppliedAt = &t
+			}
+			states = append(states, state)
+		}
+
+		current, err = schemaVersion(ctx, conn)
+		return err
+	})
+
+	return states, current, err
+}
+
+// MigrateUp applies pending migrations up to target, or all of them when
+// target is 0. It returns the migrations that were applied.
+func MigrateUp(target int) ([]Migration, error) {
+	var done []Migration
+
+	err := withMigrationConn(func(ctx context.Context, database *sql.DB, conn *sql.Conn, migrations []Migration) error {
+		if target == 0 {
+			target = len(migrations)
+		}
+		if target > len(migrations) {
+			return fmt.Errorf("unknown schema version %d, the latest is %d", target, len(migrations))
+		}
+
+		current, err := schemaVersion(ctx, conn)
+		if err != nil {
+			return err
+		}
+		if current > len(migrations) {
+			return fmt.Errorf("%w (database %d, binary %d)", ErrSchemaTooNew, current, len(migrations))
+		}
+		if target < current {
+			return fmt.Errorf("database is already at version %d, use migrate down to go back", current)
+		}
+
+		pending := migrations[current:target]
+		if current > 0 && len(pending) > 0 {
+			if err := backupBeforeMigration(database, fmt.Sprintf("pre-migrate-v%d", current)); err != nil {
+				return err
+			}
+		}
+
+		for _, m := range pending {
+			if err := applyMigration(ctx, conn, m, true); err != nil {
+				return err
+			}
+			done = append(done, m)
+		}
+		return nil
+	})
+
+	return done, err
+}
+
+// MigrateDown rolls back the last steps migrations and returns them
+func MigrateDown(steps int) ([]Migration, error) {
+	var done []Migration
+
+	err := withMigrationConn(func(ctx context.Context, database *sql.DB, conn *sql.Conn, migrations []Migration) error {
+		current, err := schemaVersion(ctx, conn)
+		if err != nil {
+			return err
+		}
+		if current > len(migrations) {
+			return fmt.Errorf("%w (database %d, binary %d)", ErrSchemaTooNew, current, len(migrations))
+		}
+		if steps > current {
+			return fmt.Errorf("cannot roll back %d migration(s), the database is at version %d", steps, current)
+		}
+		if steps > 0 {
+			if err := backupBeforeMigration(database, fmt.Sprintf("pre-rollback-v%d", current)); err != nil {
+				return err
+			}
+		}
+
+		for i := current; i > current-steps; i-- {
+			m := migrations[i-1]
+			if err := applyMigration(ctx, conn, m, false); err != nil {
+				return err
+			}
+			done = append(done, m)
+		}
+		return nil
+	})
+
+	return done, err
+}
+
+// withMigrationConn runs fn on a single connection with foreign keys off,
+// so migrations can rebuild tables that others reference
+func withMigrationConn(fn func(ctx context.Context, database *sql.DB, conn *sql.Conn, migrations []Migration) error) error {
+	migrations, err := Migrations()
+	if err != nil {
+		return fmt.Errorf("failed to load migrations: %w", err)
+	}
+
+	dbPath, err := getDBPath()
+	if err != nil {
+		return fmt.Errorf("cannot get database path: %w", err)
+	}
+
+	database, err := sql.Open("sqlite", dbPath)
+	if err != nil {
+		return fmt.Errorf("cannot open database for migration: %w", err)
+	}
+	defer database.Close()
+
+	ctx := context.Background()
+	conn, err := database.Conn(ctx)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	if _, err := conn.ExecContext(ctx, "PRAGMA foreign_keys = OFF"); err != nil {
+		return err
+	}
+
+	if err := adoptLegacySchema(ctx, conn, migrations); err != nil {
+		return fmt.Errorf("failed to read schema version: %w", err)
+	}
+
+	return fn(ctx, database, conn, migrations)
+}
+
+// adoptLegacySchema creates schema_migrations and, for a database created
+// before it existed, records the migrations that database already has
+func adoptLegacySchema(ctx context.Context, conn *sql.Conn, migrations []Migration) error {
+	var exists int
+	err := conn.QueryRowContext(ctx,
+		`SELECT COUNT(*) FROM sqlite_master WHERE type = 'table' AND name = 'schema_migrations'`,
+	).Scan(&exists)
+	if err != nil {
+		return err
+	}
+	if exists > 0 {
+		return nil
+	}
+
+	txn, err := conn.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer txn.Rollback()
+
+	if _, err := txn.ExecContext(ctx, createSchemaMigrations); err != nil {
+		return err
+	}
+
+	for _, m := range migrations {
+		if m.Version >= len(legacyProbes) {
+			break
+		}
+		var count int
+		if err := txn.QueryRowContext(ctx, legacyProbes[m.Version]).Scan(&count); err != nil {
+			return err
+		}
+		if count == 0 {
+			break
+		}
+		if _, err := txn.ExecContext(ctx,
+			`INSERT INTO schema_migrations (version, name) VALUES (?, ?)`, m.Version, m.Name,
+		); err != nil {
+			return err
+		}
+	}
+
+	return txn.Commit()
+}
+
+// backupBeforeMigration keeps a copy of the database next to it, so a
+// failed or unwanted migration can be undone by restoring the file
+func backupBeforeMigration(database *sql.DB, label string) error {
+	backupPath, err := BackupPath(label)
+	if err != nil {
+		return err
+	}
+	if err := BackupTo(database, backupPath); err != nil {
+		return err
+	}
+	log.Println("✓ Backup written to", backupPath)
+	return nil
+}
+
+func applyMigration(ctx context.Context, conn *sql.Conn, m Migration, up bool) error {
+	txn, err := conn.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer txn.Rollback()
+
+	direction := "down"
+	body := m.Down
+	if up {
+		direction = "up"
+		body = m.Up
+	}
+
+	if _, err := txn.ExecContext(ctx, body); err != nil {
+		return fmt.Errorf("migration %04d_%s (%s) failed: %w", m.Version, m.Name, direction, err)
+	}
+
+	if up {
+		_, err = txn.ExecContext(ctx, `INSERT INTO schema_migrations (version, name) VALUES (?, ?)`, m.Version, m.Name)
+	} else {
+		_, err = txn.ExecContext(ctx, `DELETE FROM schema_migrations WHERE version = ?`, m.Version)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to record migration %04d_%s: %w", m.Version, m.Name, err)
+	}
+
+	if err := txn.Commit(); err != nil {
+		return err
+	}
+
+	log.Printf("✓ Migration %04d_%s %s", m.Version, m.Name, direction)
+	return nil
+}
+
+type queryRower interface {
+	QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row
+}
+
+// schemaVersion is the highest applied migration, 0 for a database
+// without schema_migrations
+func schemaVersion(ctx context.Context, db queryRower) (int, error) {
+	var exists int
+	err := db.QueryRowContext(ctx,
+		`SELECT COUNT(*) FROM sqlite_master WHERE type = 'table' AND name = 'schema_migrations'`,
+	).Scan(&exists)
+	if err != nil || exists == 0 {
+		return 0, err
+	}
+
+	var version int
+	err = db.QueryRowContext(ctx, `SELECT COALESCE(MAX(version), 0) FROM schema_migrations`).Scan(&version)
+	return version, err
+}
+
+func appliedMigrations(ctx context.Context, conn *sql.Conn) (map[int]time.Time, error) {
+	rows, err := conn.QueryContext(ctx, `SELECT version, applied_at FROM schema_migrations`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	applied := map[int]time.Time{}
+	for rows.Next() {
+		var version int
+		var appliedAt time.Time
+		if err := rows.Scan(&version, &appliedAt); err != nil {
+			return nil, err
+		}
+		applied[version] = appliedAt
+	}
+	return applied, rows.Err()
+}
+
+// checkSchemaVersion refuses databases migrated by a newer binary
+func checkSchemaVersion(db *sql.DB) error {
+	current, err := schemaVersion(context.Background(), db)
+	if err != nil {
+		return fmt.Errorf("failed to read schema version: %w", err)
+	}
+	if latest := LatestSchemaVersion(); current > latest {
+		return fmt.Errorf("%w (database %d, binary %d)", ErrSchemaTooNew, current, latest)
+	}
+	return nil
+}
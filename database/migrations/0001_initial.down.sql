@@ -0,0 +1,3 @@
+DROP TABLE secret_list;
+
+DROP TABLE project_list;
@@ -1,4 +1,4 @@
-CREATE TABLE IF NOT EXISTS project_list (
+CREATE TABLE project_list (
     id TEXT PRIMARY KEY,
     name TEXT NOT NULL UNIQUE,
     description TEXT,
@@ -6,7 +6,7 @@ CREATE TABLE IF NOT EXISTS project_list (
     updated_at DATETIME DEFAULT CURRENT_TIMESTAMP
 );
 
-CREATE TABLE IF NOT EXISTS secret_list (
+CREATE TABLE secret_list (
     id TEXT PRIMARY KEY,
     project_id TEXT NOT NULL,
     key TEXT NOT NULL,
@@ -19,4 +19,4 @@ CREATE TABLE IF NOT EXISTS secret_list (
         REFERENCES project_list(id)
         ON DELETE CASCADE,
     CONSTRAINT unique_project_key UNIQUE (project_id, key)
-);
\ No newline at end of file
+);
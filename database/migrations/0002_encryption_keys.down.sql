@@ -0,0 +1,3 @@
+-- Encrypted values cannot be read without their data key, decrypt them
+-- (or restore a backup) before rolling this back
+DROP TABLE encryption_keys;
@@ -0,0 +1,10 @@
+CREATE TABLE encryption_keys (
+    id TEXT PRIMARY KEY,
+    wrapped_key TEXT NOT NULL,
+    kdf TEXT NOT NULL,
+    salt TEXT NOT NULL DEFAULT '',
+    kdf_time INTEGER NOT NULL DEFAULT 0,
+    kdf_memory INTEGER NOT NULL DEFAULT 0,
+    kdf_threads INTEGER NOT NULL DEFAULT 0,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP
+);
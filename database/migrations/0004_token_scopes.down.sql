@@ -0,0 +1,7 @@
+DROP TABLE api_token_projects;
+
+ALTER TABLE api_tokens DROP COLUMN expires_at;
+
+ALTER TABLE api_tokens DROP COLUMN restricted;
+
+ALTER TABLE api_tokens DROP COLUMN scope;
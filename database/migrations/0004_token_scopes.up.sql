@@ -0,0 +1,20 @@
+-- Tokens created before scopes existed keep full access
+ALTER TABLE api_tokens ADD COLUMN scope TEXT NOT NULL DEFAULT 'admin';
+
+ALTER TABLE api_tokens ADD COLUMN restricted BOOLEAN NOT NULL DEFAULT 0;
+
+ALTER TABLE api_tokens ADD COLUMN expires_at DATETIME;
+
+CREATE TABLE api_token_projects (
+    token_id TEXT NOT NULL,
+    project_id TEXT NOT NULL,
+    PRIMARY KEY (token_id, project_id),
+    CONSTRAINT fk_token
+        FOREIGN KEY (token_id)
+        REFERENCES api_tokens(id)
+        ON DELETE CASCADE,
+    CONSTRAINT fk_project
+        FOREIGN KEY (project_id)
+        REFERENCES project_list(id)
+        ON DELETE CASCADE
+);
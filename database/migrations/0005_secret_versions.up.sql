@@ -0,0 +1,17 @@
+-- Every row is the state a secret had before it was changed, actor is who
+-- changed it
+CREATE TABLE secret_versions (
+    id TEXT PRIMARY KEY,
+    secret_id TEXT NOT NULL,
+    version INTEGER NOT NULL,
+    key TEXT NOT NULL,
+    value TEXT NOT NULL,
+    description TEXT,
+    actor TEXT NOT NULL,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT fk_secret
+        FOREIGN KEY (secret_id)
+        REFERENCES secret_list(id)
+        ON DELETE CASCADE,
+    CONSTRAINT unique_secret_version UNIQUE (secret_id, version)
+);
@@ -0,0 +1,40 @@
+-- Overrides have no place without environments, only base secrets and
+-- their history are kept
+DELETE FROM secret_versions
+WHERE
+    secret_id IN (
+        SELECT
+            id
+        FROM
+            secret_list
+        WHERE
+            environment != 'base'
+    );
+
+CREATE TABLE secret_list_old (
+    id TEXT PRIMARY KEY,
+    project_id TEXT NOT NULL,
+    key TEXT NOT NULL,
+    value TEXT NOT NULL,
+    description TEXT,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    updated_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT fk_project
+        FOREIGN KEY (project_id)
+        REFERENCES project_list(id)
+        ON DELETE CASCADE,
+    CONSTRAINT unique_project_key UNIQUE (project_id, key)
+);
+
+INSERT INTO
+    secret_list_old (id, project_id, key, value, description, created_at, updated_at)
+SELECT
+    id, project_id, key, value, description, created_at, updated_at
+FROM
+    secret_list
+WHERE
+    environment = 'base';
+
+DROP TABLE secret_list;
+
+ALTER TABLE secret_list_old RENAME TO secret_list;
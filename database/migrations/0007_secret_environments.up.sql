@@ -0,0 +1,32 @@
+-- The unique constraint moves from (project_id, key) to (project_id,
+-- environment, key), which SQLite can only do with a new table. Existing
+-- secrets keep their IDs and land in the base environment.
+--
+-- environment is 'base' or the name of a deployment stage whose values
+-- override the base ones
+CREATE TABLE secret_list_new (
+    id TEXT PRIMARY KEY,
+    project_id TEXT NOT NULL,
+    environment TEXT NOT NULL DEFAULT 'base',
+    key TEXT NOT NULL,
+    value TEXT NOT NULL,
+    description TEXT,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    updated_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT fk_project
+        FOREIGN KEY (project_id)
+        REFERENCES project_list(id)
+        ON DELETE CASCADE,
+    CONSTRAINT unique_project_key UNIQUE (project_id, environment, key)
+);
+
+INSERT INTO
+    secret_list_new (id, project_id, environment, key, value, description, created_at, updated_at)
+SELECT
+    id, project_id, 'base', key, value, description, created_at, updated_at
+FROM
+    secret_list;
+
+DROP TABLE secret_list;
+
+ALTER TABLE secret_list_new RENAME TO secret_list;
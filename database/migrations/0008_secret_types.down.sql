@@ -0,0 +1 @@
+ALTER TABLE secret_list DROP COLUMN type;
@@ -0,0 +1,4 @@
+-- type is 'text' for values injected as variables or 'file' for values
+-- (certificates, kubeconfigs) injected as a temporary file whose path is
+-- passed instead
+ALTER TABLE secret_list ADD COLUMN type TEXT NOT NULL DEFAULT 'text';
@@ -0,0 +1 @@
+ALTER TABLE secret_versions DROP COLUMN type;
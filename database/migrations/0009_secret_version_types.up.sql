@@ -0,0 +1,14 @@
+-- The type is kept with every version so a rollback restores it. Versions
+-- saved before this take the current type of their secret.
+ALTER TABLE secret_versions ADD COLUMN type TEXT NOT NULL DEFAULT 'text';
+
+UPDATE secret_versions
+SET
+    type = (
+        SELECT
+            type
+        FROM
+            secret_list
+        WHERE
+            secret_list.id = secret_versions.secret_id
+    );
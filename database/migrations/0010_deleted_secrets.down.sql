@@ -0,0 +1,37 @@
+DROP TABLE deleted_secrets;
+
+-- The history of deleted secrets cannot stay under the foreign key
+CREATE TABLE secret_versions_old (
+    id TEXT PRIMARY KEY,
+    secret_id TEXT NOT NULL,
+    version INTEGER NOT NULL,
+    key TEXT NOT NULL,
+    value TEXT NOT NULL,
+    description TEXT,
+    actor TEXT NOT NULL,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    type TEXT NOT NULL DEFAULT 'text',
+    CONSTRAINT fk_secret
+        FOREIGN KEY (secret_id)
+        REFERENCES secret_list(id)
+        ON DELETE CASCADE,
+    CONSTRAINT unique_secret_version UNIQUE (secret_id, version)
+);
+
+INSERT INTO
+    secret_versions_old (id, secret_id, version, key, value, description, actor, created_at, type)
+SELECT
+    id, secret_id, version, key, value, description, actor, created_at, type
+FROM
+    secret_versions
+WHERE
+    secret_id IN (
+        SELECT
+            id
+        FROM
+            secret_list
+    );
+
+DROP TABLE secret_versions;
+
+ALTER TABLE secret_versions_old RENAME TO secret_versions;
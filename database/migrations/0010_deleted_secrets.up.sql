@@ -0,0 +1,42 @@
+-- Versions outlive their secret, so a deleted secret can be restored. The
+-- foreign key that removed them with it can only go with a new table.
+CREATE TABLE secret_versions_new (
+    id TEXT PRIMARY KEY,
+    secret_id TEXT NOT NULL,
+    version INTEGER NOT NULL,
+    key TEXT NOT NULL,
+    value TEXT NOT NULL,
+    description TEXT,
+    actor TEXT NOT NULL,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    type TEXT NOT NULL DEFAULT 'text',
+    CONSTRAINT unique_secret_version UNIQUE (secret_id, version)
+);
+
+INSERT INTO
+    secret_versions_new (id, secret_id, version, key, value, description, actor, created_at, type)
+SELECT
+    id, secret_id, version, key, value, description, actor, created_at, type
+FROM
+    secret_versions;
+
+DROP TABLE secret_versions;
+
+ALTER TABLE secret_versions_new RENAME TO secret_versions;
+
+-- A deleted secret: where it was stored and the version holding the state
+-- it had when it was deleted. id is the ID the secret had, its versions
+-- still carry it.
+CREATE TABLE deleted_secrets (
+    id TEXT PRIMARY KEY,
+    project_id TEXT NOT NULL,
+    environment TEXT NOT NULL,
+    key TEXT NOT NULL,
+    version INTEGER NOT NULL,
+    actor TEXT NOT NULL,
+    deleted_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT fk_project
+        FOREIGN KEY (project_id)
+        REFERENCES project_list(id)
+        ON DELETE CASCADE
+);
@@ -0,0 +1,132 @@
+package database
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+
+	"github.com/Knightshrestha/Secret-Injector/config"
+)
+
+// Environment variables that pick the database
+const (
+	EnvDB    = "SECRET_INJECTOR_DB"
+	EnvVault = "SECRET_INJECTOR_VAULT"
+)
+
+// DBFlag and VaultFlag are set from the --db and --vault flags and take
+// precedence over the environment
+var DBFlag string
+var VaultFlag string
+
+var vaultNamePattern = regexp.MustCompile(`^[a-z0-9][a-z0-9_-]{0,31}$`)
+
+// Location is the database of the active vault and what chose it
+type Location struct {
+	// Vault is the vault name, empty for the default vault
+	Vault string
+	DB    string
+
+	// KeyFile is the master key file the config sets for the vault
+	KeyFile string
+
+	// Source is what picked DB: flag, env SECRET_INJECTOR_DB, config,
+	// legacy (si_data next to the executable) or default
+	Source string
+}
+
+// Locate resolves the database path. For the default vault the order is
+// --db, SECRET_INJECTOR_DB, db in the config file, an existing si_data next
+// to the executable, then the user data directory. A named vault (--vault
+// or SECRET_INJECTOR_VAULT) uses its entry in the config file, otherwise
+// vaults/NAME in the user data directory.
+func Locate() (Location, error) {
+	if DBFlag != "" && VaultFlag != "" {
+		return Location{}, errors.New("use either --db or --vault, not both")
+	}
+	if DBFlag != "" {
+		path, err := filepath.Abs(config.ExpandHome(DBFlag))
+		return Location{DB: path, Source: "flag"}, err
+	}
+
+	name := VaultFlag
+	if name == "" && os.Getenv(EnvDB) == "" {
+		name = os.Getenv(EnvVault)
+	}
+	if name != "" && !vaultNamePattern.MatchString(name) {
+		return Location{}, fmt.Errorf("invalid vault name %q, use lowercase letters, digits, - and _", name)
+	}
+
+	file, err := config.Load()
+	if err != nil {
+		return Location{}, err
+	}
+
+	if name != "" {
+		loc := Location{Vault: name, KeyFile: file.Vaults[name].KeyFile}
+		if db := file.Vaults[name].DB; db != "" {
+			loc.DB, loc.Source = db, "config"
+			return loc, nil
+		}
+
+		dataHome, err := config.DataHome()
+		if err != nil {
+			return Location{}, fmt.Errorf("cannot find data directory: %w", err)
+		}
+		loc.DB = filepath.Join(dataHome, config.AppDir, "vaults", name, "secrets.db")
+		loc.Source = "default"
+		return loc, nil
+	}
+
+	if db := os.Getenv(EnvDB); db != "" {
+		path, err := filepath.Abs(config.ExpandHome(db))
+		return Location{DB: path, Source: "env " + EnvDB}, err
+	}
+	if file.DB != "" {
+		return Location{DB: file.DB, Source: "config"}, nil
+	}
+
+	// Databases created before the path was configurable
+	if exePath, err := os.Executable(); err == nil {
+		legacy := filepath.Join(filepath.Dir(exePath), "si_data", "secrets.db")
+		if _, err := os.Stat(legacy); err == nil {
+			return Location{DB: legacy, Source: "legacy"}, nil
+		}
+	}
+
+	dataHome, err := config.DataHome()
+	if err != nil {
+		return Location{}, fmt.Errorf("cannot find data directory: %w", err)
+	}
+	return Location{DB: filepath.Join(dataHome, config.AppDir, "secrets.db"), Source: "default"}, nil
+}
+
+// DataDir returns the folder holding the database, where backups and the
+// self-signed certificate are kept too, creating it if missing
+func DataDir() (string, error) {
+	loc, err := Locate()
+	if err != nil {
+		return "", err
+	}
+
+	dataDir := filepath.Dir(loc.DB)
+	if err := os.MkdirAll(dataDir, 0700); err != nil {
+		return "", err
+	}
+	return dataDir, nil
+}
+
+func getDBPath() (string, error) {
+	loc, err := Locate()
+	if err != nil {
+		return "", err
+	}
+
+	// Create folder if missing
+	if err := os.MkdirAll(filepath.Dir(loc.DB), 0700); err != nil {
+		return "", err
+	}
+	return loc.DB, nil
+}
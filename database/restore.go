@@ -0,0 +1,125 @@
+package database
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+var sqliteHeader = []byte("SQLite format 3\x00")
+
+// ValidateBackup checks that path is an intact Secret Injector database
+// this binary can open and returns its schema version
+func ValidateBackup(path string) (int, error) {
+	header := make([]byte, len(sqliteHeader))
+	file, err := os.Open(path)
+	if err != nil {
+		return 0, err
+	}
+	_, err = file.Read(header)
+	file.Close()
+	if err != nil || !bytes.Equal(header, sqliteHeader) {
+		return 0, fmt.Errorf("not a SQLite database")
+	}
+
+	database, err := sql.Open("sqlite", path+"?mode=ro")
+	if err != nil {
+		return 0, err
+	}
+	defer database.Close()
+
+	ctx := context.Background()
+
+	var result string
+	if err := database.QueryRowContext(ctx, "PRAGMA integrity_check").Scan(&result); err != nil {
+		return 0, fmt.Errorf("integrity check failed: %w", err)
+	}
+	if result != "ok" {
+		return 0, fmt.Errorf("integrity check failed: %s", result)
+	}
+
+	var tables int
+	err = database.QueryRowContext(ctx,
+		`SELECT COUNT(*) FROM sqlite_master WHERE type = 'table' AND name = 'project_list'`,
+	).Scan(&tables)
+	if err != nil {
+		return 0, err
+	}
+	if tables == 0 {
+		return 0, fmt.Errorf("not a secret_injector database")
+	}
+
+	if err := checkSchemaVersion(database); err != nil {
+		return 0, err
+	}
+	return schemaVersion(ctx, database)
+}
+
+// BackupCurrent backs up the database file as it is, whatever its schema
+// version, and returns the backup path. It returns "" when there is no
+// database yet.
+func BackupCurrent(label string) (string, error) {
+	dbPath, err := getDBPath()
+	if err != nil {
+		return "", fmt.Errorf("cannot get database path: %w", err)
+	}
+	if _, err := os.Stat(dbPath); errors.Is(err, os.ErrNotExist) {
+		return "", nil
+	}
+
+	current, err := sql.Open("sqlite", dbPath)
+	if err != nil {
+		return "", err
+	}
+	defer current.Close()
+
+	backupPath, err := BackupPath(label)
+	if err != nil {
+		return "", err
+	}
+	if err := BackupTo(current, backupPath); err != nil {
+		return "", err
+	}
+	return backupPath, nil
+}
+
+// ReplaceDatabase moves src over the database file. src must be on the same
+// filesystem, e.g. a temporary file in DataDir. The current WAL is folded
+// into the old file first and removed, so none of it is replayed onto the
+// new one.
+func ReplaceDatabase(src string) error {
+	dbPath, err := getDBPath()
+	if err != nil {
+		return fmt.Errorf("cannot get database path: %w", err)
+	}
+
+	if _, err := os.Stat(dbPath); err == nil {
+		current, err := sql.Open("sqlite", dbPath)
+		if err != nil {
+			return err
+		}
+		_, err = current.Exec(`PRAGMA wal_checkpoint(TRUNCATE)`)
+		current.Close()
+		if err != nil {
+			return fmt.Errorf("failed to checkpoint database: %w", err)
+		}
+	}
+
+	for _, suffix := range []string{"-wal", "-shm"} {
+		if err := os.Remove(dbPath + suffix); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return fmt.Errorf("failed to remove %s: %w", filepath.Base(dbPath+suffix), err)
+		}
+	}
+
+	if err := os.Chmod(src, 0600); err != nil {
+		return err
+	}
+	if err := os.Rename(src, dbPath); err != nil {
+		return fmt.Errorf("failed to move database into place: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,95 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"path/filepath"
+	"sort"
+
+	"github.com/Knightshrestha/Secret-Injector/vault"
+)
+
+// Scrub rewrites the database without free pages and empties the WAL, so
+// values that were replaced, such as plaintext sealed by migrate-encrypt,
+// leave no copy in the files. db must be the write connection.
+func Scrub(db *sql.DB) error {
+	for _, statement := range []string{
+		"PRAGMA secure_delete=ON",
+		"VACUUM",
+		"PRAGMA wal_checkpoint(TRUNCATE)",
+	} {
+		if _, err := db.Exec(statement); err != nil {
+			return fmt.Errorf("%s failed: %w", statement, err)
+		}
+	}
+	return nil
+}
+
+// PlaintextBackups returns the backups next to the database that still hold
+// unencrypted secret values, such as the one taken before migrating a
+// database that was never encrypted
+func PlaintextBackups() ([]string, error) {
+	dbPath, err := getDBPath()
+	if err != nil {
+		return nil, fmt.Errorf("cannot get database path: %w", err)
+	}
+
+	matches, err := filepath.Glob(filepath.Join(filepath.Dir(dbPath), filepath.Base(dbPath)+".*.bak"))
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(matches)
+
+	var plaintext []string
+	for _, path := range matches {
+		found, err := holdsPlaintext(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read backup %s: %w", path, err)
+		}
+		if found {
+			plaintext = append(plaintext, path)
+		}
+	}
+	return plaintext, nil
+}
+
+func holdsPlaintext(path string) (bool, error) {
+	backup, err := sql.Open("sqlite", path+"?mode=ro")
+	if err != nil {
+		return false, err
+	}
+	defer backup.Close()
+
+	ctx := context.Background()
+
+	// Older backups have no version history
+	query := `SELECT value FROM secret_list`
+	var versions int
+	err = backup.QueryRowContext(ctx,
+		`SELECT COUNT(*) FROM sqlite_master WHERE type = 'table' AND name = 'secret_versions'`,
+	).Scan(&versions)
+	if err != nil {
+		return false, err
+	}
+	if versions > 0 {
+		query += ` UNION ALL SELECT value FROM secret_versions`
+	}
+
+	rows, err := backup.QueryContext(ctx, query)
+	if err != nil {
+		return false, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var value string
+		if err := rows.Scan(&value); err != nil {
+			return false, err
+		}
+		if !vault.IsSealed(value) {
+			return true, nil
+		}
+	}
+	return false, rows.Err()
+}
@@ -0,0 +1,97 @@
+-- name: CreateAuditEntry :one
+INSERT INTO
+    audit_log (
+        created_at,
+        actor,
+        source,
+        action,
+        project_id,
+        secret_id,
+        secret_key,
+        prev_hash,
+        hash
+    )
+VALUES
+    (
+        sqlc.arg ('created_at'),
+        sqlc.arg ('actor'),
+        sqlc.arg ('source'),
+        sqlc.arg ('action'),
+        sqlc.narg ('project_id'),
+        sqlc.narg ('secret_id'),
+        sqlc.narg ('secret_key'),
+        sqlc.arg ('prev_hash'),
+        sqlc.arg ('hash')
+    ) RETURNING *;
+
+-- name: GetLastAuditEntry :one
+SELECT
+    *
+FROM
+    audit_log
+ORDER BY
+    id DESC
+LIMIT
+    1;
+
+-- name: GetAllAuditEntries :many
+SELECT
+    *
+FROM
+    audit_log
+ORDER BY
+    id;
+
+-- name: GetAuditEntries :many
+SELECT
+    *
+FROM
+    audit_log
+WHERE
+    (
+        sqlc.narg ('actor') IS NULL
+        OR actor = sqlc.narg ('actor')
+    )
+    AND (
+        sqlc.narg ('source') IS NULL
+        OR source = sqlc.narg ('source')
+    )
+    AND (
+        sqlc.narg ('action') IS NULL
+        OR action = sqlc.narg ('action')
+    )
+    AND (
+        sqlc.narg ('project_id') IS NULL
+        OR project_id = sqlc.narg ('project_id')
+    )
+    AND (
+        sqlc.narg ('secret_key') IS NULL
+        OR secret_key = sqlc.narg ('secret_key')
+    )
+    AND (
+        sqlc.narg ('since') IS NULL
+        OR created_at >= sqlc.narg ('since')
+    )
+    AND (
+        sqlc.narg ('until') IS NULL
+        OR created_at < sqlc.narg ('until')
+    )
+    AND (
+        sqlc.narg ('before_id') IS NULL
+        OR id < sqlc.narg ('before_id')
+    )
+ORDER BY
+    id DESC
+LIMIT
+    sqlc.arg ('limit');
+
+-- name: GetAuditEntriesAfter :many
+SELECT
+    *
+FROM
+    audit_log
+WHERE
+    id > sqlc.arg ('after_id')
+    AND source = sqlc.arg ('source')
+ORDER BY
+    id;
@@ -0,0 +1,59 @@
+-- name: CreateDeletedSecret :exec
+INSERT INTO
+    deleted_secrets (id, project_id, environment, key, version, actor)
+VALUES
+    (
+        sqlc.arg ('id'),
+        sqlc.arg ('project_id'),
+        sqlc.arg ('environment'),
+        sqlc.arg ('key'),
+        sqlc.arg ('version'),
+        sqlc.arg ('actor')
+    );
+
+-- name: GetDeletedSecretByID :one
+SELECT
+    *
+FROM
+    deleted_secrets
+WHERE
+    id = sqlc.arg ('id');
+
+-- name: GetDeletedSecretsByProjectID :many
+SELECT
+    *
+FROM
+    deleted_secrets
+WHERE
+    project_id = sqlc.arg ('project_id')
+ORDER BY
+    deleted_at DESC,
+    rowid DESC;
+
+-- name: RestoreDeletedSecret :one
+INSERT INTO
+    secret_list (id, project_id, environment, key, value, description, type)
+SELECT
+    deleted_secrets.id,
+    deleted_secrets.project_id,
+    deleted_secrets.environment,
+    secret_versions.key,
+    secret_versions.value,
+    secret_versions.description,
+    secret_versions.type
+FROM
+    deleted_secrets
+    JOIN secret_versions ON secret_versions.secret_id = deleted_secrets.id
+    AND secret_versions.version = deleted_secrets.version
+WHERE
+    deleted_secrets.id = sqlc.arg ('id') RETURNING *;
+
+-- name: DeleteDeletedSecret :exec
+DELETE FROM deleted_secrets
+WHERE
+    id = sqlc.arg ('id');
+
+-- name: DeleteDeletedSecretsInProject :exec
+DELETE FROM deleted_secrets
+WHERE
+    project_id = sqlc.arg ('project_id');
@@ -0,0 +1,48 @@
+-- name: CreateEncryptionKey :one
+INSERT INTO
+    encryption_keys (
+        id,
+        wrapped_key,
+        kdf,
+        salt,
+        kdf_time,
+        kdf_memory,
+        kdf_threads
+    )
+VALUES
+    (
+        sqlc.arg ('id'),
+        sqlc.arg ('wrapped_key'),
+        sqlc.arg ('kdf'),
+        sqlc.arg ('salt'),
+        sqlc.arg ('kdf_time'),
+        sqlc.arg ('kdf_memory'),
+        sqlc.arg ('kdf_threads')
+    ) RETURNING *;
+
+-- name: GetActiveEncryptionKey :one
+SELECT
+    *
+FROM
+    encryption_keys
+ORDER BY
+    created_at DESC
+LIMIT
+    1;
+
+-- name: UpdateEncryptionKeyWrapping :exec
+UPDATE encryption_keys
+SET
+    wrapped_key = sqlc.arg ('wrapped_key'),
+    kdf = sqlc.arg ('kdf'),
+    salt = sqlc.arg ('salt'),
+    kdf_time = sqlc.arg ('kdf_time'),
+    kdf_memory = sqlc.arg ('kdf_memory'),
+    kdf_threads = sqlc.arg ('kdf_threads')
+WHERE
+    id = sqlc.arg ('id');
+
+-- name: DeleteEncryptionKey :exec
+DELETE FROM encryption_keys
+WHERE
+    id = sqlc.arg ('id');
@@ -1,13 +1,15 @@
 -- name: CreateSecret :one
 INSERT INTO
-    secret_list (id, project_id, key, value, description)
+    secret_list (id, project_id, environment, key, value, description, type)
 VALUES
     (
         sqlc.arg ('id'),
         sqlc.arg ('project_id'),
+        sqlc.arg ('environment'),
         sqlc.arg ('key'),
         sqlc.arg ('value'),
-        sqlc.narg ('description')
+        sqlc.narg ('description'),
+        sqlc.arg ('type')
     ) RETURNING *;
 
 -- name: GetSecretByID :one
@@ -38,6 +40,7 @@ SET
     key = COALESCE(sqlc.narg ('key'), key),
     description = COALESCE(sqlc.narg ('description'), description),
     value = COALESCE(sqlc.narg ('value'), value),
+    type = COALESCE(sqlc.narg ('type'), type),
     updated_at = CURRENT_TIMESTAMP
 WHERE
     id = sqlc.arg ('id') RETURNING *;
@@ -50,4 +53,22 @@ WHERE
 -- name: DeleteAllSecretsInProjects :exec
 DELETE FROM secret_list
 WHERE
-    project_id = sqlc.arg ('project_id');
\ No newline at end of file
+    project_id = sqlc.arg ('project_id');
+
+-- name: SetSecretValue :exec
+UPDATE secret_list
+SET
+    value = sqlc.arg ('value')
+WHERE
+    id = sqlc.arg ('id');
+
+-- name: RestoreSecret :one
+UPDATE secret_list
+SET
+    key = sqlc.arg ('key'),
+    value = sqlc.arg ('value'),
+    description = sqlc.narg ('description'),
+    type = sqlc.arg ('type'),
+    updated_at = CURRENT_TIMESTAMP
+WHERE
+    id = sqlc.arg ('id') RETURNING *;
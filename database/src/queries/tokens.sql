@@ -0,0 +1,81 @@
+-- name: CreateApiToken :one
+INSERT INTO
+    api_tokens (
+        id,
+        name,
+        token_hash,
+        scope,
+        restricted,
+        expires_at
+    )
+VALUES
+    (
+        sqlc.arg ('id'),
+        sqlc.arg ('name'),
+        sqlc.arg ('token_hash'),
+        sqlc.arg ('scope'),
+        sqlc.arg ('restricted'),
+        sqlc.narg ('expires_at')
+    ) RETURNING *;
+
+-- name: GetApiTokenByHash :one
+SELECT
+    *
+FROM
+    api_tokens
+WHERE
+    token_hash = sqlc.arg ('token_hash');
+
+-- name: GetAllApiTokens :many
+SELECT
+    *
+FROM
+    api_tokens
+ORDER BY
+    created_at;
+
+-- name: CountApiTokens :one
+SELECT
+    COUNT(*)
+FROM
+    api_tokens;
+
+-- name: TouchApiToken :exec
+UPDATE api_tokens
+SET
+    last_used_at = CURRENT_TIMESTAMP
+WHERE
+    id = sqlc.arg ('id')
+    AND (
+        last_used_at IS NULL
+        OR last_used_at < datetime ('now', '-1 minute')
+    );
+
+-- name: DeleteApiToken :exec
+DELETE FROM api_tokens
+WHERE
+    id = sqlc.arg ('id');
+
+-- name: AddApiTokenProject :exec
+INSERT INTO
+    api_token_projects (token_id, project_id)
+VALUES
+    (sqlc.arg ('token_id'), sqlc.arg ('project_id'));
+
+-- name: GetApiTokenProjectIDs :many
+SELECT
+    project_id
+FROM
+    api_token_projects
+WHERE
+    token_id = sqlc.arg ('token_id');
+
+-- name: DeleteApiTokenProjects :exec
+DELETE FROM api_token_projects
+WHERE
+    token_id = sqlc.arg ('token_id');
+
+-- name: DeleteApiTokenProjectsByProject :exec
+DELETE FROM api_token_projects
+WHERE
+    project_id = sqlc.arg ('project_id');
@@ -0,0 +1,74 @@
+-- name: SnapshotSecretVersion :one
+INSERT INTO
+    secret_versions (id, secret_id, version, key, value, description, actor, type)
+SELECT
+    sqlc.arg ('id'),
+    secret_list.id,
+    (
+        SELECT
+            COALESCE(MAX(version), 0) + 1
+        FROM
+            secret_versions
+        WHERE
+            secret_id = sqlc.arg ('secret_id')
+    ),
+    secret_list.key,
+    secret_list.value,
+    secret_list.description,
+    sqlc.arg ('actor'),
+    secret_list.type
+FROM
+    secret_list
+WHERE
+    secret_list.id = sqlc.arg ('secret_id') RETURNING *;
+
+-- name: GetSecretVersions :many
+SELECT
+    *
+FROM
+    secret_versions
+WHERE
+    secret_id = sqlc.arg ('secret_id')
+ORDER BY
+    version DESC;
+
+-- name: GetSecretVersion :one
+SELECT
+    *
+FROM
+    secret_versions
+WHERE
+    secret_id = sqlc.arg ('secret_id')
+    AND version = sqlc.arg ('version');
+
+-- name: GetAllSecretVersions :many
+SELECT
+    *
+FROM
+    secret_versions;
+
+-- name: SetSecretVersionValue :exec
+UPDATE secret_versions
+SET
+    value = sqlc.arg ('value')
+WHERE
+    id = sqlc.arg ('id');
+
+-- name: DeleteSecretVersionsInProject :exec
+DELETE FROM secret_versions
+WHERE
+    secret_id IN (
+        SELECT
+            id
+        FROM
+            secret_list
+        WHERE
+            project_id = sqlc.arg ('project_id')
+        UNION
+        SELECT
+            id
+        FROM
+            deleted_secrets
+        WHERE
+            project_id = sqlc.arg ('project_id')
+    );
@@ -0,0 +1,25 @@
+package exporter
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+)
+
+// formatDocker writes a `docker run --env-file` file. Docker takes everything
+// after the first "=" literally and has no quoting or line continuation, so
+// multiline values cannot be represented and are rejected.
+func formatDocker(keys []string, secrets map[string]string) ([]byte, error) {
+	var b bytes.Buffer
+	for _, key := range keys {
+		value := secrets[key]
+		if strings.ContainsAny(value, "\n\r") {
+			return nil, fmt.Errorf("secret %s contains a newline, which docker env-files cannot represent", key)
+		}
+		b.WriteString(key)
+		b.WriteByte('=')
+		b.WriteString(value)
+		b.WriteByte('\n')
+	}
+	return b.Bytes(), nil
+}
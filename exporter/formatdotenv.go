@@ -0,0 +1,55 @@
+package exporter
+
+import (
+	"bytes"
+	"strings"
+)
+
+// formatDotenv writes KEY=VALUE lines. Plain values are left bare, values
+// without single quotes or newlines are single-quoted (taken literally by
+// dotenv parsers) and everything else is double-quoted with escapes.
+func formatDotenv(keys []string, secrets map[string]string) []byte {
+	var b bytes.Buffer
+	for _, key := range keys {
+		b.WriteString(key)
+		b.WriteByte('=')
+		b.WriteString(quoteDotenv(secrets[key]))
+		b.WriteByte('\n')
+	}
+	return b.Bytes()
+}
+
+func quoteDotenv(value string) string {
+	if isPlain(value) {
+		return value
+	}
+
+	if !strings.ContainsAny(value, "'\n\r") {
+		return "'" + value + "'"
+	}
+
+	replacer := strings.NewReplacer(
+		`\`, `\\`,
+		`"`, `\"`,
+		"\n", `\n`,
+		"\r", `\r`,
+		"$", `\$`,
+	)
+	return `"` + replacer.Replace(value) + `"`
+}
+
+// isPlain reports whether a value is safe to write without any quoting
+func isPlain(value string) bool {
+	if value == "" {
+		return false
+	}
+	for _, r := range value {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9':
+		case strings.ContainsRune("_-./:@,+%", r):
+		default:
+			return false
+		}
+	}
+	return true
+}
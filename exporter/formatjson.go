@@ -0,0 +1,18 @@
+package exporter
+
+import (
+	"bytes"
+	"encoding/json"
+)
+
+// formatJSON writes a flat object, encoding/json sorts map keys
+func formatJSON(secrets map[string]string) ([]byte, error) {
+	var b bytes.Buffer
+	enc := json.NewEncoder(&b)
+	enc.SetEscapeHTML(false)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(secrets); err != nil {
+		return nil, err
+	}
+	return b.Bytes(), nil
+}
@@ -0,0 +1,20 @@
+package exporter
+
+import (
+	"bytes"
+	"strings"
+)
+
+// formatShell writes POSIX `export KEY='VALUE'` lines meant to be sourced.
+// Single quotes keep everything literal, embedded ones become '\''.
+func formatShell(keys []string, secrets map[string]string) []byte {
+	var b bytes.Buffer
+	for _, key := range keys {
+		b.WriteString("export ")
+		b.WriteString(key)
+		b.WriteString("='")
+		b.WriteString(strings.ReplaceAll(secrets[key], "'", `'\''`))
+		b.WriteString("'\n")
+	}
+	return b.Bytes()
+}
@@ -0,0 +1,34 @@
+package exporter
+
+import (
+	"bytes"
+	"encoding/json"
+)
+
+// formatYAML writes a flat mapping with every value double-quoted. JSON
+// string escapes are a subset of YAML double-quoted escapes, so multiline
+// values and special characters survive a round trip.
+func formatYAML(keys []string, secrets map[string]string) ([]byte, error) {
+	var b bytes.Buffer
+	for _, key := range keys {
+		quoted, err := quoteJSON(secrets[key])
+		if err != nil {
+			return nil, err
+		}
+		b.WriteString(key)
+		b.WriteString(": ")
+		b.Write(quoted)
+		b.WriteByte('\n')
+	}
+	return b.Bytes(), nil
+}
+
+func quoteJSON(value string) ([]byte, error) {
+	var b bytes.Buffer
+	enc := json.NewEncoder(&b)
+	enc.SetEscapeHTML(false)
+	if err := enc.Encode(value); err != nil {
+		return nil, err
+	}
+	return bytes.TrimRight(b.Bytes(), "\n"), nil
+}
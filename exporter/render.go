@@ -0,0 +1,31 @@
+package exporter
+
+import (
+	"fmt"
+	"sort"
+)
+
+// Render serializes the secrets in the given format, keys are sorted so the
+// output is stable between runs
+func Render(format Format, secrets map[string]string) ([]byte, error) {
+	keys := make([]string, 0, len(secrets))
+	for key := range secrets {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	switch format {
+	case FormatDotenv:
+		return formatDotenv(keys, secrets), nil
+	case FormatJSON:
+		return formatJSON(secrets)
+	case FormatYAML:
+		return formatYAML(keys, secrets)
+	case FormatShell:
+		return formatShell(keys, secrets), nil
+	case FormatDocker:
+		return formatDocker(keys, secrets)
+	default:
+		return nil, fmt.Errorf("unsupported format %q", format)
+	}
+}
@@ -0,0 +1,28 @@
+package exporter
+
+import (
+	"fmt"
+	"strings"
+)
+
+type Format string
+
+const (
+	FormatDotenv Format = "dotenv"
+	FormatJSON   Format = "json"
+	FormatYAML   Format = "yaml"
+	FormatShell  Format = "shell"
+	FormatDocker Format = "docker"
+)
+
+var Formats = []Format{FormatDotenv, FormatJSON, FormatYAML, FormatShell, FormatDocker}
+
+// ParseFormat validates a --format value
+func ParseFormat(s string) (Format, error) {
+	for _, f := range Formats {
+		if string(f) == strings.ToLower(s) {
+			return f, nil
+		}
+	}
+	return "", fmt.Errorf("unknown format %q (expected one of: dotenv, json, yaml, shell, docker)", s)
+}
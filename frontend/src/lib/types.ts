@@ -9,17 +9,27 @@ export interface ProjectItem {
 export interface SecretItem {
 	id: string;
 	project_id: string;
+	environment: string; // 'base' or the environment overriding it
 	description: null | string;
 	key: string;
-	value: string;
+	value: string; // As stored, ${KEY} references unresolved
+	resolved_value?: null | string;
+	resolve_error?: string;
+	type: 'text' | 'file'; // A file secret is injected as a temporary file
 	created_at: string;
 	updated_at: string;
 }
 
+export const BASE_ENVIRONMENT = 'base';
+
+export const SECRET_TYPE_TEXT = 'text';
+export const SECRET_TYPE_FILE = 'file';
+
 export interface SSE_CHANGE<T> {
-	type: 'create' | 'update' | 'delete' | 'ping';
+	type: 'create' | 'update' | 'delete' | 'batch' | 'ping';
 	timestamp: string;
-	data: T;
+	data: T; // Only project_id is set on batch events
+	changes?: SSE_CHANGE<T>[]; // The changes of a batch event
 }
 
 export type ProjectChange = SSE_CHANGE<ProjectItem>;
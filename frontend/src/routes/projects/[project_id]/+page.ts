@@ -1,9 +1,9 @@
 import { error } from '@sveltejs/kit';
 import type { PageLoad } from './$types';
 import { apiEndpoint } from '$lib/url_endpoint';
-import type { ProjectItem, SecretItem } from '$lib/types';
+import { BASE_ENVIRONMENT, type ProjectItem, type SecretItem } from '$lib/types';
 
-export const load = (async ({ params, fetch }) => {
+export const load = (async ({ params, fetch, url }) => {
 	if (!params.project_id) {
 		throw error(404, {
 			message: 'Project ID not provided'
@@ -11,21 +11,39 @@ export const load = (async ({ params, fetch }) => {
 		});
 	}
 
+	const environment = url.searchParams.get('env') || BASE_ENVIRONMENT;
+
 	const projectRequest = await fetch(apiEndpoint(`/projects/${params.project_id}`));
 
 	if (projectRequest.ok) {
 		const project: ProjectItem = await projectRequest.json();
 
-		const secretRequest = await fetch(apiEndpoint(`/projects/${project.id}/secrets`));
+		const [environmentRequest, secretRequest] = await Promise.all([
+			fetch(apiEndpoint(`/projects/${project.id}/environments`)),
+			fetch(
+				apiEndpoint(
+					`/projects/${project.id}/environments/${encodeURIComponent(environment)}/secrets`
+				)
+			)
+		]);
 
-		if (secretRequest.ok) {
+		if (environmentRequest.ok && secretRequest.ok) {
+			const environments: string[] = await environmentRequest.json();
 			const secrets: SecretItem[] = await secretRequest.json();
+
+			// An environment without overrides yet is still selectable
+			if (!environments.includes(environment)) {
+				environments.push(environment);
+			}
+
 			return {
 				secrets,
-				project
+				project,
+				environments,
+				environment
 			};
 		} else {
-			throw error(secretRequest.status);
+			throw error(secretRequest.ok ? environmentRequest.status : secretRequest.status);
 		}
 	} else {
 		throw error(projectRequest.status, await projectRequest.json());
@@ -0,0 +1,40 @@
+package importer
+
+import "fmt"
+
+// Parse reads the entries of a file. A key repeated later in the file keeps
+// its first position and takes the last value.
+func Parse(format Format, data []byte) ([]Entry, error) {
+	var entries []Entry
+	var err error
+
+	switch format {
+	case FormatDotenv:
+		entries, err = parseDotenv(data)
+	case FormatJSON:
+		entries, err = parseJSON(data)
+	case FormatYAML:
+		entries, err = parseYAML(data)
+	default:
+		return nil, fmt.Errorf("unsupported format %q", format)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return dedupe(entries), nil
+}
+
+func dedupe(entries []Entry) []Entry {
+	index := make(map[string]int)
+	var unique []Entry
+	for _, entry := range entries {
+		if i, ok := index[entry.Key]; ok {
+			unique[i].Value = entry.Value
+			continue
+		}
+		index[entry.Key] = len(unique)
+		unique = append(unique, entry)
+	}
+	return unique
+}
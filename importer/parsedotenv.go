@@ -0,0 +1,142 @@
+package importer
+
+import (
+	"fmt"
+	"strings"
+)
+
+// parseDotenv reads KEY=VALUE lines the way export writes them and most
+// dotenv tools accept them. Blank lines, # comments and an `export` prefix
+// are skipped. Unquoted values end at the line end or at a " #" comment,
+// single-quoted values are literal and double-quoted ones understand \n, \r,
+// \t, \", \\ and \$. Both quoted forms may span several lines.
+func parseDotenv(data []byte) ([]Entry, error) {
+	text := strings.TrimPrefix(string(data), "\ufeff")
+	text = strings.ReplaceAll(text, "\r\n", "\n")
+
+	line := 1
+	consume := func(n int) {
+		line += strings.Count(text[:n], "\n")
+		text = text[n:]
+	}
+
+	var entries []Entry
+	for len(text) > 0 {
+		current := strings.TrimSpace(lineOf(text))
+		if current == "" || strings.HasPrefix(current, "#") {
+			consume(nextLine(text))
+			continue
+		}
+
+		eq := strings.IndexByte(current, '=')
+		if eq < 0 {
+			return nil, fmt.Errorf("line %d: expected KEY=VALUE", line)
+		}
+		key := strings.TrimSpace(current[:eq])
+		if fields := strings.Fields(key); len(fields) == 2 && fields[0] == "export" {
+			key = fields[1]
+		}
+		if key == "" || strings.ContainsAny(key, " \t") {
+			return nil, fmt.Errorf("line %d: invalid key %q", line, key)
+		}
+
+		// Skip to the value, the first = of the line is the one found above
+		consume(strings.IndexByte(text, '=') + 1)
+		consume(len(text) - len(strings.TrimLeft(text, " \t")))
+
+		var value string
+		switch {
+		case strings.HasPrefix(text, `"`):
+			start := line
+			unquoted, n, ok := readDoubleQuoted(text[1:])
+			if !ok {
+				return nil, fmt.Errorf("line %d: %s: missing closing \"", start, key)
+			}
+			value = unquoted
+			consume(n + 1)
+
+		case strings.HasPrefix(text, `'`):
+			end := strings.IndexByte(text[1:], '\'')
+			if end < 0 {
+				return nil, fmt.Errorf("line %d: %s: missing closing '", line, key)
+			}
+			value = text[1 : end+1]
+			consume(end + 2)
+
+		default:
+			raw := lineOf(text)
+			value = strings.TrimSpace(stripComment(raw))
+			consume(len(raw))
+		}
+
+		// Only a comment may follow a quoted value
+		if rest := strings.TrimSpace(lineOf(text)); rest != "" && !strings.HasPrefix(rest, "#") {
+			return nil, fmt.Errorf("line %d: %s: unexpected %q after the value", line, key, rest)
+		}
+		consume(nextLine(text))
+
+		entries = append(entries, Entry{Key: key, Value: value})
+	}
+
+	return entries, nil
+}
+
+// readDoubleQuoted unescapes s up to the closing quote and returns the value
+// and the number of bytes read, including the quote
+func readDoubleQuoted(s string) (string, int, bool) {
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		switch s[i] {
+		case '"':
+			return b.String(), i + 1, true
+		case '\\':
+			if i+1 == len(s) {
+				return "", 0, false
+			}
+			i++
+			switch s[i] {
+			case 'n':
+				b.WriteByte('\n')
+			case 'r':
+				b.WriteByte('\r')
+			case 't':
+				b.WriteByte('\t')
+			case '"', '\\', '$':
+				b.WriteByte(s[i])
+			default:
+				// Unknown escapes are kept as written
+				b.WriteByte('\\')
+				b.WriteByte(s[i])
+			}
+		default:
+			b.WriteByte(s[i])
+		}
+	}
+	return "", 0, false
+}
+
+// stripComment cuts an unquoted value at a # preceded by whitespace
+func stripComment(value string) string {
+	for i := 1; i < len(value); i++ {
+		if value[i] == '#' && (value[i-1] == ' ' || value[i-1] == '\t') {
+			return value[:i]
+		}
+	}
+	return value
+}
+
+// lineOf returns s up to, not including, the first newline
+func lineOf(s string) string {
+	if end := strings.IndexByte(s, '\n'); end >= 0 {
+		return s[:end]
+	}
+	return s
+}
+
+// nextLine returns the offset of the line after the first one in s
+func nextLine(s string) int {
+	if end := strings.IndexByte(s, '\n'); end >= 0 {
+		return end + 1
+	}
+	return len(s)
+}
@@ -0,0 +1,60 @@
+package importer
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+)
+
+// parseJSON reads a flat object. Numbers and booleans are kept as written,
+// nested objects, arrays and null are rejected.
+func parseJSON(data []byte) ([]Entry, error) {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+
+	tok, err := dec.Token()
+	if err != nil {
+		return nil, fmt.Errorf("invalid JSON: %w", err)
+	}
+	if delim, ok := tok.(json.Delim); !ok || delim != '{' {
+		return nil, fmt.Errorf("expected a JSON object of keys and values")
+	}
+
+	var entries []Entry
+	for dec.More() {
+		tok, err := dec.Token()
+		if err != nil {
+			return nil, fmt.Errorf("invalid JSON: %w", err)
+		}
+		key := tok.(string)
+
+		var value any
+		if err := dec.Decode(&value); err != nil {
+			return nil, fmt.Errorf("invalid JSON: %w", err)
+		}
+
+		switch v := value.(type) {
+		case string:
+			entries = append(entries, Entry{Key: key, Value: v})
+		case json.Number:
+			entries = append(entries, Entry{Key: key, Value: v.String()})
+		case bool:
+			entries = append(entries, Entry{Key: key, Value: strconv.FormatBool(v)})
+		case nil:
+			return nil, fmt.Errorf("%s: null is not a value", key)
+		default:
+			return nil, fmt.Errorf("%s: nested values are not supported", key)
+		}
+	}
+
+	if _, err := dec.Token(); err != nil {
+		return nil, fmt.Errorf("invalid JSON: %w", err)
+	}
+	if _, err := dec.Token(); err != io.EOF {
+		return nil, fmt.Errorf("invalid JSON: unexpected data after the object")
+	}
+
+	return entries, nil
+}
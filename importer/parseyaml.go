@@ -0,0 +1,47 @@
+package importer
+
+import (
+	"fmt"
+
+	"gopkg.in/yaml.v3"
+)
+
+// parseYAML reads a flat mapping of scalars, in document order
+func parseYAML(data []byte) ([]Entry, error) {
+	var doc yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf("invalid YAML: %w", err)
+	}
+
+	// An empty file has no document
+	if len(doc.Content) == 0 {
+		return nil, nil
+	}
+
+	mapping := doc.Content[0]
+	if mapping.Kind != yaml.MappingNode {
+		return nil, fmt.Errorf("expected a YAML mapping of keys and values")
+	}
+
+	var entries []Entry
+	for i := 0; i+1 < len(mapping.Content); i += 2 {
+		key, value := mapping.Content[i], mapping.Content[i+1]
+		if value.Kind == yaml.AliasNode {
+			value = value.Alias
+		}
+
+		if key.Kind != yaml.ScalarNode {
+			return nil, fmt.Errorf("line %d: keys must be plain strings", key.Line)
+		}
+		if value.Kind != yaml.ScalarNode {
+			return nil, fmt.Errorf("line %d: %s: nested values are not supported", value.Line, key.Value)
+		}
+		if value.Tag == "!!null" {
+			return nil, fmt.Errorf("line %d: %s: null is not a value", value.Line, key.Value)
+		}
+
+		entries = append(entries, Entry{Key: key.Value, Value: value.Value})
+	}
+
+	return entries, nil
+}
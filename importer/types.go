@@ -0,0 +1,46 @@
+package importer
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+)
+
+type Format string
+
+const (
+	FormatDotenv Format = "dotenv"
+	FormatJSON   Format = "json"
+	FormatYAML   Format = "yaml"
+)
+
+var Formats = []Format{FormatDotenv, FormatJSON, FormatYAML}
+
+// Entry is a key and its value in the order they appear in the file
+type Entry struct {
+	Key   string
+	Value string
+}
+
+// ParseFormat validates a --format value
+func ParseFormat(s string) (Format, error) {
+	for _, f := range Formats {
+		if string(f) == strings.ToLower(s) {
+			return f, nil
+		}
+	}
+	return "", fmt.Errorf("unknown format %q (expected one of: dotenv, json, yaml)", s)
+}
+
+// DetectFormat picks the format from the file extension, anything that is
+// not JSON or YAML is read as dotenv (.env, .env.local, ...)
+func DetectFormat(name string) Format {
+	switch strings.ToLower(filepath.Ext(name)) {
+	case ".json":
+		return FormatJSON
+	case ".yaml", ".yml":
+		return FormatYAML
+	default:
+		return FormatDotenv
+	}
+}
@@ -0,0 +1,67 @@
+package injector
+
+import (
+	"os"
+	"runtime"
+	"sort"
+	"strings"
+)
+
+// buildEnv merges the secrets and then the paths of the file secrets over
+// the parent environment (or over the allowlisted part of it when
+// inheritance is disabled)
+func (i *Injector) buildEnv(fileVars map[string]string) []string {
+	env := make(map[string]string)
+	names := make(map[string]string) // normalized key -> original key
+
+	set := func(key, value string) {
+		norm := envKey(key)
+		if _, ok := names[norm]; !ok {
+			names[norm] = key
+		}
+		env[norm] = value
+	}
+
+	if i.Inherit {
+		for _, kv := range os.Environ() {
+			key, value, ok := strings.Cut(kv, "=")
+			if !ok || key == "" {
+				continue
+			}
+			set(key, value)
+		}
+	} else {
+		for _, key := range i.AllowEnv {
+			if value, ok := os.LookupEnv(key); ok {
+				set(key, value)
+			}
+		}
+	}
+
+	for key, value := range i.Secrets {
+		set(key, value)
+	}
+	for key, path := range fileVars {
+		set(key, path)
+	}
+
+	keys := make([]string, 0, len(env))
+	for norm := range env {
+		keys = append(keys, norm)
+	}
+	sort.Strings(keys)
+
+	result := make([]string, 0, len(keys))
+	for _, norm := range keys {
+		result = append(result, names[norm]+"="+env[norm])
+	}
+	return result
+}
+
+// envKey normalizes variable names, Windows treats them case-insensitively
+func envKey(key string) string {
+	if runtime.GOOS == "windows" {
+		return strings.ToUpper(key)
+	}
+	return key
+}
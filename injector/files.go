@@ -0,0 +1,129 @@
+package injector
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"strings"
+
+	"github.com/Knightshrestha/Secret-Injector/utils"
+)
+
+// DefaultFileVar names the variable holding the path of a file secret
+const DefaultFileVar = "{KEY}_FILE"
+
+// fileDirPrefix starts the directories file secrets are written to. The PID
+// of the owning inject follows, so a later run can tell leftovers apart.
+const fileDirPrefix = "secret_injector-files-"
+
+// secretFiles is a private directory holding the file secrets of one run
+type secretFiles struct {
+	dir  string
+	vars map[string]string // variable -> path of the file
+}
+
+// writeSecretFiles writes each file secret to a 0600 file in a new 0700
+// directory and returns the variables pointing at them
+func writeSecretFiles(files map[string]string, fileVar string) (*secretFiles, error) {
+	if fileVar == "" {
+		fileVar = DefaultFileVar
+	}
+	if !strings.Contains(fileVar, "{KEY}") {
+		return nil, fmt.Errorf("file variable %q must contain {KEY}", fileVar)
+	}
+
+	dir, err := os.MkdirTemp(fileBaseDir(), fileDirPrefix+strconv.Itoa(os.Getpid())+"-*")
+	if err != nil {
+		return nil, fmt.Errorf("failed to create directory for file secrets: %w", err)
+	}
+	// MkdirTemp already uses 0700, this guards against an odd umask
+	if err := os.Chmod(dir, 0o700); err != nil {
+		os.RemoveAll(dir)
+		return nil, fmt.Errorf("failed to protect %s: %w", dir, err)
+	}
+
+	result := &secretFiles{dir: dir, vars: make(map[string]string, len(files))}
+	for key, value := range files {
+		// Keys are checked when stored, older rows and renames were not
+		path := filepath.Join(dir, key)
+		if err := utils.CheckFileKey(key); err != nil || filepath.Dir(path) != dir {
+			result.shred()
+			return nil, fmt.Errorf("invalid key %q for a file secret", key)
+		}
+		if err := os.WriteFile(path, []byte(value), 0o600); err != nil {
+			result.shred()
+			return nil, fmt.Errorf("failed to write file secret %s: %w", key, err)
+		}
+		result.vars[strings.ReplaceAll(fileVar, "{KEY}", key)] = path
+	}
+	return result, nil
+}
+
+// shred overwrites every file with zeros before removing the directory
+func (f *secretFiles) shred() {
+	shredDir(f.dir)
+}
+
+// cleanStaleFiles shreds the file secrets left behind by inject runs that
+// did not get to clean up, e.g. because they were killed
+func cleanStaleFiles() {
+	base := fileBaseDir()
+	entries, err := os.ReadDir(base)
+	if err != nil {
+		return
+	}
+
+	for _, entry := range entries {
+		rest, ok := strings.CutPrefix(entry.Name(), fileDirPrefix)
+		if !ok || !entry.IsDir() {
+			continue
+		}
+		pidText, _, _ := strings.Cut(rest, "-")
+		pid, err := strconv.Atoi(pidText)
+		if err != nil || pid == os.Getpid() || processAlive(pid) {
+			continue
+		}
+		shredDir(filepath.Join(base, entry.Name()))
+	}
+}
+
+func shredDir(dir string) {
+	entries, _ := os.ReadDir(dir)
+	for _, entry := range entries {
+		shredFile(filepath.Join(dir, entry.Name()))
+	}
+	os.RemoveAll(dir)
+}
+
+func shredFile(path string) {
+	file, err := os.OpenFile(path, os.O_WRONLY, 0)
+	if err == nil {
+		if info, err := file.Stat(); err == nil && info.Mode().IsRegular() {
+			file.Write(make([]byte, info.Size()))
+			file.Sync()
+		}
+		file.Close()
+	}
+	os.Remove(path)
+}
+
+// fileBaseDir prefers memory-backed directories so file secrets never reach
+// the disk: the per-user runtime directory, then /dev/shm on Linux
+func fileBaseDir() string {
+	if runtime.GOOS == "linux" {
+		if dir := os.Getenv("XDG_RUNTIME_DIR"); dir != "" && isDir(dir) {
+			return dir
+		}
+		if isDir("/dev/shm") {
+			return "/dev/shm"
+		}
+	}
+	return os.TempDir()
+}
+
+func isDir(dir string) bool {
+	info, err := os.Stat(dir)
+	return err == nil && info.IsDir()
+}
@@ -0,0 +1,32 @@
+package injector
+
+import (
+	"os"
+	"os/signal"
+	"syscall"
+)
+
+// forwardSignals relays SIGINT, SIGTERM and SIGHUP to the child process
+// until the returned stop function is called
+func forwardSignals(proc *os.Process) func() {
+	sigChan := make(chan os.Signal, 1)
+	signal.Notify(sigChan, os.Interrupt, syscall.SIGTERM, syscall.SIGHUP)
+
+	done := make(chan struct{})
+	go func() {
+		for {
+			select {
+			case sig := <-sigChan:
+				// The child may already be gone, nothing to do then
+				_ = proc.Signal(sig)
+			case <-done:
+				return
+			}
+		}
+	}()
+
+	return func() {
+		signal.Stop(sigChan)
+		close(done)
+	}
+}
@@ -0,0 +1,65 @@
+package injector
+
+import (
+	"errors"
+	"os"
+	"os/exec"
+	"time"
+
+	"github.com/Knightshrestha/Secret-Injector/redact"
+	"golang.org/x/term"
+)
+
+// errNoTerminal means the command cannot get a terminal of its own and its
+// output is masked through pipes instead
+var errNoTerminal = errors.New("no pseudo-terminal available")
+
+// promptDelay is how long output that may be the start of a secret is held
+// back on a terminal before it is shown anyway
+const promptDelay = 50 * time.Millisecond
+
+// redactor looks for the values of the secrets and file secrets
+func (i *Injector) redactor() *redact.Redactor {
+	values := make(map[string]string, len(i.Secrets)+len(i.Files))
+	for key, value := range i.Secrets {
+		values[key] = value
+	}
+	for key, value := range i.Files {
+		values[key] = value
+	}
+	return redact.New(values, redact.DefaultMinLength)
+}
+
+// startMasked starts the command with its stdout and stderr passing through
+// the redactor. When inject runs in a terminal the command gets a
+// pseudo-terminal of its own, so it still behaves interactively; otherwise
+// both streams are redacted through pipes. The returned function must be
+// called once the command exited, it writes out the rest of the output and
+// restores the terminal.
+func startMasked(cmd *exec.Cmd, r *redact.Redactor) (func(), error) {
+	if isInteractive() {
+		finish, err := startOnTerminal(cmd, r)
+		if !errors.Is(err, errNoTerminal) {
+			return finish, err
+		}
+	}
+
+	stdout := r.Writer(os.Stdout)
+	stderr := r.Writer(os.Stderr)
+	cmd.Stdout = stdout
+	cmd.Stderr = stderr
+	if err := cmd.Start(); err != nil {
+		return nil, err
+	}
+
+	return func() {
+		stdout.Close()
+		stderr.Close()
+	}, nil
+}
+
+func isInteractive() bool {
+	return term.IsTerminal(int(os.Stdin.Fd())) &&
+		term.IsTerminal(int(os.Stdout.Fd())) &&
+		term.IsTerminal(int(os.Stderr.Fd()))
+}
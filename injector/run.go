@@ -0,0 +1,76 @@
+package injector
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"syscall"
+)
+
+// Run starts the command with the secrets in its environment, waits for it
+// to finish and returns its exit code. File secrets only exist while the
+// command runs, they are shredded once it exits, also when it was stopped
+// by a forwarded signal. With Mask the command's output is redacted, see
+// startMasked.
+func (i *Injector) Run() (int, error) {
+	if i.Command == "" {
+		return 1, fmt.Errorf("no command given")
+	}
+
+	path, err := exec.LookPath(i.Command)
+	if err != nil {
+		return 127, fmt.Errorf("command not found: %w", err)
+	}
+
+	// Files of earlier runs that were killed before cleaning up
+	cleanStaleFiles()
+
+	var fileVars map[string]string
+	if len(i.Files) > 0 {
+		files, err := writeSecretFiles(i.Files, i.FileVar)
+		if err != nil {
+			return 1, err
+		}
+		defer files.shred()
+		fileVars = files.vars
+	}
+
+	cmd := exec.Command(path, i.Args...)
+	cmd.Env = i.buildEnv(fileVars)
+	cmd.Stdin = os.Stdin
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+
+	finish := func() {}
+	if i.Mask {
+		finish, err = startMasked(cmd, i.redactor())
+	} else {
+		err = cmd.Start()
+	}
+	if err != nil {
+		return 126, fmt.Errorf("failed to start command: %w", err)
+	}
+
+	stop := forwardSignals(cmd.Process)
+	defer stop()
+
+	err = cmd.Wait()
+	finish()
+	if err != nil {
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) {
+			if code := exitErr.ExitCode(); code >= 0 {
+				return code, nil
+			}
+			// Killed by a signal, report it the way shells do
+			if status, ok := exitErr.Sys().(syscall.WaitStatus); ok && status.Signaled() {
+				return 128 + int(status.Signal()), nil
+			}
+			return 1, nil
+		}
+		return 1, fmt.Errorf("failed to wait for command: %w", err)
+	}
+
+	return 0, nil
+}
@@ -0,0 +1,141 @@
+//go:build linux
+
+package injector
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"os/signal"
+	"strconv"
+	"syscall"
+	"time"
+
+	"github.com/Knightshrestha/Secret-Injector/redact"
+	"golang.org/x/sys/unix"
+	"golang.org/x/term"
+)
+
+// startOnTerminal starts the command on a new pseudo-terminal whose output
+// is redacted on its way to ours. Our terminal is put in raw mode so keys,
+// Ctrl-C included, reach the command's terminal untouched; its size
+// follows ours.
+func startOnTerminal(cmd *exec.Cmd, r *redact.Redactor) (func(), error) {
+	master, slave, err := openPTY()
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", errNoTerminal, err)
+	}
+
+	stdin := int(os.Stdin.Fd())
+
+	// The command's terminal starts out with the settings of ours
+	if termios, err := unix.IoctlGetTermios(stdin, unix.TCGETS); err == nil {
+		_ = unix.IoctlSetTermios(int(slave.Fd()), unix.TCSETS, termios)
+	}
+	resizePTY(master)
+
+	cmd.Stdin = slave
+	cmd.Stdout = slave
+	cmd.Stderr = slave
+	cmd.SysProcAttr = &syscall.SysProcAttr{Setsid: true, Setctty: true, Ctty: 0}
+	if err := cmd.Start(); err != nil {
+		master.Close()
+		slave.Close()
+		return nil, err
+	}
+	// With only the command holding it, reads see the terminal close
+	slave.Close()
+
+	state, _ := term.MakeRaw(stdin)
+
+	winch := make(chan os.Signal, 1)
+	signal.Notify(winch, syscall.SIGWINCH)
+	go func() {
+		for range winch {
+			resizePTY(master)
+		}
+	}()
+
+	// Left blocked on our stdin when the command exits, inject exits too
+	go io.Copy(master, os.Stdin)
+
+	out := r.Writer(os.Stdout).FlushAfter(promptDelay)
+	done := make(chan struct{})
+	go func() {
+		// Ends with EIO once the terminal is closed
+		io.Copy(out, master)
+		close(done)
+	}()
+
+	return func() {
+		// Processes the command left behind may keep its terminal open
+		select {
+		case <-done:
+		case <-time.After(time.Second):
+			master.Close()
+			<-done
+		}
+		out.Close()
+		master.Close()
+
+		signal.Stop(winch)
+		close(winch)
+		if state != nil {
+			term.Restore(stdin, state)
+		}
+	}, nil
+}
+
+// openPTY opens a new pseudo-terminal pair. The master stays non-blocking,
+// so closing it ends a pending read.
+func openPTY() (*os.File, *os.File, error) {
+	master, err := os.OpenFile("/dev/ptmx", os.O_RDWR|syscall.O_NOCTTY, 0)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var number uint32
+	if err := controlFile(master, func(fd int) error {
+		if err := unix.IoctlSetPointerInt(fd, unix.TIOCSPTLCK, 0); err != nil {
+			return err
+		}
+		number, err = unix.IoctlGetUint32(fd, unix.TIOCGPTN)
+		return err
+	}); err != nil {
+		master.Close()
+		return nil, nil, err
+	}
+
+	slave, err := os.OpenFile("/dev/pts/"+strconv.FormatUint(uint64(number), 10), os.O_RDWR|syscall.O_NOCTTY, 0)
+	if err != nil {
+		master.Close()
+		return nil, nil, err
+	}
+	return master, slave, nil
+}
+
+// resizePTY gives the pseudo-terminal the size of our terminal
+func resizePTY(master *os.File) {
+	size, err := unix.IoctlGetWinsize(int(os.Stdout.Fd()), unix.TIOCGWINSZ)
+	if err != nil {
+		return
+	}
+	_ = controlFile(master, func(fd int) error {
+		return unix.IoctlSetWinsize(fd, unix.TIOCSWINSZ, size)
+	})
+}
+
+// controlFile runs f on the descriptor of file without switching it to
+// blocking mode as Fd does
+func controlFile(file *os.File, f func(fd int) error) error {
+	conn, err := file.SyscallConn()
+	if err != nil {
+		return err
+	}
+	var ferr error
+	if err := conn.Control(func(fd uintptr) { ferr = f(int(fd)) }); err != nil {
+		return err
+	}
+	return ferr
+}
@@ -0,0 +1,15 @@
+//go:build !linux
+
+package injector
+
+import (
+	"os/exec"
+
+	"github.com/Knightshrestha/Secret-Injector/redact"
+)
+
+// startOnTerminal is only implemented on Linux, elsewhere masked output
+// goes through pipes
+func startOnTerminal(cmd *exec.Cmd, r *redact.Redactor) (func(), error) {
+	return nil, errNoTerminal
+}
@@ -0,0 +1,12 @@
+package injector
+
+type Injector struct {
+	Command  string            // Executable to run
+	Args     []string          // Arguments passed to the executable
+	Secrets  map[string]string // Secrets to expose as environment variables
+	Inherit  bool              // Start from the parent environment
+	AllowEnv []string          // Parent variables kept when Inherit is false
+	Files    map[string]string // Secrets written to private temporary files
+	FileVar  string            // Variable holding a file's path, {KEY} is the key (default DefaultFileVar)
+	Mask     bool              // Replace secret values in the command's output with ***KEY***
+}
@@ -0,0 +1,190 @@
+package redact
+
+import "sort"
+
+// Matcher finds any number of patterns in a single pass over the input
+// (Aho-Corasick), so thousands of secret values cost about as much to look
+// for as one
+type Matcher struct {
+	nodes    []node
+	root     [256]int32 // Transitions of the root, the busiest node
+	patterns [][]byte
+}
+
+type node struct {
+	edges []edge // Children, sorted by byte
+	fail  int32
+	dict  int32   // Nearest node on the fail chain ending a pattern, -1 if none
+	out   []int32 // Patterns ending at this node
+	depth int32
+}
+
+// edge is a transition of the trie. Most nodes have a single child, a
+// sorted slice keeps thousands of patterns far smaller than a map per node.
+type edge struct {
+	b  byte
+	to int32
+}
+
+// Match is one occurrence of a pattern, Pattern indexes the patterns the
+// Matcher was built from
+type Match struct {
+	Pattern int
+	Start   int
+	End     int
+}
+
+// NewMatcher builds a matcher for the patterns, empty ones are ignored
+func NewMatcher(patterns [][]byte) *Matcher {
+	m := &Matcher{
+		nodes:    []node{{dict: -1}},
+		patterns: patterns,
+	}
+
+	for id, pattern := range patterns {
+		if len(pattern) == 0 {
+			continue
+		}
+		state := int32(0)
+		for _, b := range pattern {
+			next, ok := m.child(state, b)
+			if !ok {
+				next = int32(len(m.nodes))
+				m.nodes = append(m.nodes, node{dict: -1, depth: m.nodes[state].depth + 1})
+				m.addChild(state, b, next)
+			}
+			state = next
+		}
+		m.nodes[state].out = append(m.nodes[state].out, int32(id))
+	}
+
+	// The root answers every byte directly, unknown ones lead back to it
+	for _, e := range m.nodes[0].edges {
+		m.root[e.b] = e.to
+	}
+
+	// Fail links point at the longest proper suffix that is also a prefix,
+	// computed breadth first so shorter prefixes are done before longer ones
+	queue := make([]int32, 0, len(m.nodes))
+	for _, e := range m.nodes[0].edges {
+		queue = append(queue, e.to)
+	}
+	for len(queue) > 0 {
+		state := queue[0]
+		queue = queue[1:]
+
+		for _, e := range m.nodes[state].edges {
+			child := e.to
+			fail := m.nodes[state].fail
+			for {
+				if next, ok := m.child(fail, e.b); ok && next != child {
+					m.nodes[child].fail = next
+					break
+				}
+				if fail == 0 {
+					m.nodes[child].fail = 0
+					break
+				}
+				fail = m.nodes[fail].fail
+			}
+
+			failNode := m.nodes[m.nodes[child].fail]
+			if len(failNode.out) > 0 {
+				m.nodes[child].dict = m.nodes[child].fail
+			} else {
+				m.nodes[child].dict = failNode.dict
+			}
+			queue = append(queue, child)
+		}
+	}
+
+	return m
+}
+
+func (m *Matcher) child(state int32, b byte) (int32, bool) {
+	edges := m.nodes[state].edges
+	i := sort.Search(len(edges), func(i int) bool { return edges[i].b >= b })
+	if i < len(edges) && edges[i].b == b {
+		return edges[i].to, true
+	}
+	return 0, false
+}
+
+func (m *Matcher) addChild(state int32, b byte, to int32) {
+	edges := m.nodes[state].edges
+	i := sort.Search(len(edges), func(i int) bool { return edges[i].b >= b })
+	edges = append(edges, edge{})
+	copy(edges[i+1:], edges[i:])
+	edges[i] = edge{b: b, to: to}
+	m.nodes[state].edges = edges
+}
+
+// Len returns the number of patterns
+func (m *Matcher) Len() int {
+	return len(m.patterns)
+}
+
+func (m *Matcher) step(state int32, b byte) int32 {
+	for state != 0 {
+		if next, ok := m.child(state, b); ok {
+			return next
+		}
+		state = m.nodes[state].fail
+	}
+	return m.root[b]
+}
+
+// scan returns every match in data, ordered by where it ends, and how many
+// bytes at the end of data could still start a match
+func (m *Matcher) scan(data []byte) ([]Match, int) {
+	var matches []Match
+	state := int32(0)
+	for i, b := range data {
+		state = m.step(state, b)
+		for at := state; at >= 0; at = m.nodes[at].dict {
+			for _, id := range m.nodes[at].out {
+				matches = append(matches, Match{
+					Pattern: int(id),
+					Start:   i + 1 - len(m.patterns[id]),
+					End:     i + 1,
+				})
+			}
+			if at == 0 {
+				break
+			}
+		}
+	}
+	return matches, int(m.nodes[state].depth)
+}
+
+// FindAll returns every occurrence of every pattern in data, overlapping
+// ones included, ordered by where they end
+func (m *Matcher) FindAll(data []byte) []Match {
+	matches, _ := m.scan(data)
+	return matches
+}
+
+// leftmostLongest keeps the matches a replacement would use: scanning from
+// the left, the longest match starting at each point that does not overlap
+// one already taken
+func leftmostLongest(matches []Match) []Match {
+	sort.Slice(matches, func(i, j int) bool {
+		if matches[i].Start != matches[j].Start {
+			return matches[i].Start < matches[j].Start
+		}
+		if matches[i].End != matches[j].End {
+			return matches[i].End > matches[j].End
+		}
+		return matches[i].Pattern < matches[j].Pattern
+	})
+
+	var kept []Match
+	end := 0
+	for _, match := range matches {
+		if match.Start >= end {
+			kept = append(kept, match)
+			end = match.End
+		}
+	}
+	return kept
+}
@@ -0,0 +1,143 @@
+// Package redact finds secret values in text and replaces them with the
+// name of the secret, also when they appear encoded.
+package redact
+
+import (
+	"encoding/base64"
+	"net/url"
+	"sort"
+	"strings"
+)
+
+// DefaultMinLength is the shortest value looked for. Shorter values such as
+// "1" or "true" appear everywhere and would mostly hide unrelated text.
+const DefaultMinLength = 4
+
+// Forms a secret value is looked for in
+const (
+	FormRaw       = "raw"
+	FormBase64    = "base64"
+	FormBase64URL = "base64url"
+	FormURL       = "url"
+)
+
+// Redactor looks for the values of a set of secrets
+type Redactor struct {
+	matcher *Matcher
+	keys    []string // Secret key per pattern
+	forms   []string // Form per pattern
+}
+
+// Finding is one occurrence of a secret value
+type Finding struct {
+	Key   string
+	Form  string
+	Start int
+	End   int
+}
+
+// New builds a redactor for the secrets (key -> value). Each value is looked
+// for as is, base64 encoded and URL encoded; values and encodings shorter
+// than minLength are skipped.
+func New(secrets map[string]string, minLength int) *Redactor {
+	keys := make([]string, 0, len(secrets))
+	for key := range secrets {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	r := &Redactor{}
+	var patterns [][]byte
+	for _, key := range keys {
+		value := secrets[key]
+		if len(value) < minLength {
+			continue
+		}
+
+		seen := make(map[string]bool)
+		add := func(form string, pattern string) {
+			if len(pattern) < minLength || seen[pattern] {
+				return
+			}
+			seen[pattern] = true
+			patterns = append(patterns, []byte(pattern))
+			r.keys = append(r.keys, key)
+			r.forms = append(r.forms, form)
+		}
+
+		add(FormRaw, value)
+		// A terminal turns \n into \r\n on the way out
+		if strings.Contains(value, "\n") {
+			add(FormRaw, strings.ReplaceAll(strings.ReplaceAll(value, "\r\n", "\n"), "\n", "\r\n"))
+		}
+		// Unpadded, so the padded and unpadded forms both match
+		add(FormBase64, base64.RawStdEncoding.EncodeToString([]byte(value)))
+		add(FormBase64URL, base64.RawURLEncoding.EncodeToString([]byte(value)))
+		add(FormURL, url.QueryEscape(value))
+		add(FormURL, url.PathEscape(value))
+	}
+
+	r.matcher = NewMatcher(patterns)
+	return r
+}
+
+// Empty reports whether there is nothing to look for
+func (r *Redactor) Empty() bool {
+	return r.matcher.Len() == 0
+}
+
+// Find returns the occurrences in data that Redact replaces, ordered by
+// position. Where values overlap the one starting first wins, then the
+// longest.
+func (r *Redactor) Find(data []byte) []Finding {
+	matches := leftmostLongest(r.matcher.FindAll(data))
+	findings := make([]Finding, 0, len(matches))
+	for _, match := range matches {
+		findings = append(findings, r.finding(match))
+	}
+	return findings
+}
+
+// Redact returns data with every secret value replaced by ***KEY***
+func (r *Redactor) Redact(data []byte) []byte {
+	out, _ := r.replace(data, r.matcher.FindAll(data), len(data), nil)
+	return out
+}
+
+// Label is what a value of the secret key is replaced with
+func Label(key string) string {
+	return "***" + key + "***"
+}
+
+func (r *Redactor) finding(match Match) Finding {
+	return Finding{
+		Key:   r.keys[match.Pattern],
+		Form:  r.forms[match.Pattern],
+		Start: match.Start,
+		End:   match.End,
+	}
+}
+
+// replace replaces the matches in data that start before limit. It returns
+// the output for data[:n] and n, which is limit or the end of the last
+// match replaced when that lies beyond it. Counts, when not nil, receives
+// the number of replacements per key.
+func (r *Redactor) replace(data []byte, matches []Match, limit int, counts map[string]int) ([]byte, int) {
+	out := make([]byte, 0, len(data))
+	pos := 0
+	for _, match := range leftmostLongest(matches) {
+		if match.Start >= limit {
+			break
+		}
+		key := r.keys[match.Pattern]
+		out = append(out, data[pos:match.Start]...)
+		out = append(out, Label(key)...)
+		pos = match.End
+		if counts != nil {
+			counts[key]++
+		}
+	}
+	end := max(pos, limit)
+	out = append(out, data[pos:end]...)
+	return out, end
+}
@@ -0,0 +1,113 @@
+package redact
+
+import (
+	"io"
+	"sync"
+	"time"
+)
+
+// Writer redacts a stream on its way to another writer. A value split
+// across writes is still caught: the end of each write that could be the
+// start of a value is held back until the next write shows whether it is.
+// Close flushes what is held back.
+type Writer struct {
+	mu     sync.Mutex
+	r      *Redactor
+	w      io.Writer
+	buf    []byte
+	counts map[string]int
+	err    error
+
+	idle  time.Duration
+	timer *time.Timer
+}
+
+// Writer returns a writer redacting everything written to it into w
+func (r *Redactor) Writer(w io.Writer) *Writer {
+	return &Writer{r: r, w: w, counts: make(map[string]int)}
+}
+
+// FlushAfter makes the writer flush what it holds back once no write came
+// for d. Interactive programs print prompts and wait, and a prompt ending
+// in what looks like the start of a value would never show otherwise. A
+// value written in two parts with a longer pause in between gets through.
+func (w *Writer) FlushAfter(d time.Duration) *Writer {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.idle = d
+	return w
+}
+
+func (w *Writer) Write(p []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.err != nil {
+		return 0, w.err
+	}
+	if w.timer != nil {
+		w.timer.Stop()
+	}
+
+	w.buf = append(w.buf, p...)
+	matches, pending := w.r.matcher.scan(w.buf)
+	if err := w.emit(matches, len(w.buf)-pending); err != nil {
+		return 0, err
+	}
+
+	if len(w.buf) > 0 && w.idle > 0 {
+		if w.timer == nil {
+			w.timer = time.AfterFunc(w.idle, func() { w.Flush() })
+		} else {
+			w.timer.Reset(w.idle)
+		}
+	}
+	return len(p), nil
+}
+
+// Flush redacts and writes everything held back
+func (w *Writer) Flush() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.err != nil {
+		return w.err
+	}
+	return w.emit(w.r.matcher.FindAll(w.buf), len(w.buf))
+}
+
+// Close flushes the writer, the underlying writer is left open
+func (w *Writer) Close() error {
+	w.mu.Lock()
+	if w.timer != nil {
+		w.timer.Stop()
+	}
+	w.mu.Unlock()
+	return w.Flush()
+}
+
+// Counts returns how many values of each secret were replaced so far
+func (w *Writer) Counts() map[string]int {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	counts := make(map[string]int, len(w.counts))
+	for key, count := range w.counts {
+		counts[key] = count
+	}
+	return counts
+}
+
+// emit writes the redacted buffer up to limit and keeps the rest
+func (w *Writer) emit(matches []Match, limit int) error {
+	out, n := w.r.replace(w.buf, matches, limit, w.counts)
+	w.buf = w.buf[:copy(w.buf, w.buf[n:])]
+
+	if len(out) > 0 {
+		if _, err := w.w.Write(out); err != nil {
+			w.err = err
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,40 @@
+package scanner
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os/exec"
+	"strings"
+)
+
+// ErrNotRepository is returned for git features used outside a repository
+var ErrNotRepository = errors.New("not a git repository")
+
+// gitCommand prepares git to run in dir. Paths are printed as they are,
+// without quoting non-ASCII names.
+func gitCommand(dir string, args ...string) *exec.Cmd {
+	return exec.Command("git", append([]string{"-C", dir, "-c", "core.quotePath=false"}, args...)...)
+}
+
+// git runs git in dir and returns its output
+func git(dir string, args ...string) ([]byte, error) {
+	var stderr bytes.Buffer
+	cmd := gitCommand(dir, args...)
+	cmd.Stderr = &stderr
+
+	out, err := cmd.Output()
+	if err != nil {
+		if message := strings.TrimSpace(stderr.String()); message != "" {
+			return nil, fmt.Errorf("git %s: %s", args[0], message)
+		}
+		return nil, fmt.Errorf("git %s: %w", args[0], err)
+	}
+	return out, nil
+}
+
+// IsRepository reports whether dir is inside a git working tree
+func IsRepository(dir string) bool {
+	out, err := git(dir, "rev-parse", "--is-inside-work-tree")
+	return err == nil && strings.TrimSpace(string(out)) == "true"
+}
@@ -0,0 +1,149 @@
+package scanner
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/Knightshrestha/Secret-Injector/redact"
+)
+
+// diffArgs make git print only the added and removed lines of a patch, with
+// the usual a/ b/ prefixes whatever the user's configuration says, paths
+// relative to the directory scanned and nothing outside it
+var diffArgs = []string{
+	"-p", "-U0", "--no-color", "--no-renames", "--no-ext-diff", "--no-textconv",
+	"--src-prefix=a/", "--dst-prefix=b/", "--relative",
+}
+
+// commitMarker starts the line naming the commit of the patch that follows
+const commitMarker = "\x00commit "
+
+// ScanHistory looks for the values in the lines added by every commit
+// reachable from a ref of the repository at root. A value is reported for
+// each commit adding it, so values that were later removed are found too.
+func ScanHistory(r *redact.Redactor, root string) ([]Finding, error) {
+	if !IsRepository(root) {
+		return nil, ErrNotRepository
+	}
+	// %x00 prints the NUL of commitMarker
+	return scanPatch(r, root, append([]string{"log", "--all", "--format=%x00commit %H"}, diffArgs...))
+}
+
+// ScanStaged looks for the values in the lines the index adds, what the
+// next commit would add to the repository at root
+func ScanStaged(r *redact.Redactor, root string) ([]Finding, error) {
+	if !IsRepository(root) {
+		return nil, ErrNotRepository
+	}
+	return scanPatch(r, root, append([]string{"diff", "--cached"}, diffArgs...))
+}
+
+func scanPatch(r *redact.Redactor, root string, args []string) ([]Finding, error) {
+	var stderr bytes.Buffer
+	cmd := gitCommand(root, args...)
+	cmd.Stderr = &stderr
+
+	out, err := cmd.StdoutPipe()
+	if err != nil {
+		return nil, err
+	}
+	if err := cmd.Start(); err != nil {
+		return nil, fmt.Errorf("git %s: %w", args[0], err)
+	}
+
+	findings, parseErr := parsePatch(r, out)
+	if parseErr != nil {
+		// Stop git rather than wait for it to fill the pipe
+		cmd.Process.Kill()
+	}
+	if err := cmd.Wait(); err != nil && parseErr == nil {
+		if message := strings.TrimSpace(stderr.String()); message != "" {
+			return nil, fmt.Errorf("git %s: %s", args[0], message)
+		}
+		return nil, fmt.Errorf("git %s: %w", args[0], err)
+	}
+	return findings, parseErr
+}
+
+// parsePatch reads the output of git log -p or git diff with -U0. The
+// added lines of a hunk are consecutive, so each hunk is scanned as one
+// block and values spanning lines are found.
+func parsePatch(r *redact.Redactor, patch io.Reader) ([]Finding, error) {
+	var findings []Finding
+	var commit, path string
+	var block []byte
+	firstLine := 0
+	inHunk := false
+
+	flush := func() {
+		if len(block) > 0 && path != "" {
+			findings = append(findings, findInText(r, block, path, firstLine, commit)...)
+		}
+		block = block[:0]
+	}
+
+	reader := bufio.NewReaderSize(patch, 64*1024)
+	for {
+		line, err := reader.ReadString('\n')
+		if line != "" {
+			switch {
+			case strings.HasPrefix(line, commitMarker):
+				flush()
+				commit = strings.TrimSpace(strings.TrimPrefix(line, commitMarker))
+				path, inHunk = "", false
+			case strings.HasPrefix(line, "diff "):
+				flush()
+				path, inHunk = "", false
+			case strings.HasPrefix(line, "@@ "):
+				flush()
+				firstLine = hunkStart(line)
+				inHunk = true
+			case !inHunk && strings.HasPrefix(line, "+++ "):
+				// Names with spaces may end in a tab
+				path = patchPath(strings.TrimRight(strings.TrimPrefix(line, "+++ "), "\t\n"))
+			case inHunk && strings.HasPrefix(line, "+"):
+				block = append(block, line[1:]...)
+			}
+		}
+		if err == io.EOF {
+			flush()
+			return findings, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+}
+
+// hunkStart returns the first line of the new side of a hunk header such as
+// "@@ -10,2 +12,3 @@"
+func hunkStart(header string) int {
+	fields := strings.Fields(header)
+	if len(fields) < 3 {
+		return 1
+	}
+	start, _, _ := strings.Cut(strings.TrimPrefix(fields[2], "+"), ",")
+	n, err := strconv.Atoi(start)
+	if err != nil {
+		return 1
+	}
+	return n
+}
+
+// patchPath returns the path of a +++ line, empty for a deleted file. Git
+// quotes paths with special characters like a C string.
+func patchPath(name string) string {
+	if strings.HasPrefix(name, `"`) {
+		if unquoted, err := strconv.Unquote(name); err == nil {
+			name = unquoted
+		}
+	}
+	if name == "/dev/null" {
+		return ""
+	}
+	return strings.TrimPrefix(name, "b/")
+}
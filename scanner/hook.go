@@ -0,0 +1,64 @@
+package scanner
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// hookMarker tells a hook written by InstallHook from one written by hand
+const hookMarker = "# Installed by secret_injector scan --install-hook"
+
+// ErrHookExists is a pre-commit hook InstallHook did not write
+var ErrHookExists = errors.New("a pre-commit hook already exists")
+
+// InstallHook writes a pre-commit hook running command (program and
+// arguments) to the repository at root and returns its path. A hook it did
+// not write itself is only replaced with force.
+func InstallHook(root string, command []string, force bool) (string, error) {
+	if !IsRepository(root) {
+		return "", ErrNotRepository
+	}
+
+	// Honours core.hooksPath and worktrees
+	out, err := git(root, "rev-parse", "--git-path", "hooks/pre-commit")
+	if err != nil {
+		return "", err
+	}
+	path := strings.TrimSpace(string(out))
+	if !filepath.IsAbs(path) {
+		path = filepath.Join(root, path)
+	}
+
+	if existing, err := os.ReadFile(path); err == nil && !force && !bytes.Contains(existing, []byte(hookMarker)) {
+		return path, fmt.Errorf("%w: %s", ErrHookExists, path)
+	}
+
+	quoted := make([]string, len(command))
+	for i, arg := range command {
+		quoted[i] = shellQuote(arg)
+	}
+	script := "#!/bin/sh\n" +
+		hookMarker + "\n" +
+		"# Stops commits adding a stored secret value, git commit --no-verify skips it\n" +
+		"exec " + strings.Join(quoted, " ") + "\n"
+
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return "", fmt.Errorf("failed to create hooks directory: %w", err)
+	}
+	if err := os.WriteFile(path, []byte(script), 0o755); err != nil {
+		return "", fmt.Errorf("failed to write hook: %w", err)
+	}
+	// WriteFile keeps the mode of an existing file
+	if err := os.Chmod(path, 0o755); err != nil {
+		return "", fmt.Errorf("failed to make hook executable: %w", err)
+	}
+	return path, nil
+}
+
+func shellQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
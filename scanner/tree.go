@@ -0,0 +1,100 @@
+package scanner
+
+import (
+	"bytes"
+	"io/fs"
+	"os"
+	"path/filepath"
+
+	"github.com/Knightshrestha/Secret-Injector/redact"
+)
+
+// ScanTree looks for the values in the files under root, or in root itself
+// when it is a file. In a git repository the files are those git tracks or
+// would track, so .gitignore is honoured; elsewhere every file is read.
+// Binary files and symlinks are skipped. Paths are relative to root.
+func ScanTree(r *redact.Redactor, root string) ([]Finding, error) {
+	info, err := os.Stat(root)
+	if err != nil {
+		return nil, err
+	}
+	if !info.IsDir() {
+		return scanFile(r, root, root)
+	}
+
+	paths, err := listFiles(root)
+	if err != nil {
+		return nil, err
+	}
+
+	var findings []Finding
+	for _, path := range paths {
+		found, err := scanFile(r, filepath.Join(root, filepath.FromSlash(path)), path)
+		if err != nil {
+			return nil, err
+		}
+		findings = append(findings, found...)
+	}
+	return findings, nil
+}
+
+// listFiles returns the files under root as slash separated relative paths
+func listFiles(root string) ([]string, error) {
+	if IsRepository(root) {
+		out, err := git(root, "ls-files", "-z", "--cached", "--others", "--exclude-standard")
+		if err != nil {
+			return nil, err
+		}
+
+		var paths []string
+		for _, path := range bytes.Split(out, []byte{0}) {
+			if len(path) > 0 {
+				paths = append(paths, string(path))
+			}
+		}
+		return paths, nil
+	}
+
+	var paths []string
+	err := filepath.WalkDir(root, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if entry.IsDir() {
+			if entry.Name() == ".git" {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		rel, err := filepath.Rel(root, path)
+		if err != nil {
+			return err
+		}
+		paths = append(paths, filepath.ToSlash(rel))
+		return nil
+	})
+	return paths, err
+}
+
+func scanFile(r *redact.Redactor, path string, name string) ([]Finding, error) {
+	// Tracked files may be deleted, submodules are directories
+	info, err := os.Lstat(path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if !info.Mode().IsRegular() {
+		return nil, nil
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	if isBinary(data) {
+		return nil, nil
+	}
+	return findInText(r, data, name, 1, ""), nil
+}
@@ -0,0 +1,54 @@
+// Package scanner looks for secret values in a working tree, in the git
+// history of a repository and in staged changes.
+package scanner
+
+import (
+	"bytes"
+
+	"github.com/Knightshrestha/Secret-Injector/redact"
+)
+
+// Finding is a secret value found in a file. The value itself is never
+// part of it.
+type Finding struct {
+	Path   string `json:"path"`
+	Line   int    `json:"line"`
+	Column int    `json:"column"`
+	Key    string `json:"key"`
+	Form   string `json:"form"`             // How the value was written, see redact.FormRaw etc.
+	Commit string `json:"commit,omitempty"` // Commit adding the value, empty for files on disk
+}
+
+// findInText reports the values in text, whose first line is line firstLine
+// of the file at path
+func findInText(r *redact.Redactor, text []byte, path string, firstLine int, commit string) []Finding {
+	var findings []Finding
+	line := firstLine
+	lineStart := 0
+	pos := 0
+	for _, found := range r.Find(text) {
+		for {
+			next := bytes.IndexByte(text[pos:found.Start], '\n')
+			if next < 0 {
+				break
+			}
+			pos += next + 1
+			line++
+			lineStart = pos
+		}
+		findings = append(findings, Finding{
+			Path:   path,
+			Line:   line,
+			Column: found.Start - lineStart + 1,
+			Key:    found.Key,
+			Form:   found.Form,
+			Commit: commit,
+		})
+	}
+	return findings
+}
+
+// isBinary guesses like git does: text has no NUL in its first 8000 bytes
+func isBinary(data []byte) bool {
+	return bytes.IndexByte(data[:min(len(data), 8000)], 0) >= 0
+}
@@ -1,19 +1,29 @@
 package server
 
 import (
+	"github.com/Knightshrestha/Secret-Injector/audit"
 	"github.com/Knightshrestha/Secret-Injector/database"
 	"github.com/Knightshrestha/Secret-Injector/server/server_sse"
 	"github.com/gofiber/fiber/v2"
 )
 
 func RegisterApiRoutes(app *fiber.App, customDb database.CustomDB) {
-	apiGroup := app.Group("/api")
+	auditLog := audit.NewLogger(customDb.WriteDB, customDb.WriteQueries)
+
+	apiGroup := app.Group("/api", RequireToken(customDb.ReadQueries, customDb.WriteQueries, false))
 	RegisterReadOnlyProjectRoute(apiGroup, customDb.ReadQueries)
 	RegisterWriteProjectRoute(apiGroup, customDb.WriteDB, customDb.WriteQueries)
 
-	RegisterReadOnlySecretRoute(apiGroup, customDb.ReadQueries)
-	RegisterWriteSecretRoute(apiGroup, customDb.WriteQueries)
+	RegisterReadOnlySecretRoute(apiGroup, customDb.ReadQueries, customDb.Cipher, auditLog)
+	RegisterWriteSecretRoute(apiGroup, customDb.WriteDB, customDb.WriteQueries, customDb.Cipher)
+	RegisterBatchSecretRoute(apiGroup, customDb.WriteDB, customDb.WriteQueries, customDb.Cipher)
+	RegisterImportRoute(apiGroup, customDb.WriteDB, customDb.WriteQueries, customDb.Cipher)
+
+	RegisterReferenceRoute(apiGroup, customDb.ReadQueries, customDb.Cipher, auditLog)
+	RegisterReadOnlyEnvironmentRoute(apiGroup, customDb.ReadQueries, customDb.Cipher, auditLog)
+
+	RegisterAuditRoute(apiGroup, customDb.ReadQueries)
 
-	sseGroup := app.Group("/events")
+	sseGroup := app.Group("/events", RequireToken(customDb.ReadQueries, customDb.WriteQueries, true))
 	server_sse.RegisterSSERoutes(sseGroup)
 }
@@ -0,0 +1,107 @@
+package server
+
+import (
+	"log"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Knightshrestha/Secret-Injector/audit"
+	"github.com/Knightshrestha/Secret-Injector/auth"
+	"github.com/Knightshrestha/Secret-Injector/database/generated"
+	"github.com/gofiber/fiber/v2"
+)
+
+const (
+	defaultAuditLimit = 100
+	maxAuditLimit     = 1000
+)
+
+func RegisterAuditRoute(router fiber.Router, readOnlyDatabase *generated.Queries) {
+	// Get audit entries, newest first. Filters: actor, source, action,
+	// project_id, key, since, until (RFC 3339), before_id and limit
+	router.Get("/audit", func(c *fiber.Ctx) error {
+		if !auth.GrantFromCtx(c).IsAdmin() {
+			return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
+				"error": "Reading the audit log requires an admin token",
+			})
+		}
+
+		params := generated.GetAuditEntriesParams{
+			Actor:     queryString(c, "actor"),
+			Source:    queryString(c, "source"),
+			Action:    queryString(c, "action"),
+			ProjectID: queryString(c, "project_id"),
+			SecretKey: queryString(c, "key"),
+			Limit:     defaultAuditLimit,
+		}
+
+		for name, target := range map[string]**time.Time{"since": &params.Since, "until": &params.Until} {
+			raw := c.Query(name)
+			if raw == "" {
+				continue
+			}
+			t, err := time.Parse(time.RFC3339, raw)
+			if err != nil {
+				return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+					"error": "Invalid " + name + ", expected an RFC 3339 timestamp",
+				})
+			}
+			t = t.UTC()
+			*target = &t
+		}
+
+		if raw := c.Query("before_id"); raw != "" {
+			beforeID, err := strconv.ParseInt(raw, 10, 64)
+			if err != nil {
+				return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+					"error": "Invalid before_id",
+				})
+			}
+			params.BeforeID = &beforeID
+		}
+
+		if raw := c.Query("limit"); raw != "" {
+			limit, err := strconv.ParseInt(raw, 10, 64)
+			if err != nil || limit < 1 || limit > maxAuditLimit {
+				return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+					"error": "limit must be between 1 and " + strconv.Itoa(maxAuditLimit),
+				})
+			}
+			params.Limit = limit
+		}
+
+		entries, err := readOnlyDatabase.GetAuditEntries(c.Context(), params)
+		if err != nil {
+			log.Printf("Error fetching audit entries: %v", err)
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+				"error": "Failed to fetch audit entries",
+			})
+		}
+
+		if entries == nil {
+			entries = []generated.AuditLog{}
+		}
+		return c.JSON(entries)
+	})
+}
+
+func queryString(c *fiber.Ctx, name string) *string {
+	value := strings.TrimSpace(c.Query(name))
+	if value == "" {
+		return nil
+	}
+	return &value
+}
+
+// recordSecretAccess audits secrets handed out by a request. Reads fail
+// when they cannot be recorded.
+func recordSecretAccess(c *fiber.Ctx, auditLog *audit.Logger, action audit.Action, secrets []generated.SecretList) error {
+	return auditLog.Record(c.Context(), audit.SecretEntries(auth.ActorFromCtx(c), audit.SourceAPI, action, secrets)...)
+}
+
+// apiOrigin is the origin writes of a request are audited as, they record
+// it in their own transaction
+func apiOrigin(c *fiber.Ctx) audit.Origin {
+	return audit.Origin{Actor: auth.ActorFromCtx(c), Source: audit.SourceAPI}
+}
@@ -0,0 +1,128 @@
+package server
+
+import (
+	"database/sql"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/Knightshrestha/Secret-Injector/auth"
+	"github.com/Knightshrestha/Secret-Injector/database/generated"
+	"github.com/gofiber/fiber/v2"
+)
+
+// RequireToken rejects requests without a valid, unexpired bearer token and
+// stores its grant for the handlers. The token is read from the
+// Authorization header or the UI cookie. allowQuery also accepts a `token`
+// query parameter, only for the event streams: EventSource cannot send
+// headers, and a token in a URL ends up in logs and browser history.
+func RequireToken(readOnlyDatabase *generated.Queries, readWriteDatabase *generated.Queries, allowQuery bool) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		raw := extractToken(c, allowQuery)
+		if raw == "" {
+			return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+				"error": "Authentication required",
+			})
+		}
+
+		token, err := readOnlyDatabase.GetApiTokenByHash(c.Context(), auth.HashToken(raw))
+		if err != nil {
+			if err == sql.ErrNoRows {
+				return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+					"error": "Invalid token",
+				})
+			}
+			log.Printf("Failed to look up token: %v", err)
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+				"error": "Failed to verify token",
+			})
+		}
+
+		grant, err := loadGrant(c, readOnlyDatabase, token)
+		if err != nil {
+			log.Printf("Failed to load token %s projects: %v", token.ID, err)
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+				"error": "Failed to verify token",
+			})
+		}
+
+		if grant.Expired(time.Now()) {
+			return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+				"error": "Token has expired",
+			})
+		}
+
+		if err := readWriteDatabase.TouchApiToken(c.Context(), token.ID); err != nil {
+			log.Printf("Failed to update token %s usage: %v", token.ID, err)
+		}
+
+		auth.SetGrant(c, grant)
+		return c.Next()
+	}
+}
+
+// UILogin turns a `/ui?token=...` link into an HttpOnly cookie and redirects
+// to the same page without the token in the URL
+func UILogin(readOnlyDatabase *generated.Queries) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		raw := c.Query("token")
+		if raw == "" {
+			return c.Next()
+		}
+
+		token, err := readOnlyDatabase.GetApiTokenByHash(c.Context(), auth.HashToken(raw))
+		if err != nil {
+			if err != sql.ErrNoRows {
+				log.Printf("Failed to look up token: %v", err)
+			}
+			return c.Status(fiber.StatusUnauthorized).SendString("Invalid token")
+		}
+
+		if auth.NewGrant(token, nil).Expired(time.Now()) {
+			return c.Status(fiber.StatusUnauthorized).SendString("Token has expired")
+		}
+
+		c.Cookie(&fiber.Cookie{
+			Name:     auth.CookieName,
+			Value:    raw,
+			Path:     "/",
+			HTTPOnly: true,
+			Secure:   c.Protocol() == "https",
+			SameSite: fiber.CookieSameSiteStrictMode,
+			Expires:  time.Now().Add(30 * 24 * time.Hour),
+		})
+		return c.Redirect(c.Path(), fiber.StatusSeeOther)
+	}
+}
+
+// loadGrant fetches the project list of restricted tokens
+func loadGrant(c *fiber.Ctx, readOnlyDatabase *generated.Queries, token generated.ApiToken) (auth.Grant, error) {
+	if !token.Restricted {
+		return auth.NewGrant(token, nil), nil
+	}
+
+	projectIDs, err := readOnlyDatabase.GetApiTokenProjectIDs(c.Context(), token.ID)
+	if err != nil {
+		return auth.Grant{}, err
+	}
+	return auth.NewGrant(token, projectIDs), nil
+}
+
+func extractToken(c *fiber.Ctx, allowQuery bool) string {
+	if header := c.Get(fiber.HeaderAuthorization); header != "" {
+		scheme, value, ok := strings.Cut(header, " ")
+		if ok && strings.EqualFold(scheme, "Bearer") {
+			return strings.TrimSpace(value)
+		}
+		return ""
+	}
+
+	if cookie := c.Cookies(auth.CookieName); cookie != "" {
+		return cookie
+	}
+
+	if allowQuery {
+		return c.Query("token")
+	}
+	return ""
+}
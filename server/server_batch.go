@@ -0,0 +1,125 @@
+package server
+
+import (
+	"database/sql"
+	"errors"
+	"log"
+
+	"github.com/Knightshrestha/Secret-Injector/auth"
+	"github.com/Knightshrestha/Secret-Injector/core/db_ro"
+	"github.com/Knightshrestha/Secret-Injector/core/db_rw"
+	"github.com/Knightshrestha/Secret-Injector/database/generated"
+	"github.com/Knightshrestha/Secret-Injector/server/server_sse"
+	"github.com/Knightshrestha/Secret-Injector/vault"
+	"github.com/gofiber/fiber/v2"
+)
+
+var batchEvents = map[db_rw.BatchOp]server_sse.EventType{
+	db_rw.BatchCreate: server_sse.EventCreate,
+	db_rw.BatchUpdate: server_sse.EventUpdate,
+	db_rw.BatchDelete: server_sse.EventDelete,
+}
+
+func RegisterBatchSecretRoute(
+	router fiber.Router,
+	readWriteDB *sql.DB,
+	readWriteDatabase *generated.Queries,
+	cipher *vault.Cipher,
+) {
+	// Apply several creates, updates and deletes in one transaction. The
+	// colon is escaped, it is part of the path and not a parameter.
+	router.Post("/projects/:id/secrets\\:batch", func(c *fiber.Ctx) error {
+		id := c.Params("id")
+
+		if id == "" {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"error": "Project ID is required",
+			})
+		}
+
+		if !auth.GrantFromCtx(c).CanWrite(id) {
+			return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
+				"error": "Token is not allowed to modify this project",
+			})
+		}
+
+		var body struct {
+			Operations []db_rw.BatchOperation `json:"operations"`
+		}
+		if err := c.BodyParser(&body); err != nil {
+			log.Printf("Body parse error: %v", err)
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"error": "Invalid request body",
+			})
+		}
+
+		results, err := db_rw.ApplySecretBatch(c.Context(), readWriteDB, readWriteDatabase, cipher, id, body.Operations, auth.GrantFromCtx(c).CanRead, apiOrigin(c))
+		if err != nil {
+			var batchErr *db_rw.BatchError
+			if errors.As(err, &batchErr) {
+				status, message := batchFailure(batchErr.Err)
+				if status == fiber.StatusInternalServerError {
+					log.Printf("Batch on project %s failed at operation %d: %v", id, batchErr.Index, batchErr.Err)
+				}
+				results[batchErr.Index].Error = message
+				return c.Status(status).JSON(fiber.Map{
+					"error":   message,
+					"results": results,
+				})
+			}
+
+			var invalidInput *db_rw.InvalidInputError
+			if errors.As(err, &invalidInput) {
+				return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+					"error": invalidInput.Message,
+				})
+			}
+			if errors.Is(err, db_rw.ErrProjectNotFound) {
+				return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+					"error": "Project not found",
+				})
+			}
+			log.Printf("Failed to apply batch on project %s: %v", id, err)
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+				"error": "Failed to apply batch",
+			})
+		}
+
+		// Only committed changes are broadcast
+		var changed []generated.SecretList
+		for _, result := range results {
+			if result.Secret == nil {
+				continue
+			}
+			server_sse.BroadcastSecretChange(batchEvents[result.Op], *result.Secret)
+			changed = append(changed, *result.Secret)
+		}
+		broadcastDependents(c.Context(), readWriteDatabase, cipher, changed...)
+
+		return c.JSON(fiber.Map{
+			"results": results,
+		})
+	})
+}
+
+// batchFailure maps the error of the operation that failed a batch to the
+// status and message its own endpoint would answer with
+func batchFailure(err error) (int, string) {
+	var invalidInput *db_rw.InvalidInputError
+	switch {
+	case errors.As(err, &invalidInput):
+		return fiber.StatusBadRequest, invalidInput.Message
+	case errors.Is(err, db_rw.ErrProjectNotFound):
+		return fiber.StatusNotFound, "Project not found"
+	case errors.Is(err, db_rw.ErrSecretNotFound):
+		return fiber.StatusNotFound, "Secret not found"
+	case errors.Is(err, db_rw.ErrDuplicateKey):
+		return fiber.StatusConflict, "Secret with this name already exists in the environment"
+	case errors.Is(err, db_ro.ErrReferenceDenied):
+		return fiber.StatusForbidden, "Token does not have access to the referenced project"
+	case errors.Is(err, db_rw.ErrSecretReferenced):
+		return fiber.StatusConflict, "Secret is referenced by other secrets, delete it with force to break the references"
+	default:
+		return fiber.StatusInternalServerError, "Failed to apply operation"
+	}
+}
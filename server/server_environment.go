@@ -0,0 +1,83 @@
+package server
+
+import (
+	"log"
+	"slices"
+
+	"github.com/Knightshrestha/Secret-Injector/audit"
+	"github.com/Knightshrestha/Secret-Injector/auth"
+	"github.com/Knightshrestha/Secret-Injector/database/generated"
+	"github.com/Knightshrestha/Secret-Injector/utils"
+	"github.com/Knightshrestha/Secret-Injector/vault"
+	"github.com/gofiber/fiber/v2"
+)
+
+func RegisterReadOnlyEnvironmentRoute(router fiber.Router, readOnlyDatabase *generated.Queries, cipher *vault.Cipher, auditLog *audit.Logger) {
+	// List the environments of a project, base first
+	router.Get("/projects/:projectId/environments", func(c *fiber.Ctx) error {
+		projectId := c.Params("projectId")
+
+		if !auth.GrantFromCtx(c).CanRead(projectId) {
+			return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
+				"error": "Token does not have access to this project",
+			})
+		}
+
+		secrets, err := readOnlyDatabase.GetSecretsByProjectID(c.Context(), projectId)
+		if err != nil {
+			log.Printf("Error fetching secrets for project %s: %v", projectId, err)
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+				"error": "Failed to fetch environments",
+			})
+		}
+
+		return c.JSON(utils.Environments(secrets))
+	})
+
+	// Get the effective secrets of a project in an environment. Inherited
+	// secrets keep "environment": "base".
+	router.Get("/projects/:projectId/environments/:environment/secrets", func(c *fiber.Ctx) error {
+		projectId := c.Params("projectId")
+
+		environment, err := utils.NormalizeEnvironment(c.Params("environment"))
+		if err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"error": err.Error(),
+			})
+		}
+
+		if !auth.GrantFromCtx(c).CanRead(projectId) {
+			return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
+				"error": "Token does not have access to this project",
+			})
+		}
+
+		secrets, err := readOnlyDatabase.GetSecretsByProjectID(c.Context(), projectId)
+		if err != nil {
+			log.Printf("Error fetching secrets for project %s: %v", projectId, err)
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+				"error": "Failed to fetch secrets",
+			})
+		}
+
+		// Every row is opened, references may point at any of them
+		projectSecrets, err := cipher.OpenSecrets(secrets)
+		if err != nil {
+			log.Printf("Error decrypting secrets for project %s: %v", projectId, err)
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+				"error": "Failed to decrypt secrets",
+			})
+		}
+		secrets = utils.ResolveEnvironment(projectSecrets, environment)
+
+		// Secrets reached through references are read too
+		resolved, sources := resolveSecrets(grantReferences(c, readOnlyDatabase, cipher), secrets, projectSecrets, environment)
+		if err := recordSecretAccess(c, auditLog, audit.ActionRead, slices.Concat(secrets, sources)); err != nil {
+			log.Printf("Failed to record audit entry: %v", err)
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+				"error": "Failed to record audit entry",
+			})
+		}
+		return c.JSON(resolved)
+	})
+}
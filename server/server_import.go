@@ -0,0 +1,135 @@
+package server
+
+import (
+	"database/sql"
+	"errors"
+	"log"
+	"slices"
+
+	"github.com/Knightshrestha/Secret-Injector/auth"
+	"github.com/Knightshrestha/Secret-Injector/core/db_ro"
+	"github.com/Knightshrestha/Secret-Injector/core/db_rw"
+	"github.com/Knightshrestha/Secret-Injector/database/generated"
+	"github.com/Knightshrestha/Secret-Injector/importer"
+	"github.com/Knightshrestha/Secret-Injector/server/server_sse"
+	"github.com/Knightshrestha/Secret-Injector/vault"
+	"github.com/gofiber/fiber/v2"
+)
+
+func RegisterImportRoute(
+	router fiber.Router,
+	readWriteDatabase *sql.DB,
+	readWriteQueries *generated.Queries,
+	cipher *vault.Cipher,
+) {
+	// Import a dotenv, JSON or YAML file into a project
+	router.Post("/projects/:id/import", func(c *fiber.Ctx) error {
+		id := c.Params("id")
+
+		if id == "" {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"error": "Project ID is required",
+			})
+		}
+
+		if !auth.GrantFromCtx(c).CanWrite(id) {
+			return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
+				"error": "Token is not allowed to modify this project",
+			})
+		}
+
+		var body struct {
+			Format      string `json:"format"`
+			Content     string `json:"content"`
+			Environment string `json:"environment"`
+			Conflict    string `json:"conflict"`
+			DryRun      bool   `json:"dry_run"`
+		}
+		if err := c.BodyParser(&body); err != nil {
+			log.Printf("Body parse error: %v", err)
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"error": "Invalid request body",
+			})
+		}
+
+		format := importer.FormatDotenv
+		if body.Format != "" {
+			var err error
+			if format, err = importer.ParseFormat(body.Format); err != nil {
+				return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+					"error": err.Error(),
+				})
+			}
+		}
+
+		entries, err := importer.Parse(format, []byte(body.Content))
+		if err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"error": err.Error(),
+			})
+		}
+
+		values := make([]string, len(entries))
+		for i, entry := range entries {
+			values[i] = entry.Value
+		}
+		if err := checkReferences(c, readWriteQueries, cipher, values...); err != nil {
+			if errors.Is(err, db_ro.ErrReferenceDenied) {
+				return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
+					"error": "Token does not have access to the referenced project",
+				})
+			}
+			log.Printf("Failed to check references: %v", err)
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+				"error": "Failed to check references",
+			})
+		}
+
+		result, err := db_rw.ImportSecrets(c.Context(), readWriteDatabase, readWriteQueries, cipher, db_rw.ImportParams{
+			ProjectID:   id,
+			Environment: body.Environment,
+			Entries:     entries,
+			Policy:      db_rw.ConflictPolicy(body.Conflict),
+			DryRun:      body.DryRun,
+		}, apiOrigin(c))
+		if err != nil {
+			var invalidInput *db_rw.InvalidInputError
+			if errors.As(err, &invalidInput) {
+				return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+					"error": invalidInput.Message,
+				})
+			}
+			if errors.Is(err, db_rw.ErrProjectNotFound) {
+				return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+					"error": "Project not found",
+				})
+			}
+			if errors.Is(err, db_rw.ErrImportConflict) {
+				return c.Status(fiber.StatusConflict).JSON(fiber.Map{
+					"error":  err.Error(),
+					"result": result,
+				})
+			}
+			log.Printf("Failed to import secrets into project %s: %v", id, err)
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+				"error": "Failed to import secrets",
+			})
+		}
+
+		if result.DryRun {
+			return c.JSON(result)
+		}
+
+		var changes []server_sse.SecretChange
+		for _, secret := range result.Created {
+			changes = append(changes, server_sse.SecretChange{Type: server_sse.EventCreate, Data: secret})
+		}
+		for _, secret := range result.Updated {
+			changes = append(changes, server_sse.SecretChange{Type: server_sse.EventUpdate, Data: secret})
+		}
+		server_sse.BroadcastSecretBatch(id, changes)
+		broadcastDependents(c.Context(), readWriteQueries, cipher, slices.Concat(result.Created, result.Updated)...)
+
+		return c.JSON(result)
+	})
+}
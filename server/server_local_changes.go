@@ -0,0 +1,134 @@
+package server
+
+import (
+	"context"
+	"database/sql"
+	"log"
+	"time"
+
+	"github.com/Knightshrestha/Secret-Injector/audit"
+	"github.com/Knightshrestha/Secret-Injector/database"
+	"github.com/Knightshrestha/Secret-Injector/database/generated"
+	"github.com/Knightshrestha/Secret-Injector/server/server_sse"
+	"github.com/Knightshrestha/Secret-Injector/utils"
+)
+
+const localChangesInterval = time.Second
+
+// WatchLocalChanges broadcasts the changes CLI commands make to the
+// database while the server runs. The CLI audits every change, so the
+// server follows the audit log for new CLI entries and replays them as the
+// events the API would have sent.
+func WatchLocalChanges(customDb database.CustomDB) {
+	ctx := context.Background()
+
+	var lastID int64
+	last, err := customDb.ReadQueries.GetLastAuditEntry(ctx)
+	if err == nil {
+		lastID = last.ID
+	} else if err != sql.ErrNoRows {
+		log.Printf("Failed to read audit log, CLI changes are not broadcast: %v", err)
+		return
+	}
+
+	ticker := time.NewTicker(localChangesInterval)
+	defer ticker.Stop()
+
+	for range ticker.C {
+		entries, err := customDb.ReadQueries.GetAuditEntriesAfter(ctx, generated.GetAuditEntriesAfterParams{
+			AfterID: lastID,
+			Source:  string(audit.SourceCLI),
+		})
+		if err != nil {
+			log.Printf("Failed to read audit log: %v", err)
+			continue
+		}
+
+		// Secret changes seen together, such as an import, go out as one
+		// batch per project
+		var projectIDs []string
+		var changed []generated.SecretList
+		batches := make(map[string][]server_sse.SecretChange)
+		for _, entry := range entries {
+			if change, ok := broadcastLocalChange(ctx, customDb, entry); ok {
+				projectID := change.Data.ProjectID
+				if _, seen := batches[projectID]; !seen {
+					projectIDs = append(projectIDs, projectID)
+				}
+				batches[projectID] = append(batches[projectID], change)
+
+				// The audit log does not keep the environment of a deleted
+				// secret, base reaches every secret referencing its key
+				target := change.Data
+				if target.Environment == "" {
+					target.Environment = utils.BaseEnvironment
+				}
+				changed = append(changed, target)
+			}
+			lastID = entry.ID
+		}
+
+		for _, projectID := range projectIDs {
+			changes := batches[projectID]
+			if len(changes) == 1 {
+				server_sse.BroadcastSecretChange(changes[0].Type, changes[0].Data)
+				continue
+			}
+			server_sse.BroadcastSecretBatch(projectID, changes)
+		}
+		if len(changed) > 0 {
+			broadcastDependents(ctx, customDb.ReadQueries, customDb.Cipher, changed...)
+		}
+	}
+}
+
+// broadcastLocalChange broadcasts a project change right away and returns a
+// secret change for the caller to send
+func broadcastLocalChange(ctx context.Context, customDb database.CustomDB, entry generated.AuditLog) (server_sse.SecretChange, bool) {
+	var eventType server_sse.EventType
+	switch audit.Action(entry.Action) {
+	case audit.ActionCreate, audit.ActionRestore:
+		eventType = server_sse.EventCreate
+	case audit.ActionUpdate, audit.ActionRollback:
+		eventType = server_sse.EventUpdate
+	case audit.ActionDelete:
+		eventType = server_sse.EventDelete
+	default:
+		// Reads change nothing
+		return server_sse.SecretChange{}, false
+	}
+
+	if entry.ProjectID == nil {
+		return server_sse.SecretChange{}, false
+	}
+
+	if entry.SecretID == nil {
+		project := generated.ProjectList{ID: *entry.ProjectID}
+		if eventType != server_sse.EventDelete {
+			var err error
+			project, err = customDb.ReadQueries.GetProjectByID(ctx, *entry.ProjectID)
+			if err != nil {
+				// Deleted again since, its delete entry follows
+				return server_sse.SecretChange{}, false
+			}
+		}
+		server_sse.BroadcastProjectChange(eventType, project)
+		return server_sse.SecretChange{}, false
+	}
+
+	secret := generated.SecretList{ID: *entry.SecretID, ProjectID: *entry.ProjectID}
+	if entry.SecretKey != nil {
+		secret.Key = *entry.SecretKey
+	}
+	if eventType != server_sse.EventDelete {
+		stored, err := customDb.ReadQueries.GetSecretByID(ctx, *entry.SecretID)
+		if err != nil {
+			return server_sse.SecretChange{}, false
+		}
+		if secret, err = customDb.Cipher.OpenSecret(stored); err != nil {
+			log.Printf("Failed to decrypt secret %s: %v", stored.ID, err)
+			return server_sse.SecretChange{}, false
+		}
+	}
+	return server_sse.SecretChange{Type: eventType, Data: secret}, true
+}
@@ -2,14 +2,14 @@ package server
 
 import (
 	"database/sql"
+	"errors"
 	"log"
-	"strings"
 
+	"github.com/Knightshrestha/Secret-Injector/auth"
+	"github.com/Knightshrestha/Secret-Injector/core/db_rw"
 	"github.com/Knightshrestha/Secret-Injector/database/generated"
 	"github.com/Knightshrestha/Secret-Injector/server/server_sse"
-	"github.com/Knightshrestha/Secret-Injector/utils"
 	"github.com/gofiber/fiber/v2"
-	"github.com/google/uuid"
 )
 
 func RegisterReadOnlyProjectRoute(router fiber.Router, readOnlyDatabase *generated.Queries) {
@@ -21,7 +21,16 @@ func RegisterReadOnlyProjectRoute(router fiber.Router, readOnlyDatabase *generat
 				"error": "Failed to fetch projects",
 			})
 		}
-		return c.JSON(allProjects)
+
+		// Only list what the token can see
+		grant := auth.GrantFromCtx(c)
+		visible := []generated.ProjectList{}
+		for _, project := range allProjects {
+			if grant.CanRead(project.ID) {
+				visible = append(visible, project)
+			}
+		}
+		return c.JSON(visible)
 	})
 
 	router.Get("/projects/:id", func(c *fiber.Ctx) error {
@@ -33,6 +42,12 @@ func RegisterReadOnlyProjectRoute(router fiber.Router, readOnlyDatabase *generat
 			})
 		}
 
+		if !auth.GrantFromCtx(c).CanRead(id) {
+			return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
+				"error": "Token does not have access to this project",
+			})
+		}
+
 		project, err := readOnlyDatabase.GetProjectByID(c.Context(), id)
 		if err != nil {
 			if err == sql.ErrNoRows {
@@ -55,6 +70,12 @@ func RegisterWriteProjectRoute(
 	readWriteQueries *generated.Queries,
 ) {
 	router.Post("/projects", func(c *fiber.Ctx) error {
+		if !auth.GrantFromCtx(c).CanCreateProjects() {
+			return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
+				"error": "Token is not allowed to create projects",
+			})
+		}
+
 		var body struct {
 			Name        string  `json:"name"`
 			Description *string `json:"description"`
@@ -66,30 +87,20 @@ func RegisterWriteProjectRoute(
 			})
 		}
 
-		// Validation
-		if strings.TrimSpace(body.Name) == "" {
-			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
-				"error": "Project name is required",
-			})
-		}
-
-		id := uuid.New().String()
-
-		newProject := generated.CreateProjectParams{
-			ID:          id,
-			Name:        utils.ToScreamingSnakeCase(body.Name),
-			Description: body.Description,
-		}
-
-		project, err := readWriteQueries.CreateProject(c.Context(), newProject)
+		project, err := db_rw.CreateProject(c.Context(), readWriteDatabase, readWriteQueries, body.Name, body.Description, apiOrigin(c))
 		if err != nil {
-
-			if strings.Contains(err.Error(), "UNIQUE constraint failed") {
+			var invalidInput *db_rw.InvalidInputError
+			if errors.As(err, &invalidInput) {
+				return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+					"error": invalidInput.Message,
+				})
+			}
+			if errors.Is(err, db_rw.ErrDuplicateProject) {
 				return c.Status(fiber.StatusConflict).JSON(fiber.Map{
 					"error": "Project with this name already exists",
 				})
 			}
-			log.Printf("Failed to create project %s: %v", id, err)
+			log.Printf("Failed to create project: %v", err)
 
 			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
 				"error": "Failed to create project",
@@ -111,6 +122,12 @@ func RegisterWriteProjectRoute(
 			})
 		}
 
+		if !auth.GrantFromCtx(c).CanWrite(id) {
+			return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
+				"error": "Token is not allowed to modify this project",
+			})
+		}
+
 		var body struct {
 			Name        *string `json:"name"`
 			Description *string `json:"description"`
@@ -122,40 +139,31 @@ func RegisterWriteProjectRoute(
 			})
 		}
 
-		// Validation - at least one field should be provided
-		if body.Name == nil && body.Description == nil {
-			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
-				"error": "At least one field (name or description) must be provided",
-			})
-		}
-
-		// Validate non-empty if provided
-		if body.Name != nil && strings.TrimSpace(*body.Name) == "" {
-			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
-				"error": "Secret name cannot be empty",
-			})
-		}
-		if body.Description != nil && strings.TrimSpace(*body.Description) == "" {
-			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
-				"error": "Secret value cannot be empty",
-			})
-		}
-
 		updatedProject := generated.UpdateProjectParams{
 			ID:          id,
-			Name:        utils.ToScreamingSnakeCasePtr(body.Name),
+			Name:        body.Name,
 			Description: body.Description,
 		}
 
-		project, err := readWriteQueries.UpdateProject(c.Context(), updatedProject)
+		project, err := db_rw.UpdateProject(c.Context(), readWriteDatabase, readWriteQueries, updatedProject, apiOrigin(c))
 		if err != nil {
-			log.Printf("Failed to update project %s: %v", id, err)
-
-			if strings.Contains(err.Error(), "UNIQUE constraint failed") {
+			var invalidInput *db_rw.InvalidInputError
+			if errors.As(err, &invalidInput) {
+				return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+					"error": invalidInput.Message,
+				})
+			}
+			if errors.Is(err, db_rw.ErrProjectNotFound) {
+				return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+					"error": "Project not found",
+				})
+			}
+			if errors.Is(err, db_rw.ErrDuplicateProject) {
 				return c.Status(fiber.StatusConflict).JSON(fiber.Map{
 					"error": "Project with this name already exists",
 				})
 			}
+			log.Printf("Failed to update project %s: %v", id, err)
 			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
 				"error": "Failed to update project",
 			})
@@ -174,6 +182,12 @@ func RegisterWriteProjectRoute(
 			})
 		}
 
+		if !auth.GrantFromCtx(c).CanWrite(id) {
+			return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
+				"error": "Token is not allowed to modify this project",
+			})
+		}
+
 		// Fetch project to return in SSE
 		project, err := readWriteQueries.GetProjectByID(c.Context(), id)
 		if err != nil {
@@ -188,41 +202,13 @@ func RegisterWriteProjectRoute(
 			})
 		}
 
-		// Begin transaction
-		txn, err := readWriteDatabase.BeginTx(c.Context(), nil)
-		if err != nil {
-			log.Printf("Failed to begin transaction: %v", err)
-			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
-				"error": "Failed to begin transaction",
-			})
-		}
-		defer txn.Rollback()
-		queriesTx := readWriteQueries.WithTx(txn)
-
-		// Delete all secrets in project
-		if err := queriesTx.DeleteAllSecretsInProjects(c.Context(), id); err != nil {
-			log.Printf("Failed to delete secrets for project %s: %v", id, err)
-			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
-				"error": "Failed to delete secrets",
-			})
-		}
-
-		// Delete the project
-		if err := queriesTx.DeleteProject(c.Context(), id); err != nil {
+		if err := db_rw.DeleteProject(c.Context(), readWriteDatabase, readWriteQueries, id, apiOrigin(c)); err != nil {
 			log.Printf("Failed to delete project %s: %v", id, err)
 			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
 				"error": "Failed to delete project",
 			})
 		}
 
-		// Commit transaction
-		if err := txn.Commit(); err != nil {
-			log.Printf("Failed to commit transaction for project %s: %v", id, err)
-			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
-				"error": "Failed to commit transaction",
-			})
-		}
-
 		// Broadcast SSE event
 		server_sse.BroadcastProjectChange(server_sse.EventDelete, project)
 
@@ -0,0 +1,97 @@
+package server
+
+import (
+	"context"
+	"database/sql"
+	"log"
+
+	"github.com/Knightshrestha/Secret-Injector/audit"
+	"github.com/Knightshrestha/Secret-Injector/auth"
+	"github.com/Knightshrestha/Secret-Injector/core/db_ro"
+	"github.com/Knightshrestha/Secret-Injector/database/generated"
+	"github.com/Knightshrestha/Secret-Injector/server/server_sse"
+	"github.com/Knightshrestha/Secret-Injector/vault"
+	"github.com/gofiber/fiber/v2"
+)
+
+func RegisterReferenceRoute(router fiber.Router, readOnlyDatabase *generated.Queries, cipher *vault.Cipher, auditLog *audit.Logger) {
+	// List the secrets referencing a secret, directly or through other
+	// references
+	router.Get("/secrets/:id/dependents", func(c *fiber.Ctx) error {
+		id := c.Params("id")
+
+		secret, err := readOnlyDatabase.GetSecretByID(c.Context(), id)
+		if err != nil {
+			if err == sql.ErrNoRows {
+				return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+					"error": "Secret not found",
+				})
+			}
+			log.Printf("Failed to fetch secret %s: %v", id, err)
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+				"error": "Failed to fetch secret",
+			})
+		}
+
+		if !auth.GrantFromCtx(c).CanRead(secret.ProjectID) {
+			return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
+				"error": "Token does not have access to this project",
+			})
+		}
+
+		dependents, err := db_ro.NewReferences(c.Context(), readOnlyDatabase, cipher).Dependents(secret)
+		if err != nil {
+			log.Printf("Failed to look up dependents of secret %s: %v", id, err)
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+				"error": "Failed to look up dependents",
+			})
+		}
+
+		dependents = readableSecrets(c, dependents)
+		if err := recordSecretAccess(c, auditLog, audit.ActionRead, dependents); err != nil {
+			log.Printf("Failed to record audit entry: %v", err)
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+				"error": "Failed to record audit entry",
+			})
+		}
+		return c.JSON(dependents)
+	})
+}
+
+// readableSecrets drops the secrets of projects the token cannot read
+func readableSecrets(c *fiber.Ctx, secrets []generated.SecretList) []generated.SecretList {
+	grant := auth.GrantFromCtx(c)
+	visible := []generated.SecretList{}
+	for _, secret := range secrets {
+		if grant.CanRead(secret.ProjectID) {
+			visible = append(visible, secret)
+		}
+	}
+	return visible
+}
+
+// broadcastDependents sends an update for every secret referencing one of
+// the changed secrets, the value they stand for changed with them. Pass
+// the secret as it was before a change too when its key may have changed.
+func broadcastDependents(ctx context.Context, queries *generated.Queries, cipher *vault.Cipher, changed ...generated.SecretList) {
+	refs := db_ro.NewReferences(ctx, queries, cipher)
+
+	sent := make(map[string]bool)
+	for _, secret := range changed {
+		sent[secret.ID] = true
+	}
+
+	for _, secret := range changed {
+		dependents, err := refs.Dependents(secret)
+		if err != nil {
+			log.Printf("Failed to look up dependents of secret %s: %v", secret.ID, err)
+			return
+		}
+		for _, dependent := range dependents {
+			if !sent[dependent.ID] {
+				sent[dependent.ID] = true
+				server_sse.BroadcastSecretChange(server_sse.EventUpdate, dependent)
+			}
+		}
+	}
+}
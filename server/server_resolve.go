@@ -0,0 +1,105 @@
+package server
+
+import (
+	"github.com/Knightshrestha/Secret-Injector/auth"
+	"github.com/Knightshrestha/Secret-Injector/core/db_ro"
+	"github.com/Knightshrestha/Secret-Injector/database/generated"
+	"github.com/Knightshrestha/Secret-Injector/utils"
+	"github.com/Knightshrestha/Secret-Injector/vault"
+	"github.com/gofiber/fiber/v2"
+)
+
+// resolvedSecret is a secret with its ref:// and ${KEY} references
+// resolved. Value stays as stored, ResolvedValue is null when the
+// references cannot be resolved and ResolveError says why.
+type resolvedSecret struct {
+	generated.SecretList
+	ResolvedValue *string `json:"resolved_value"`
+	ResolveError  string  `json:"resolve_error,omitempty"`
+}
+
+// grantReferences returns a resolver limited to the projects the token of
+// the request can read
+func grantReferences(c *fiber.Ctx, queries *generated.Queries, cipher *vault.Cipher) *db_ro.References {
+	return db_ro.NewReferences(c.Context(), queries, cipher).Restrict(auth.GrantFromCtx(c).CanRead)
+}
+
+// checkReferences refuses values referencing a project the token of the
+// request cannot read, so a token cannot copy secrets out of such a project
+// by referencing them. Call it before a write transaction is open.
+func checkReferences(c *fiber.Ctx, queries *generated.Queries, cipher *vault.Cipher, values ...string) error {
+	refs := grantReferences(c, queries, cipher)
+	for _, value := range values {
+		if err := refs.Check(value); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// resolveSecrets resolves the references of secrets: ref:// values through
+// refs, then ${KEY} against the effective secrets of their own project,
+// taken from projectSecrets (decrypted). Each secret is resolved in its own
+// environment unless environment is given. The secrets of other rows that
+// referenced values were taken from are returned too, to be audited as read.
+func resolveSecrets(refs *db_ro.References, secrets []generated.SecretList, projectSecrets []generated.SecretList, environment string) ([]resolvedSecret, []generated.SecretList) {
+	byProject := make(map[string][]generated.SecretList)
+	for _, secret := range projectSecrets {
+		byProject[secret.ProjectID] = append(byProject[secret.ProjectID], secret)
+	}
+
+	type scope struct{ projectID, environment string }
+	interpolators := make(map[scope]*utils.Interpolator)
+	refErrors := make(map[scope]map[string]error)
+
+	// Sources already among secrets are audited with them
+	seen := make(map[string]bool)
+	for _, secret := range secrets {
+		seen[secret.ID] = true
+	}
+	var sources []generated.SecretList
+
+	result := make([]resolvedSecret, 0, len(secrets))
+	for _, secret := range secrets {
+		key := scope{secret.ProjectID, environment}
+		if key.environment == "" {
+			key.environment = secret.Environment
+		}
+
+		in, ok := interpolators[key]
+		if !ok {
+			// A broken ref:// keeps its stored value for the keys using it
+			values := make(map[string]string)
+			files := make(map[string]bool)
+			refErrors[key] = make(map[string]error)
+			for _, row := range utils.ResolveEnvironment(byProject[key.projectID], key.environment) {
+				value, from, err := refs.Resolve(row, key.environment)
+				if err != nil {
+					refErrors[key][row.ID] = err
+					value = row.Value
+				}
+				for _, source := range from {
+					if !seen[source.ID] {
+						seen[source.ID] = true
+						sources = append(sources, source)
+					}
+				}
+				values[row.Key] = value
+				files[row.Key] = row.Type == utils.SecretTypeFile
+			}
+			in = utils.NewInterpolator(values, files)
+			interpolators[key] = in
+		}
+
+		item := resolvedSecret{SecretList: secret}
+		if err := refErrors[key][secret.ID]; err != nil {
+			item.ResolveError = err.Error()
+		} else if value, err := in.Resolve(secret.Key); err != nil {
+			item.ResolveError = err.Error()
+		} else {
+			item.ResolvedValue = &value
+		}
+		result = append(result, item)
+	}
+	return result, sources
+}
@@ -2,17 +2,22 @@ package server
 
 import (
 	"database/sql"
+	"errors"
 	"log"
-	"strings"
+	"slices"
 
+	"github.com/Knightshrestha/Secret-Injector/audit"
+	"github.com/Knightshrestha/Secret-Injector/auth"
+	"github.com/Knightshrestha/Secret-Injector/core/db_ro"
+	"github.com/Knightshrestha/Secret-Injector/core/db_rw"
 	"github.com/Knightshrestha/Secret-Injector/database/generated"
 	"github.com/Knightshrestha/Secret-Injector/server/server_sse"
 	"github.com/Knightshrestha/Secret-Injector/utils"
+	"github.com/Knightshrestha/Secret-Injector/vault"
 	"github.com/gofiber/fiber/v2"
-	"github.com/google/uuid"
 )
 
-func RegisterReadOnlySecretRoute(router fiber.Router, readOnlyDatabase *generated.Queries) {
+func RegisterReadOnlySecretRoute(router fiber.Router, readOnlyDatabase *generated.Queries, cipher *vault.Cipher, auditLog *audit.Logger) {
 	// Get all secrets
 	router.Get("/secrets", func(c *fiber.Ctx) error {
 		allSecrets, err := readOnlyDatabase.GetAllSecrets(c.Context())
@@ -22,7 +27,32 @@ func RegisterReadOnlySecretRoute(router fiber.Router, readOnlyDatabase *generate
 				"error": "Failed to fetch secrets",
 			})
 		}
-		return c.JSON(allSecrets)
+
+		// Only return secrets of projects the token can read
+		grant := auth.GrantFromCtx(c)
+		visible := []generated.SecretList{}
+		for _, secret := range allSecrets {
+			if grant.CanRead(secret.ProjectID) {
+				visible = append(visible, secret)
+			}
+		}
+
+		allSecrets, err = cipher.OpenSecrets(visible)
+		if err != nil {
+			log.Printf("Error decrypting secrets: %v", err)
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+				"error": "Failed to decrypt secrets",
+			})
+		}
+		// Secrets reached through references are read too
+		resolved, sources := resolveSecrets(grantReferences(c, readOnlyDatabase, cipher), allSecrets, allSecrets, "")
+		if err := recordSecretAccess(c, auditLog, audit.ActionRead, slices.Concat(allSecrets, sources)); err != nil {
+			log.Printf("Failed to record audit entry: %v", err)
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+				"error": "Failed to record audit entry",
+			})
+		}
+		return c.JSON(resolved)
 	})
 
 	// Get secrets by project ID
@@ -35,6 +65,12 @@ func RegisterReadOnlySecretRoute(router fiber.Router, readOnlyDatabase *generate
 			})
 		}
 
+		if !auth.GrantFromCtx(c).CanRead(projectId) {
+			return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
+				"error": "Token does not have access to this project",
+			})
+		}
+
 		secrets, err := readOnlyDatabase.GetSecretsByProjectID(c.Context(), projectId)
 		if err != nil {
 			log.Printf("Error fetching secrets for project %s: %v", projectId, err)
@@ -42,7 +78,77 @@ func RegisterReadOnlySecretRoute(router fiber.Router, readOnlyDatabase *generate
 				"error": "Failed to fetch secrets",
 			})
 		}
-		return c.JSON(secrets)
+
+		environment := ""
+		if raw := c.Query("environment"); raw != "" {
+			environment, err = utils.NormalizeEnvironment(raw)
+			if err != nil {
+				return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+					"error": err.Error(),
+				})
+			}
+		}
+
+		// Every row is opened, references may point at any of them
+		projectSecrets, err := cipher.OpenSecrets(secrets)
+		if err != nil {
+			log.Printf("Error decrypting secrets for project %s: %v", projectId, err)
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+				"error": "Failed to decrypt secrets",
+			})
+		}
+
+		// Only the rows stored in one environment, without inheritance
+		secrets = projectSecrets
+		if environment != "" {
+			secrets = []generated.SecretList{}
+			for _, secret := range projectSecrets {
+				if secret.Environment == environment {
+					secrets = append(secrets, secret)
+				}
+			}
+		}
+
+		// Secrets reached through references are read too
+		resolved, sources := resolveSecrets(grantReferences(c, readOnlyDatabase, cipher), secrets, projectSecrets, "")
+		if err := recordSecretAccess(c, auditLog, audit.ActionRead, slices.Concat(secrets, sources)); err != nil {
+			log.Printf("Failed to record audit entry: %v", err)
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+				"error": "Failed to record audit entry",
+			})
+		}
+		return c.JSON(resolved)
+	})
+
+	// Get the deleted secrets of a project, most recent first. Values stay
+	// in the history, so nothing is read.
+	router.Get("/projects/:projectId/secrets/deleted", func(c *fiber.Ctx) error {
+		projectId := c.Params("projectId")
+
+		if projectId == "" {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"error": "Project ID cannot be empty",
+			})
+		}
+
+		if !auth.GrantFromCtx(c).CanRead(projectId) {
+			return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
+				"error": "Token does not have access to this project",
+			})
+		}
+
+		deleted, err := readOnlyDatabase.GetDeletedSecretsByProjectID(c.Context(), projectId)
+		if err != nil {
+			log.Printf("Error fetching deleted secrets for project %s: %v", projectId, err)
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+				"error": "Failed to fetch deleted secrets",
+			})
+		}
+
+		if deleted == nil {
+			deleted = []generated.DeletedSecret{}
+		}
+		return c.JSON(deleted)
 	})
 
 	// Get secret by ID
@@ -67,18 +173,117 @@ func RegisterReadOnlySecretRoute(router fiber.Router, readOnlyDatabase *generate
 				"error": "Failed to fetch secret",
 			})
 		}
-		return c.JSON(secret)
+
+		if !auth.GrantFromCtx(c).CanRead(secret.ProjectID) {
+			return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
+				"error": "Token does not have access to this project",
+			})
+		}
+
+		secret, err = cipher.OpenSecret(secret)
+		if err != nil {
+			log.Printf("Failed to decrypt secret %s: %v", id, err)
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+				"error": "Failed to decrypt secret",
+			})
+		}
+
+		// The rest of the project is needed to resolve references
+		projectSecrets, err := readOnlyDatabase.GetSecretsByProjectID(c.Context(), secret.ProjectID)
+		if err != nil {
+			log.Printf("Error fetching secrets for project %s: %v", secret.ProjectID, err)
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+				"error": "Failed to fetch secret",
+			})
+		}
+		projectSecrets, err = cipher.OpenSecrets(projectSecrets)
+		if err != nil {
+			log.Printf("Error decrypting secrets for project %s: %v", secret.ProjectID, err)
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+				"error": "Failed to decrypt secret",
+			})
+		}
+
+		// Secrets reached through references are read too
+		resolved, sources := resolveSecrets(grantReferences(c, readOnlyDatabase, cipher), []generated.SecretList{secret}, projectSecrets, "")
+		if err := recordSecretAccess(c, auditLog, audit.ActionRead, slices.Concat([]generated.SecretList{secret}, sources)); err != nil {
+			log.Printf("Failed to record audit entry: %v", err)
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+				"error": "Failed to record audit entry",
+			})
+		}
+		return c.JSON(resolved[0])
+	})
+
+	// Get the version history of a secret, newest first
+	router.Get("/secrets/:id/versions", func(c *fiber.Ctx) error {
+		id := c.Params("id")
+
+		if id == "" {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"error": "Secret ID cannot be empty",
+			})
+		}
+
+		secret, err := readOnlyDatabase.GetSecretByID(c.Context(), id)
+		if err != nil {
+			if err == sql.ErrNoRows {
+				return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+					"error": "Secret not found",
+				})
+			}
+			log.Printf("Failed to fetch secret %s: %v", id, err)
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+				"error": "Failed to fetch secret",
+			})
+		}
+
+		if !auth.GrantFromCtx(c).CanRead(secret.ProjectID) {
+			return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
+				"error": "Token does not have access to this project",
+			})
+		}
+
+		versions, err := readOnlyDatabase.GetSecretVersions(c.Context(), id)
+		if err != nil {
+			log.Printf("Failed to fetch versions of secret %s: %v", id, err)
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+				"error": "Failed to fetch secret versions",
+			})
+		}
+
+		versions, err = cipher.OpenVersions(versions)
+		if err != nil {
+			log.Printf("Failed to decrypt versions of secret %s: %v", id, err)
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+				"error": "Failed to decrypt secret versions",
+			})
+		}
+
+		if err := recordSecretAccess(c, auditLog, audit.ActionHistory, []generated.SecretList{secret}); err != nil {
+			log.Printf("Failed to record audit entry: %v", err)
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+				"error": "Failed to record audit entry",
+			})
+		}
+
+		if versions == nil {
+			versions = []generated.SecretVersion{}
+		}
+		return c.JSON(versions)
 	})
 }
 
-func RegisterWriteSecretRoute(router fiber.Router, readWriteDatabase *generated.Queries) {
+func RegisterWriteSecretRoute(router fiber.Router, readWriteDB *sql.DB, readWriteDatabase *generated.Queries, cipher *vault.Cipher) {
 	// Create secret
 	router.Post("/secrets", func(c *fiber.Ctx) error {
 		var body struct {
 			ProjectID   string  `json:"project_id"`
+			Environment string  `json:"environment"`
 			Key         string  `json:"key"`
 			Value       string  `json:"value"`
 			Description *string `json:"description"`
+			Type        string  `json:"type"`
 		}
 
 		if err := c.BodyParser(&body); err != nil {
@@ -88,62 +293,51 @@ func RegisterWriteSecretRoute(router fiber.Router, readWriteDatabase *generated.
 			})
 		}
 
-		// Validation
-		if strings.TrimSpace(body.ProjectID) == "" {
-			log.Printf("Id: %s", body.ProjectID)
-			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
-				"error": "Project ID is required",
-			})
-		}
-
-		if strings.TrimSpace(body.Key) == "" {
-			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
-				"error": "Secret key is required",
-			})
-		}
-
-		if strings.TrimSpace(body.Value) == "" {
-			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
-				"error": "Secret value is required",
+		if !auth.GrantFromCtx(c).CanWrite(body.ProjectID) {
+			return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
+				"error": "Token is not allowed to modify this project",
 			})
 		}
 
-		// Verify project exists
-		_, err := readWriteDatabase.GetProjectByID(c.Context(), body.ProjectID)
-		if err != nil {
-			if err == sql.ErrNoRows {
-				return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
-					"error": "Project not found",
+		if err := checkReferences(c, readWriteDatabase, cipher, body.Value); err != nil {
+			if errors.Is(err, db_ro.ErrReferenceDenied) {
+				return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
+					"error": "Token does not have access to the referenced project",
 				})
 			}
-			log.Printf("Failed to fetch project %s: %v", body.ProjectID, err)
+			log.Printf("Failed to check references: %v", err)
 			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
-				"error": "Failed to verify project",
+				"error": "Failed to check references",
 			})
 		}
 
-		id := uuid.New().String()
-
 		newSecret := generated.CreateSecretParams{
-			ID:          id,
 			ProjectID:   body.ProjectID,
-			Key:         utils.ToScreamingSnakeCase(body.Key),
+			Environment: body.Environment,
+			Key:         body.Key,
 			Value:       body.Value,
 			Description: body.Description,
+			Type:        body.Type,
 		}
 
-		secret, err := readWriteDatabase.CreateSecret(c.Context(), newSecret)
+		secret, err := db_rw.CreateSecret(c.Context(), readWriteDB, readWriteDatabase, cipher, newSecret, apiOrigin(c))
 		if err != nil {
-			if strings.Contains(err.Error(), "UNIQUE constraint failed") {
-				return c.Status(fiber.StatusConflict).JSON(fiber.Map{
-					"error": "Secret with this name already exists in the project",
+			var invalidInput *db_rw.InvalidInputError
+			if errors.As(err, &invalidInput) {
+				return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+					"error": invalidInput.Message,
 				})
 			}
-			if strings.Contains(err.Error(), "FOREIGN KEY constraint failed") {
+			if errors.Is(err, db_rw.ErrProjectNotFound) {
 				return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
 					"error": "Project not found",
 				})
 			}
+			if errors.Is(err, db_rw.ErrDuplicateKey) {
+				return c.Status(fiber.StatusConflict).JSON(fiber.Map{
+					"error": "Secret with this name already exists in the environment",
+				})
+			}
 			log.Printf("Failed to create secret: %v", err)
 			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
 				"error": "Failed to create secret",
@@ -151,6 +345,7 @@ func RegisterWriteSecretRoute(router fiber.Router, readWriteDatabase *generated.
 		}
 
 		server_sse.BroadcastSecretChange(server_sse.EventCreate, secret)
+		broadcastDependents(c.Context(), readWriteDatabase, cipher, secret)
 
 		return c.Status(fiber.StatusCreated).JSON(secret)
 	})
@@ -167,9 +362,10 @@ func RegisterWriteSecretRoute(router fiber.Router, readWriteDatabase *generated.
 
 		var body struct {
 			ProjectID   string  `json:"project_id"`
-			Key         *string  `json:"key"`
-			Value       *string  `json:"value"`
+			Key         *string `json:"key"`
+			Value       *string `json:"value"`
 			Description *string `json:"description"`
+			Type        *string `json:"type"`
 		}
 
 		if err := c.BodyParser(&body); err != nil {
@@ -179,47 +375,64 @@ func RegisterWriteSecretRoute(router fiber.Router, readWriteDatabase *generated.
 			})
 		}
 
-		// Validation - at least one field should be provided
-		if body.Key == nil && body.Value == nil && body.Description == nil {
-			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
-				"error": "At least one field (key or value or description) must be provided",
+		// Check the token may write to the secret's project
+		existing, err := readWriteDatabase.GetSecretByID(c.Context(), id)
+		if err != nil {
+			if err == sql.ErrNoRows {
+				return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+					"error": "Secret not found",
+				})
+			}
+			log.Printf("Failed to fetch secret %s: %v", id, err)
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+				"error": "Failed to fetch secret",
 			})
 		}
 
-		// Validate non-empty if provided
-		if body.Key != nil && strings.TrimSpace(*body.Key) == "" {
-			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
-				"error": "Secret key cannot be empty",
+		if !auth.GrantFromCtx(c).CanWrite(existing.ProjectID) {
+			return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
+				"error": "Token is not allowed to modify this project",
 			})
 		}
-		if body.Value != nil && strings.TrimSpace(*body.Value) == "" {
-			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
-				"error": "Secret value cannot be empty",
-			})
+
+		if body.Value != nil {
+			if err := checkReferences(c, readWriteDatabase, cipher, *body.Value); err != nil {
+				if errors.Is(err, db_ro.ErrReferenceDenied) {
+					return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
+						"error": "Token does not have access to the referenced project",
+					})
+				}
+				log.Printf("Failed to check references: %v", err)
+				return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+					"error": "Failed to check references",
+				})
+			}
 		}
-		// if body.Description != nil && strings.TrimSpace(*body.Description) == "" {
-		// 	return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
-		// 		"error": "Secret description cannot be empty",
-		// 	})
-		// }
 
 		updatedSecret := generated.UpdateSecretParams{
-			ID:     id,
-			Key:   utils.ToScreamingSnakeCasePtr(body.Key),
-			Value: body.Value,
+			ID:          id,
+			Key:         body.Key,
+			Value:       body.Value,
 			Description: body.Description,
+			Type:        body.Type,
 		}
 
-		secret, err := readWriteDatabase.UpdateSecret(c.Context(), updatedSecret)
+		secret, err := db_rw.UpdateSecret(c.Context(), readWriteDB, readWriteDatabase, cipher, updatedSecret, apiOrigin(c))
 		if err != nil {
-			if err == sql.ErrNoRows {
+			var invalidInput *db_rw.InvalidInputError
+			if errors.As(err, &invalidInput) {
+				return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+					"error": invalidInput.Message,
+				})
+			}
+			if errors.Is(err, db_rw.ErrSecretNotFound) {
 				return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
 					"error": "Secret not found",
 				})
 			}
-			if strings.Contains(err.Error(), "UNIQUE constraint failed") {
+			if errors.Is(err, db_rw.ErrDuplicateKey) {
 				return c.Status(fiber.StatusConflict).JSON(fiber.Map{
-					"error": "Secret with this name already exists in the project",
+					"error": "Secret with this name already exists in the environment",
 				})
 			}
 			log.Printf("Failed to update secret %s: %v", id, err)
@@ -229,6 +442,7 @@ func RegisterWriteSecretRoute(router fiber.Router, readWriteDatabase *generated.
 		}
 
 		server_sse.BroadcastSecretChange(server_sse.EventUpdate, secret)
+		broadcastDependents(c.Context(), readWriteDatabase, cipher, secret, existing)
 
 		return c.Status(fiber.StatusOK).JSON(secret)
 	})
@@ -257,8 +471,30 @@ func RegisterWriteSecretRoute(router fiber.Router, readWriteDatabase *generated.
 			})
 		}
 
-		err = readWriteDatabase.DeleteSecret(c.Context(), id)
+		if !auth.GrantFromCtx(c).CanWrite(secret.ProjectID) {
+			return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
+				"error": "Token is not allowed to modify this project",
+			})
+		}
+
+		secret, err = cipher.OpenSecret(secret)
+		if err != nil {
+			log.Printf("Failed to decrypt secret %s: %v", id, err)
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+				"error": "Failed to decrypt secret",
+			})
+		}
+
+		// ?force=true deletes a secret other secrets still reference
+		err = db_rw.DeleteSecret(c.Context(), readWriteDB, readWriteDatabase, cipher, id, c.QueryBool("force"), apiOrigin(c))
 		if err != nil {
+			var referenced *db_rw.SecretReferencedError
+			if errors.As(err, &referenced) {
+				return c.Status(fiber.StatusConflict).JSON(fiber.Map{
+					"error":      "Secret is referenced by other secrets, delete it with force to break the references",
+					"dependents": readableSecrets(c, referenced.Dependents),
+				})
+			}
 			log.Printf("Failed to delete secret %s: %v", id, err)
 			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
 				"error": "Failed to delete secret",
@@ -266,7 +502,135 @@ func RegisterWriteSecretRoute(router fiber.Router, readWriteDatabase *generated.
 		}
 
 		server_sse.BroadcastSecretChange(server_sse.EventDelete, secret)
+		broadcastDependents(c.Context(), readWriteDatabase, cipher, secret)
 
 		return c.SendStatus(fiber.StatusNoContent)
 	})
+
+	// Roll a secret back to an earlier version
+	router.Post("/secrets/:id/rollback", func(c *fiber.Ctx) error {
+		id := c.Params("id")
+
+		if id == "" {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"error": "Secret ID is required",
+			})
+		}
+
+		var body struct {
+			Version int64 `json:"version"`
+		}
+
+		if err := c.BodyParser(&body); err != nil {
+			log.Printf("Body parse error: %v", err)
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"error": "Invalid request body",
+			})
+		}
+
+		if body.Version < 1 {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"error": "Version must be a positive number",
+			})
+		}
+
+		existing, err := readWriteDatabase.GetSecretByID(c.Context(), id)
+		if err != nil {
+			if err == sql.ErrNoRows {
+				return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+					"error": "Secret not found",
+				})
+			}
+			log.Printf("Failed to fetch secret %s: %v", id, err)
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+				"error": "Failed to fetch secret",
+			})
+		}
+
+		if !auth.GrantFromCtx(c).CanWrite(existing.ProjectID) {
+			return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
+				"error": "Token is not allowed to modify this project",
+			})
+		}
+
+		secret, err := db_rw.RollbackSecret(c.Context(), readWriteDB, readWriteDatabase, cipher, id, body.Version, apiOrigin(c))
+		if err != nil {
+			if errors.Is(err, db_rw.ErrSecretNotFound) {
+				return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+					"error": "Secret not found",
+				})
+			}
+			if errors.Is(err, db_rw.ErrVersionNotFound) {
+				return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+					"error": "Version not found",
+				})
+			}
+			if errors.Is(err, db_rw.ErrDuplicateKey) {
+				return c.Status(fiber.StatusConflict).JSON(fiber.Map{
+					"error": "Another secret in the environment already uses the key of this version",
+				})
+			}
+			log.Printf("Failed to roll back secret %s: %v", id, err)
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+				"error": "Failed to roll back secret",
+			})
+		}
+
+		server_sse.BroadcastSecretChange(server_sse.EventUpdate, secret)
+		broadcastDependents(c.Context(), readWriteDatabase, cipher, secret, existing)
+
+		return c.Status(fiber.StatusOK).JSON(secret)
+	})
+
+	router.Post("/secrets/:id/restore", func(c *fiber.Ctx) error {
+		id := c.Params("id")
+
+		if id == "" {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"error": "Secret ID is required",
+			})
+		}
+
+		deleted, err := readWriteDatabase.GetDeletedSecretByID(c.Context(), id)
+		if err != nil {
+			if err == sql.ErrNoRows {
+				return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+					"error": "Deleted secret not found",
+				})
+			}
+			log.Printf("Failed to fetch deleted secret %s: %v", id, err)
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+				"error": "Failed to fetch deleted secret",
+			})
+		}
+
+		if !auth.GrantFromCtx(c).CanWrite(deleted.ProjectID) {
+			return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
+				"error": "Token is not allowed to modify this project",
+			})
+		}
+
+		secret, err := db_rw.RestoreSecret(c.Context(), readWriteDB, readWriteDatabase, cipher, id, apiOrigin(c))
+		if err != nil {
+			if errors.Is(err, db_rw.ErrDeletedNotFound) {
+				return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+					"error": "Deleted secret not found",
+				})
+			}
+			if errors.Is(err, db_rw.ErrDuplicateKey) {
+				return c.Status(fiber.StatusConflict).JSON(fiber.Map{
+					"error": "Another secret in the environment already uses this key",
+				})
+			}
+			log.Printf("Failed to restore secret %s: %v", id, err)
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+				"error": "Failed to restore secret",
+			})
+		}
+
+		server_sse.BroadcastSecretChange(server_sse.EventCreate, secret)
+		broadcastDependents(c.Context(), readWriteDatabase, cipher, secret)
+
+		return c.Status(fiber.StatusOK).JSON(secret)
+	})
 }
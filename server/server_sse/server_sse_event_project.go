@@ -9,6 +9,7 @@ import (
 	"sync"
 	"time"
 
+	"github.com/Knightshrestha/Secret-Injector/auth"
 	"github.com/Knightshrestha/Secret-Injector/database/generated"
 	"github.com/gofiber/fiber/v2"
 )
@@ -27,6 +28,7 @@ type ProjectClient struct {
 	Context  context.Context
 	Cancel   context.CancelFunc
 	LastPing time.Time
+	CanRead  func(projectID string) bool // Filters events by project
 	mu       sync.Mutex
 }
 
@@ -93,6 +95,9 @@ func (h *ProjectHub) Run() {
 		case change := <-h.broadcast:
 			h.mu.RLock()
 			for _, client := range h.clients {
+				if client.CanRead != nil && !client.CanRead(change.Data.ID) {
+					continue
+				}
 				select {
 				case client.Chan <- change:
 					// Successfully sent
@@ -209,6 +214,7 @@ func handleProjectSSE(c *fiber.Ctx) error {
 		Context:  ctx,
 		Cancel:   cancel,
 		LastPing: time.Now(),
+		CanRead:  auth.GrantFromCtx(c).CanRead,
 	}
 
 	SSE_ProjectHub.register <- client
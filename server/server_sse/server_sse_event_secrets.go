@@ -9,15 +9,19 @@ import (
 	"sync"
 	"time"
 
+	"github.com/Knightshrestha/Secret-Injector/auth"
 	"github.com/Knightshrestha/Secret-Injector/database/generated"
 	"github.com/gofiber/fiber/v2"
 )
 
-// SecretChange represents a secret change event with full data
+// SecretChange represents a secret change event with full data. A batch
+// event carries only the project ID in Data and the changes, all in that
+// project, in Changes.
 type SecretChange struct {
 	Type      EventType            `json:"type"`
 	Timestamp time.Time            `json:"timestamp"`
 	Data      generated.SecretList `json:"data"`
+	Changes   []SecretChange       `json:"changes,omitempty"`
 }
 
 // SecretClient represents an SSE client for secrets
@@ -27,6 +31,7 @@ type SecretClient struct {
 	Context  context.Context
 	Cancel   context.CancelFunc
 	LastPing time.Time
+	CanRead  func(projectID string) bool // Filters events by project
 	mu       sync.Mutex
 }
 
@@ -93,6 +98,9 @@ func (h *SecretHub) Run() {
 		case change := <-h.broadcast:
 			h.mu.RLock()
 			for _, client := range h.clients {
+				if client.CanRead != nil && !client.CanRead(change.Data.ProjectID) {
+					continue
+				}
 				select {
 				case client.Chan <- change:
 				default:
@@ -184,6 +192,38 @@ func BroadcastSecretChange(changeType EventType, secretData generated.SecretList
 	}
 }
 
+// BroadcastSecretBatch sends changes made together in one project as a
+// single event, so clients refresh once
+func BroadcastSecretBatch(projectID string, changes []SecretChange) {
+	if len(changes) == 0 {
+		return
+	}
+
+	SSE_SecretHub.mu.RLock()
+	isRunning := SSE_SecretHub.running
+	SSE_SecretHub.mu.RUnlock()
+
+	if !isRunning {
+		return
+	}
+
+	now := time.Now()
+	for i := range changes {
+		changes[i].Timestamp = now
+	}
+
+	select {
+	case SSE_SecretHub.broadcast <- SecretChange{
+		Type:      EventBatch,
+		Timestamp: now,
+		Data:      generated.SecretList{ProjectID: projectID},
+		Changes:   changes,
+	}:
+	default:
+		log.Println("Secret broadcast channel full, skipping")
+	}
+}
+
 // handleSecretSSE handles SSE connections for secrets
 func handleSecretSSE(c *fiber.Ctx) error {
 	// Check if hub is running
@@ -208,6 +248,7 @@ func handleSecretSSE(c *fiber.Ctx) error {
 		Context:  ctx,
 		Cancel:   cancel,
 		LastPing: time.Now(),
+		CanRead:  auth.GrantFromCtx(c).CanRead,
 	}
 
 	SSE_SecretHub.register <- client
@@ -11,6 +11,7 @@ const (
 	EventCreate EventType = "create"
 	EventUpdate EventType = "update"
 	EventDelete EventType = "delete"
+	EventBatch  EventType = "batch" // Several changes in one project
 	EventPing   EventType = "ping"
 )
 
@@ -1,6 +1,7 @@
 package updater
 
 import (
+	"io"
 	"time"
 )
 
@@ -18,8 +19,17 @@ type GitHubRelease struct {
 }
 
 type Updater struct {
-	Owner      string // GitHub username or org
-	Repo       string // Repository name
-	CurrentVer string // Current version (e.g., "0.0.1")
-	ExeName    string // Executable name without extension
+	Owner      string    // GitHub username or org
+	Repo       string    // Repository name
+	CurrentVer string    // Current version (e.g., "0.0.1")
+	ExeName    string    // Executable name without extension
+	Out        io.Writer // Progress messages, nil means stdout
+}
+
+// Result describes what Update did
+type Result struct {
+	CurrentVersion string `json:"current_version"`
+	LatestVersion  string `json:"latest_version"`
+	Updated        bool   `json:"updated"`
+	BackupPath     string `json:"backup_path,omitempty"`
 }
@@ -2,47 +2,52 @@ package updater
 
 import (
 	"fmt"
+	"io"
 	"os"
 	"path/filepath"
 	"runtime"
 )
 
 // Update performs the self-update
-func (u *Updater) Update() error {
-	fmt.Println("Checking for updates...")
+func (u *Updater) Update() (Result, error) {
+	result := Result{CurrentVersion: u.CurrentVer}
+	out := u.out()
+
+	fmt.Fprintln(out, "Checking for updates...")
 
 	hasUpdate, latestVer, err := u.CheckForUpdate()
 	if err != nil {
-		return err
+		return result, err
 	}
+	result.LatestVersion = latestVer
 
 	if !hasUpdate {
-		fmt.Printf("Already up to date (v%s)\n", u.CurrentVer)
-		return nil
+		fmt.Fprintf(out, "Already up to date (v%s)\n", u.CurrentVer)
+		return result, nil
 	}
 
-	fmt.Printf("New version available: v%s (current: v%s)\n", latestVer, u.CurrentVer)
-	fmt.Println("Downloading update...")
+	fmt.Fprintf(out, "New version available: v%s (current: v%s)\n", latestVer, u.CurrentVer)
+	fmt.Fprintln(out, "Downloading update...")
 
 	release, err := u.fetchLatestRelease()
 	if err != nil {
-		return err
+		return result, err
 	}
 
 	// Find the appropriate asset for this platform
 	assetURL, checksumURL, err := u.findAsset(release, latestVer)
 	if err != nil {
-		return err
+		return result, err
 	}
 
 	// Get current executable path
 	exePath, err := os.Executable()
 	if err != nil {
-		return fmt.Errorf("failed to get executable path: %w", err)
+		return result, fmt.Errorf("failed to get executable path: %w", err)
 	}
 	exePath, err = filepath.EvalSymlinks(exePath)
 	if err != nil {
-		return fmt.Errorf("failed to resolve symlinks: %w", err)
+		return result, fmt.Errorf("failed to resolve symlinks: %w", err)
 	}
 
 	exeDir := filepath.Dir(exePath)
@@ -51,45 +56,55 @@ func (u *Updater) Update() error {
 
 	// Download to temporary file
 	if err := u.downloadAndExtract(assetURL, tmpPath); err != nil {
-		return fmt.Errorf("failed to download update: %w", err)
+		return result, fmt.Errorf("failed to download update: %w", err)
 	}
 	defer os.Remove(tmpPath) // Clean up tmp file on error
 
 	// Verify checksum if available
 	if checksumURL != "" {
-		fmt.Println("Verifying checksum...")
+		fmt.Fprintln(out, "Verifying checksum...")
 		if err := u.verifyChecksum(tmpPath, checksumURL); err != nil {
-			return fmt.Errorf("checksum verification failed: %w", err)
+			return result, fmt.Errorf("checksum verification failed: %w", err)
 		}
-		fmt.Println("Checksum verified successfully")
+		fmt.Fprintln(out, "Checksum verified successfully")
 	}
 
 	// Rename old executable
 	if _, err := os.Stat(oldPath); err == nil {
 		if err := os.Remove(oldPath); err != nil {
-			return fmt.Errorf("failed to remove old backup: %w", err)
+			return result, fmt.Errorf("failed to remove old backup: %w", err)
 		}
 	}
 
 	if err := os.Rename(exePath, oldPath); err != nil {
-		return fmt.Errorf("failed to backup current executable: %w", err)
+		return result, fmt.Errorf("failed to backup current executable: %w", err)
 	}
 
 	// Rename new executable
 	if err := os.Rename(tmpPath, exePath); err != nil {
 		// Try to restore old executable
 		os.Rename(oldPath, exePath)
-		return fmt.Errorf("failed to install update: %w", err)
+		return result, fmt.Errorf("failed to install update: %w", err)
 	}
 
 	// Make executable on Unix systems
 	if runtime.GOOS != "windows" {
 		if err := os.Chmod(exePath, 0755); err != nil {
-			return fmt.Errorf("failed to set permissions: %w", err)
+			return result, fmt.Errorf("failed to set permissions: %w", err)
 		}
 	}
 
-	fmt.Printf("Successfully updated to v%s\n", latestVer)
-	fmt.Println("Old version backed up to:", filepath.Base(oldPath))
-	return nil
+	fmt.Fprintf(out, "Successfully updated to v%s\n", latestVer)
+	fmt.Fprintln(out, "Old version backed up to:", filepath.Base(oldPath))
+
+	result.Updated = true
+	result.BackupPath = oldPath
+	return result, nil
+}
+
+func (u *Updater) out() io.Writer {
+	if u.Out == nil {
+		return os.Stdout
+	}
+	return u.Out
 }
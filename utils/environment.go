@@ -0,0 +1,71 @@
+package utils
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/Knightshrestha/Secret-Injector/database/generated"
+)
+
+// BaseEnvironment holds the values every other environment inherits
+const BaseEnvironment = "base"
+
+var environmentName = regexp.MustCompile(`^[a-z0-9][a-z0-9_-]{0,31}$`)
+
+// NormalizeEnvironment lowercases an environment name and checks it, an
+// empty name means the base environment
+func NormalizeEnvironment(name string) (string, error) {
+	name = strings.ToLower(strings.TrimSpace(name))
+	if name == "" {
+		return BaseEnvironment, nil
+	}
+	if !environmentName.MatchString(name) {
+		return "", fmt.Errorf("invalid environment %q: use up to 32 letters, digits, - or _", name)
+	}
+	return name, nil
+}
+
+// ResolveEnvironment returns the effective secrets of one project in an
+// environment: the base secrets, with those redefined in the environment
+// replaced by the override. Rows keep their own environment, so callers
+// can tell inherited values apart. The result is sorted by key.
+func ResolveEnvironment(secrets []generated.SecretList, environment string) []generated.SecretList {
+	byKey := make(map[string]generated.SecretList)
+	for _, secret := range secrets {
+		if secret.Environment == BaseEnvironment {
+			byKey[secret.Key] = secret
+		}
+	}
+	if environment != BaseEnvironment {
+		for _, secret := range secrets {
+			if secret.Environment == environment {
+				byKey[secret.Key] = secret
+			}
+		}
+	}
+
+	result := make([]generated.SecretList, 0, len(byKey))
+	for _, secret := range byKey {
+		result = append(result, secret)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Key < result[j].Key
+	})
+	return result
+}
+
+// Environments lists the environments used by the secrets, base first
+func Environments(secrets []generated.SecretList) []string {
+	seen := map[string]bool{BaseEnvironment: true}
+	var others []string
+	for _, secret := range secrets {
+		if !seen[secret.Environment] {
+			seen[secret.Environment] = true
+			others = append(others, secret.Environment)
+		}
+	}
+	sort.Strings(others)
+	return append([]string{BaseEnvironment}, others...)
+}
@@ -0,0 +1,125 @@
+package utils
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"sort"
+	"strings"
+)
+
+// reference matches ${KEY} and its escaped form $${KEY}. Anything else,
+// including $$, a lone $ or ${...} around something that cannot be a key,
+// is kept as written.
+var reference = regexp.MustCompile(`\$(\$?)\{([A-Za-z_][A-Za-z0-9_]*)\}`)
+
+// Interpolator resolves ${KEY} references between the values of a set of
+// secrets, $${KEY} stands for a literal ${KEY}. A reference to a key outside
+// the set is kept as written, so values stored before references existed
+// come out unchanged. Values are resolved on demand and remembered.
+type Interpolator struct {
+	raw      map[string]string
+	files    map[string]bool
+	resolved map[string]string
+	failed   map[string]error
+	visiting []string
+}
+
+// NewInterpolator resolves against values, which are taken as is. The keys
+// in files hold file contents: they are passed through unresolved and
+// references to them are kept as written.
+func NewInterpolator(values map[string]string, files map[string]bool) *Interpolator {
+	return &Interpolator{
+		raw:      values,
+		files:    files,
+		resolved: make(map[string]string),
+		failed:   make(map[string]error),
+	}
+}
+
+// Resolve returns the value of key with every reference replaced. A key
+// outside the set or a cycle of references is an error, a cycle is named in
+// full (A -> B -> A).
+func (in *Interpolator) Resolve(key string) (string, error) {
+	if value, ok := in.resolved[key]; ok {
+		return value, nil
+	}
+	if in.files[key] {
+		if value, ok := in.raw[key]; ok {
+			return value, nil
+		}
+	}
+	if err, ok := in.failed[key]; ok {
+		return "", err
+	}
+
+	if i := slices.Index(in.visiting, key); i >= 0 {
+		cycle := append(slices.Clone(in.visiting[i:]), key)
+		return "", fmt.Errorf("reference cycle: %s", strings.Join(cycle, " -> "))
+	}
+
+	raw, ok := in.raw[key]
+	if !ok {
+		return "", fmt.Errorf("%s is not set", key)
+	}
+
+	in.visiting = append(in.visiting, key)
+	value, err := in.expand(key, raw)
+	in.visiting = in.visiting[:len(in.visiting)-1]
+
+	if err != nil {
+		in.failed[key] = err
+		return "", err
+	}
+	in.resolved[key] = value
+	return value, nil
+}
+
+func (in *Interpolator) expand(key string, raw string) (string, error) {
+	if !strings.Contains(raw, "$") {
+		return raw, nil
+	}
+
+	var firstErr error
+	value := reference.ReplaceAllStringFunc(raw, func(match string) string {
+		if firstErr != nil {
+			return match
+		}
+		if strings.HasPrefix(match, "$$") {
+			return match[1:]
+		}
+
+		name := match[2 : len(match)-1]
+		if _, ok := in.raw[name]; !ok || in.files[name] {
+			return match
+		}
+		resolved, err := in.Resolve(name)
+		if err != nil {
+			firstErr = err
+			return match
+		}
+		return resolved
+	})
+	return value, firstErr
+}
+
+// Interpolate resolves the references of every value, see Interpolator.
+// Keys are resolved in sorted order so the reported error is stable.
+func Interpolate(values map[string]string, files map[string]bool) (map[string]string, error) {
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	in := NewInterpolator(values, files)
+	result := make(map[string]string, len(values))
+	for _, key := range keys {
+		value, err := in.Resolve(key)
+		if err != nil {
+			return nil, err
+		}
+		result[key] = value
+	}
+	return result, nil
+}
@@ -0,0 +1,32 @@
+package utils
+
+import "strings"
+
+// ReferencePrefix starts a value that stands for a secret of another
+// project, e.g. ref://SHARED_INFRA/SENTRY_DSN
+const ReferencePrefix = "ref://"
+
+// Reference points at a secret by project name (or ID) and key
+type Reference struct {
+	Project string
+	Key     string
+}
+
+// ParseReference reports whether value is a reference as a whole and which
+// secret it points at. Surrounding whitespace is ignored.
+func ParseReference(value string) (Reference, bool) {
+	rest, ok := strings.CutPrefix(strings.TrimSpace(value), ReferencePrefix)
+	if !ok {
+		return Reference{}, false
+	}
+
+	project, key, ok := strings.Cut(rest, "/")
+	if !ok || project == "" || key == "" || strings.ContainsAny(key, "/ \t\r\n") {
+		return Reference{}, false
+	}
+	return Reference{Project: project, Key: key}, true
+}
+
+func (r Reference) String() string {
+	return ReferencePrefix + r.Project + "/" + r.Key
+}
@@ -0,0 +1,40 @@
+package utils
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// Secret types. A text secret is injected as a variable holding its value,
+// a file secret as a temporary file whose path the variable holds.
+const (
+	SecretTypeText = "text"
+	SecretTypeFile = "file"
+)
+
+// NormalizeSecretType lowercases a secret type and checks it, an empty type
+// means text
+func NormalizeSecretType(name string) (string, error) {
+	name = strings.ToLower(strings.TrimSpace(name))
+	switch name {
+	case "":
+		return SecretTypeText, nil
+	case SecretTypeText, SecretTypeFile:
+		return name, nil
+	default:
+		return "", fmt.Errorf("invalid secret type %q: use %s or %s", name, SecretTypeText, SecretTypeFile)
+	}
+}
+
+// fileKeyPattern is what the key of a file secret may contain. The key names
+// the file and, with a suffix, a variable, so / and .. must never get in.
+var fileKeyPattern = regexp.MustCompile(`^[A-Z0-9_]+$`)
+
+// CheckFileKey rejects keys that cannot be used for a file secret
+func CheckFileKey(key string) error {
+	if !fileKeyPattern.MatchString(key) {
+		return fmt.Errorf("invalid key %q for a file secret: use only A-Z, 0-9 and _", key)
+	}
+	return nil
+}
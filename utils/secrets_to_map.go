@@ -0,0 +1,22 @@
+package utils
+
+import "github.com/Knightshrestha/Secret-Injector/database/generated"
+
+// SecretsToMap flattens secrets into KEY -> VALUE, later entries win so
+// projects listed last override earlier ones
+func SecretsToMap(secrets []generated.SecretList) map[string]string {
+	result := make(map[string]string, len(secrets))
+	for _, secret := range secrets {
+		result[secret.Key] = secret.Value
+	}
+	return result
+}
+
+// FileKeys returns the keys whose winning secret in SecretsToMap is a file
+func FileKeys(secrets []generated.SecretList) map[string]bool {
+	result := make(map[string]bool)
+	for _, secret := range secrets {
+		result[secret.Key] = secret.Type == SecretTypeFile
+	}
+	return result
+}
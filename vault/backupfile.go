@@ -0,0 +1,120 @@
+package vault
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/binary"
+	"fmt"
+	"os"
+
+	"golang.org/x/crypto/argon2"
+)
+
+// backupMagic starts a backup sealed with a passphrase. It is followed by
+// the Argon2id time, memory and threads, the salt, then nonce || ciphertext
+// of the SQLite file. The header is authenticated as additional data.
+var backupMagic = []byte("SIBACKUP\x01")
+
+const backupHeaderSize = 9 + 4 + 4 + 1 + saltSize
+
+// The Argon2id parameters of a backup are read before it is authenticated,
+// so a crafted file could ask for any amount of memory. Backups written
+// with larger ones are refused.
+const (
+	maxBackupArgonTime    = 10
+	maxBackupArgonMemory  = 256 * 1024 // KiB
+	maxBackupArgonThreads = 16
+)
+
+// IsSealedBackup reports whether data was written by SealBackup
+func IsSealedBackup(data []byte) bool {
+	return bytes.HasPrefix(data, backupMagic)
+}
+
+// SealBackup encrypts a database snapshot under a key derived from passphrase
+func SealBackup(data []byte, passphrase []byte) ([]byte, error) {
+	salt := make([]byte, saltSize)
+	if _, err := rand.Read(salt); err != nil {
+		return nil, err
+	}
+
+	header := make([]byte, 0, backupHeaderSize)
+	header = append(header, backupMagic...)
+	header = binary.BigEndian.AppendUint32(header, argonTime)
+	header = binary.BigEndian.AppendUint32(header, argonMemory)
+	header = append(header, argonThreads)
+	header = append(header, salt...)
+
+	aead, err := newAEAD(argon2.IDKey(passphrase, salt, argonTime, argonMemory, argonThreads, keySize))
+	if err != nil {
+		return nil, err
+	}
+
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, err
+	}
+
+	sealed := append(header, nonce...)
+	return aead.Seal(sealed, nonce, data, header), nil
+}
+
+// OpenBackup decrypts a backup written by SealBackup
+func OpenBackup(data []byte, passphrase []byte) ([]byte, error) {
+	if !IsSealedBackup(data) || len(data) < backupHeaderSize {
+		return nil, fmt.Errorf("not an encrypted backup")
+	}
+
+	header := data[:backupHeaderSize]
+	params := header[len(backupMagic):]
+	time := binary.BigEndian.Uint32(params[0:4])
+	memory := binary.BigEndian.Uint32(params[4:8])
+	threads := params[8]
+	salt := params[9:]
+	if time < 1 || time > maxBackupArgonTime || memory > maxBackupArgonMemory || threads < 1 || threads > maxBackupArgonThreads {
+		return nil, fmt.Errorf("backup key derivation parameters out of range (time %d, memory %d KiB, threads %d)", time, memory, threads)
+	}
+
+	aead, err := newAEAD(argon2.IDKey(passphrase, salt, time, memory, threads, keySize))
+	if err != nil {
+		return nil, err
+	}
+
+	payload := data[backupHeaderSize:]
+	if len(payload) < aead.NonceSize() {
+		return nil, fmt.Errorf("backup is truncated")
+	}
+	nonce, ciphertext := payload[:aead.NonceSize()], payload[aead.NonceSize():]
+
+	plain, err := aead.Open(nil, nonce, ciphertext, header)
+	if err != nil {
+		return nil, fmt.Errorf("wrong backup passphrase or corrupt backup")
+	}
+	return plain, nil
+}
+
+// LoadBackupPassphrase reads the backup passphrase from
+// SECRET_INJECTOR_BACKUP_PASSPHRASE or a prompt. confirm asks twice, used
+// when a backup is being written.
+func LoadBackupPassphrase(confirm bool) ([]byte, error) {
+	if value := os.Getenv(EnvBackupPassphrase); value != "" {
+		return []byte(value), nil
+	}
+
+	passphrase, err := promptPassphraseFor("Backup passphrase: ", EnvBackupPassphrase)
+	if err != nil {
+		return nil, err
+	}
+
+	if confirm {
+		again, err := promptPassphraseFor("Confirm backup passphrase: ", EnvBackupPassphrase)
+		if err != nil {
+			return nil, err
+		}
+		if !bytes.Equal(passphrase, again) {
+			return nil, fmt.Errorf("passphrases do not match")
+		}
+	}
+
+	return passphrase, nil
+}
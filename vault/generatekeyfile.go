@@ -0,0 +1,76 @@
+package vault
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// NewKeyFile is a generated master key that is not at its path yet. It waits
+// in a temporary file next to it until the key is in use, so a failed
+// rotation leaves no key file that opens nothing.
+type NewKeyFile struct {
+	Key  *MasterKey
+	Path string
+	temp string
+}
+
+// GenerateKeyFile writes a new random master key (base64) readable only by
+// the current user to a temporary file next to path. Keep moves it to path,
+// Discard removes it. Existing files are never overwritten.
+func GenerateKeyFile(path string) (*NewKeyFile, error) {
+	if _, err := os.Lstat(path); err == nil {
+		return nil, fmt.Errorf("failed to create key file: %s already exists", path)
+	} else if !errors.Is(err, os.ErrNotExist) {
+		return nil, fmt.Errorf("failed to create key file: %w", err)
+	}
+
+	key := make([]byte, keySize)
+	if _, err := rand.Read(key); err != nil {
+		return nil, err
+	}
+
+	f, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+"-*.tmp")
+	if err != nil {
+		return nil, fmt.Errorf("failed to create key file: %w", err)
+	}
+	temp := f.Name()
+
+	err = f.Chmod(0600)
+	if err == nil {
+		_, err = f.WriteString(base64.StdEncoding.EncodeToString(key) + "\n")
+	}
+	if err == nil {
+		err = f.Sync()
+	}
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		os.Remove(temp)
+		return nil, fmt.Errorf("failed to write key file: %w", err)
+	}
+
+	return &NewKeyFile{Key: &MasterKey{kdf: KdfRaw, secret: key}, Path: path, temp: temp}, nil
+}
+
+// Keep moves the key to its path. A file created there in the meantime is
+// not overwritten, the key then stays in the temporary file the error names.
+func (f *NewKeyFile) Keep() error {
+	if err := os.Link(f.temp, f.Path); err != nil {
+		return fmt.Errorf("failed to move the new master key to %s, it is in %s: %w", f.Path, f.temp, err)
+	}
+	os.Remove(f.temp)
+	return nil
+}
+
+// Discard removes the key, for when it was never put to use. It does
+// nothing on a nil NewKeyFile.
+func (f *NewKeyFile) Discard() {
+	if f != nil {
+		os.Remove(f.temp)
+	}
+}
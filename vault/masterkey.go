@@ -0,0 +1,173 @@
+package vault
+
+import (
+	"bytes"
+	"encoding/base64"
+	"fmt"
+	"os"
+	"strings"
+
+	"golang.org/x/term"
+)
+
+// NewPassphraseKey returns a master key derived from a passphrase
+func NewPassphraseKey(passphrase []byte) (*MasterKey, error) {
+	if len(passphrase) == 0 {
+		return nil, fmt.Errorf("passphrase cannot be empty")
+	}
+	return &MasterKey{kdf: KdfArgon2id, secret: passphrase}, nil
+}
+
+// NewRawKey returns a master key from 32 raw bytes or their base64 encoding
+func NewRawKey(data []byte) (*MasterKey, error) {
+	if len(data) == keySize {
+		return &MasterKey{kdf: KdfRaw, secret: data}, nil
+	}
+
+	decoded, err := base64.StdEncoding.DecodeString(string(bytes.TrimSpace(data)))
+	if err != nil || len(decoded) != keySize {
+		return nil, fmt.Errorf("master key must be %d bytes or their base64 encoding", keySize)
+	}
+	return &MasterKey{kdf: KdfRaw, secret: decoded}, nil
+}
+
+// LoadMasterKey picks the configured master key: a key file (--key-file or
+// SECRET_INJECTOR_KEY_FILE), SECRET_INJECTOR_KEY, then a passphrase from
+// SECRET_INJECTOR_PASSPHRASE or an interactive prompt. confirm asks for the
+// passphrase twice, used when a new key is being set.
+func LoadMasterKey(confirm bool) (*MasterKey, error) {
+	key, err := loadRawKey()
+	if err != nil || key != nil {
+		return key, err
+	}
+	return loadPassphraseKey(confirm)
+}
+
+// LoadNewMasterKey loads the replacement key for a rotation: a key file when
+// given, otherwise a passphrase from SECRET_INJECTOR_NEW_PASSPHRASE or a
+// confirmed prompt
+func LoadNewMasterKey(keyFile string) (*MasterKey, error) {
+	if keyFile != "" {
+		data, err := os.ReadFile(keyFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read key file: %w", err)
+		}
+		return NewRawKey(data)
+	}
+
+	if value := os.Getenv(EnvNewPassphrase); value != "" {
+		return NewPassphraseKey([]byte(value))
+	}
+
+	passphrase, err := promptPassphrase("New master passphrase: ")
+	if err != nil {
+		return nil, err
+	}
+	again, err := promptPassphrase("Confirm new passphrase: ")
+	if err != nil {
+		return nil, err
+	}
+	if !bytes.Equal(passphrase, again) {
+		return nil, fmt.Errorf("passphrases do not match")
+	}
+	return NewPassphraseKey(passphrase)
+}
+
+// loadMasterKeyFor loads a master key of the kind the database was sealed with
+func loadMasterKeyFor(kdf string) (*MasterKey, error) {
+	switch kdf {
+	case KdfRaw:
+		key, err := loadRawKey()
+		if err != nil {
+			return nil, err
+		}
+		if key == nil {
+			return nil, fmt.Errorf("database is encrypted with a key file, use --key-file or set %s / %s", EnvKeyFile, EnvKey)
+		}
+		return key, nil
+	case KdfArgon2id:
+		return loadPassphraseKey(false)
+	default:
+		return nil, fmt.Errorf("unknown key derivation %q", kdf)
+	}
+}
+
+// loadRawKey returns nil without error when no raw key is configured
+func loadRawKey() (*MasterKey, error) {
+	path := KeyFile
+	if path == "" {
+		path = os.Getenv(EnvKeyFile)
+	}
+
+	if path != "" {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read key file: %w", err)
+		}
+		return NewRawKey(data)
+	}
+
+	if value := os.Getenv(EnvKey); value != "" {
+		return NewRawKey([]byte(value))
+	}
+
+	return nil, nil
+}
+
+func loadPassphraseKey(confirm bool) (*MasterKey, error) {
+	if value := os.Getenv(EnvPassphrase); value != "" {
+		return NewPassphraseKey([]byte(value))
+	}
+
+	passphrase, err := promptPassphrase("Master passphrase: ")
+	if err != nil {
+		return nil, err
+	}
+
+	if confirm {
+		again, err := promptPassphrase("Confirm passphrase: ")
+		if err != nil {
+			return nil, err
+		}
+		if !bytes.Equal(passphrase, again) {
+			return nil, fmt.Errorf("passphrases do not match")
+		}
+	}
+
+	return NewPassphraseKey(passphrase)
+}
+
+// promptPassphrase reads a passphrase from the terminal without echo. The
+// prompt goes to stderr so it never mixes with exported output.
+func promptPassphrase(prompt string) ([]byte, error) {
+	fd := int(os.Stdin.Fd())
+	if !term.IsTerminal(fd) {
+		return nil, fmt.Errorf("database is encrypted, set %s or run interactively", EnvPassphrase)
+	}
+	return readPassphrase(fd, prompt)
+}
+
+// promptPassphraseFor is promptPassphrase for a passphrase that can also be
+// supplied through env
+func promptPassphraseFor(prompt string, env string) ([]byte, error) {
+	fd := int(os.Stdin.Fd())
+	if !term.IsTerminal(fd) {
+		return nil, fmt.Errorf("set %s or run interactively", env)
+	}
+	return readPassphrase(fd, prompt)
+}
+
+func readPassphrase(fd int, prompt string) ([]byte, error) {
+	fmt.Fprint(os.Stderr, prompt)
+	passphrase, err := term.ReadPassword(fd)
+	fmt.Fprintln(os.Stderr)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read passphrase: %w", err)
+	}
+
+	passphrase = []byte(strings.TrimRight(string(passphrase), "\r\n"))
+	if len(passphrase) == 0 {
+		return nil, fmt.Errorf("passphrase cannot be empty")
+	}
+	return passphrase, nil
+}
@@ -0,0 +1,78 @@
+package vault
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+
+	"github.com/Knightshrestha/Secret-Injector/database/generated"
+)
+
+// Rewrap stores the current data key wrapped under a new master key. Secret
+// rows are untouched. Run it inside a transaction.
+func (c *Cipher) Rewrap(ctx context.Context, queries *generated.Queries, master *MasterKey) error {
+	if c == nil {
+		return fmt.Errorf("database is not encrypted")
+	}
+
+	rec, err := master.newRecord()
+	if err != nil {
+		return err
+	}
+
+	wrapped, err := master.wrapKey(c.keyID, c.dataKey, rec)
+	if err != nil {
+		return err
+	}
+
+	return queries.UpdateEncryptionKeyWrapping(ctx, generated.UpdateEncryptionKeyWrappingParams{
+		WrappedKey: wrapped,
+		Kdf:        rec.kdf,
+		Salt:       base64.StdEncoding.EncodeToString(rec.salt),
+		KdfTime:    int64(rec.time),
+		KdfMemory:  int64(rec.memory),
+		KdfThreads: int64(rec.threads),
+		ID:         c.keyID,
+	})
+}
+
+// Reencrypt generates a fresh data key under the new master key, re-seals
+// every secret and secret version with it and drops the old data key. It returns the new
+// cipher and the number of rows re-encrypted. Run it inside a transaction.
+func (c *Cipher) Reencrypt(ctx context.Context, queries *generated.Queries, master *MasterKey) (*Cipher, int, error) {
+	if c == nil {
+		return nil, 0, fmt.Errorf("database is not encrypted")
+	}
+
+	values, err := storedValues(ctx, queries)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	next, err := Initialize(ctx, queries, master)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	for _, v := range values {
+		plaintext, err := c.Open(v.secretID, v.value)
+		if err != nil {
+			return nil, 0, err
+		}
+
+		sealed, err := next.Seal(v.secretID, plaintext)
+		if err != nil {
+			return nil, 0, err
+		}
+
+		if err := v.set(ctx, sealed); err != nil {
+			return nil, 0, fmt.Errorf("failed to update %s: %w", v.label, err)
+		}
+	}
+
+	if err := queries.DeleteEncryptionKey(ctx, c.keyID); err != nil {
+		return nil, 0, fmt.Errorf("failed to remove old data key: %w", err)
+	}
+
+	return next, len(values), nil
+}
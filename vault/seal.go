@@ -0,0 +1,122 @@
+package vault
+
+import (
+	"encoding/base64"
+	"fmt"
+	"strings"
+
+	"github.com/Knightshrestha/Secret-Injector/database/generated"
+	"github.com/google/uuid"
+)
+
+// Sizes of the AES-GCM nonce and tag every sealed payload carries
+const (
+	nonceSize = 12
+	tagSize   = 16
+)
+
+// IsSealed reports whether a stored value is encrypted. The whole structure
+// is checked, so a plaintext value that merely starts with the prefix is not
+// taken for one.
+func IsSealed(value string) bool {
+	_, _, ok := parseSealed(value)
+	return ok
+}
+
+// parseSealed splits a sealed value into its data key ID and payload. The
+// key ID must be a UUID and the payload base64 of at least a nonce and tag.
+func parseSealed(value string) (keyID, payload string, ok bool) {
+	rest, ok := strings.CutPrefix(value, Prefix)
+	if !ok {
+		return "", "", false
+	}
+	keyID, payload, ok = strings.Cut(rest, ":")
+	if !ok {
+		return "", "", false
+	}
+	if _, err := uuid.Parse(keyID); err != nil {
+		return "", "", false
+	}
+	data, err := base64.StdEncoding.DecodeString(payload)
+	if err != nil || len(data) < nonceSize+tagSize {
+		return "", "", false
+	}
+	return keyID, payload, true
+}
+
+// isOwn reports whether value was sealed with this cipher's data key
+func (c *Cipher) isOwn(value string) bool {
+	keyID, _, ok := parseSealed(value)
+	return ok && c != nil && keyID == c.keyID
+}
+
+// Seal encrypts a value for the secret row with the given ID. The ID is used
+// as additional data so a sealed value cannot be moved to another row.
+func (c *Cipher) Seal(secretID, value string) (string, error) {
+	if c == nil {
+		return value, nil
+	}
+
+	payload, err := seal(c.aead, []byte(value), []byte(secretID))
+	if err != nil {
+		return "", fmt.Errorf("failed to encrypt secret: %w", err)
+	}
+	return Prefix + c.keyID + ":" + payload, nil
+}
+
+// Open decrypts a stored value, plaintext values are returned unchanged
+func (c *Cipher) Open(secretID, value string) (string, error) {
+	keyID, payload, ok := parseSealed(value)
+	if !ok {
+		return value, nil
+	}
+	if c == nil {
+		return "", fmt.Errorf("secret %s is encrypted but the database is not unlocked", secretID)
+	}
+	if keyID != c.keyID {
+		return "", fmt.Errorf("secret %s is encrypted with unknown data key %s", secretID, keyID)
+	}
+
+	plaintext, err := open(c.aead, payload, []byte(secretID))
+	if err != nil {
+		return "", fmt.Errorf("failed to decrypt secret %s", secretID)
+	}
+	return string(plaintext), nil
+}
+
+// OpenSecret returns a copy of the row with its value decrypted
+func (c *Cipher) OpenSecret(secret generated.SecretList) (generated.SecretList, error) {
+	value, err := c.Open(secret.ID, secret.Value)
+	if err != nil {
+		return generated.SecretList{}, err
+	}
+	secret.Value = value
+	return secret, nil
+}
+
+// OpenSecrets decrypts a list of rows
+func (c *Cipher) OpenSecrets(secrets []generated.SecretList) ([]generated.SecretList, error) {
+	result := make([]generated.SecretList, 0, len(secrets))
+	for _, secret := range secrets {
+		opened, err := c.OpenSecret(secret)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, opened)
+	}
+	return result, nil
+}
+
+// OpenVersions decrypts a list of past secret versions
+func (c *Cipher) OpenVersions(versions []generated.SecretVersion) ([]generated.SecretVersion, error) {
+	result := make([]generated.SecretVersion, 0, len(versions))
+	for _, version := range versions {
+		value, err := c.Open(version.SecretID, version.Value)
+		if err != nil {
+			return nil, err
+		}
+		version.Value = value
+		result = append(result, version)
+	}
+	return result, nil
+}
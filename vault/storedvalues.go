@@ -0,0 +1,56 @@
+package vault
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/Knightshrestha/Secret-Injector/database/generated"
+)
+
+// storedValue is one sealed column: a secret value or a past version of it.
+// Both are bound to the secret ID, so versions can be restored verbatim.
+type storedValue struct {
+	secretID string
+	value    string
+	label    string
+	set      func(ctx context.Context, sealed string) error
+}
+
+// storedValues collects every value the cipher is responsible for
+func storedValues(ctx context.Context, queries *generated.Queries) ([]storedValue, error) {
+	secrets, err := queries.GetAllSecrets(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch secrets: %w", err)
+	}
+
+	versions, err := queries.GetAllSecretVersions(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch secret versions: %w", err)
+	}
+
+	values := make([]storedValue, 0, len(secrets)+len(versions))
+	for _, secret := range secrets {
+		id := secret.ID
+		values = append(values, storedValue{
+			secretID: id,
+			value:    secret.Value,
+			label:    "secret " + id,
+			set: func(ctx context.Context, sealed string) error {
+				return queries.SetSecretValue(ctx, generated.SetSecretValueParams{Value: sealed, ID: id})
+			},
+		})
+	}
+	for _, version := range versions {
+		id := version.ID
+		values = append(values, storedValue{
+			secretID: version.SecretID,
+			value:    version.Value,
+			label:    fmt.Sprintf("secret %s version %d", version.SecretID, version.Version),
+			set: func(ctx context.Context, sealed string) error {
+				return queries.SetSecretVersionValue(ctx, generated.SetSecretVersionValueParams{Value: sealed, ID: id})
+			},
+		})
+	}
+
+	return values, nil
+}
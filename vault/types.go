@@ -0,0 +1,56 @@
+package vault
+
+import (
+	"crypto/cipher"
+	"errors"
+)
+
+// ErrLocked matches the errors of Unlock when the master key is missing or
+// does not open the data key
+var ErrLocked = errors.New("database is locked")
+
+const (
+	// Prefix marks a sealed value, followed by "<key id>:<base64 payload>"
+	Prefix = "enc:v1:"
+
+	KdfArgon2id = "argon2id" // Master key derived from a passphrase
+	KdfRaw      = "raw"      // Master key supplied as 32 random bytes
+
+	keySize = 32 // AES-256
+
+	argonTime    = 3
+	argonMemory  = 64 * 1024 // KiB
+	argonThreads = 4
+	saltSize     = 16
+)
+
+// Environment variables that supply the master key
+const (
+	EnvKeyFile    = "SECRET_INJECTOR_KEY_FILE"
+	EnvKey        = "SECRET_INJECTOR_KEY"
+	EnvPassphrase = "SECRET_INJECTOR_PASSPHRASE"
+
+	// Used by rekey for the replacement passphrase
+	EnvNewPassphrase = "SECRET_INJECTOR_NEW_PASSPHRASE"
+
+	// Used by backup and restore for encrypted backups
+	EnvBackupPassphrase = "SECRET_INJECTOR_BACKUP_PASSPHRASE"
+)
+
+// KeyFile is set from the --key-file flag and takes precedence over the
+// environment
+var KeyFile string
+
+// Cipher seals and opens secret values with the unwrapped data key. A nil
+// *Cipher means the database is not encrypted and values pass through.
+type Cipher struct {
+	keyID   string
+	dataKey []byte
+	aead    cipher.AEAD
+}
+
+// MasterKey wraps the data key, either directly (raw) or through Argon2id
+type MasterKey struct {
+	kdf    string
+	secret []byte
+}
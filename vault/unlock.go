@@ -0,0 +1,115 @@
+package vault
+
+import (
+	"context"
+	"crypto/rand"
+	"database/sql"
+	"encoding/base64"
+	"fmt"
+	"strings"
+
+	"github.com/Knightshrestha/Secret-Injector/database/generated"
+	"github.com/google/uuid"
+)
+
+// Unlock loads the data key of an encrypted database, asking for the master
+// key as needed. It returns a nil Cipher for databases that have not been
+// encrypted yet.
+func Unlock(ctx context.Context, queries *generated.Queries) (*Cipher, error) {
+	row, err := queries.GetActiveEncryptionKey(ctx)
+	if err != nil {
+		// Databases created before encryption existed have no key table
+		if err == sql.ErrNoRows || strings.Contains(err.Error(), "no such table") {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to read encryption key: %w", err)
+	}
+
+	master, err := loadMasterKeyFor(row.Kdf)
+	if err != nil {
+		return nil, &lockedError{err}
+	}
+
+	cipher, err := UnlockWith(row, master)
+	if err != nil {
+		return nil, &lockedError{err}
+	}
+	return cipher, nil
+}
+
+// lockedError keeps the message of the underlying error and matches
+// ErrLocked
+type lockedError struct {
+	err error
+}
+
+func (e *lockedError) Error() string {
+	return e.err.Error()
+}
+
+func (e *lockedError) Unwrap() error {
+	return e.err
+}
+
+func (e *lockedError) Is(target error) bool {
+	return target == ErrLocked
+}
+
+// UnlockWith unwraps a stored data key with the given master key
+func UnlockWith(row generated.EncryptionKey, master *MasterKey) (*Cipher, error) {
+	rec, err := recordFromRow(row)
+	if err != nil {
+		return nil, err
+	}
+
+	dataKey, err := master.unwrapKey(row.ID, row.WrappedKey, rec)
+	if err != nil {
+		return nil, err
+	}
+
+	return newCipher(row.ID, dataKey)
+}
+
+// Initialize generates a new data key, wraps it with the master key and
+// stores it
+func Initialize(ctx context.Context, queries *generated.Queries, master *MasterKey) (*Cipher, error) {
+	dataKey := make([]byte, keySize)
+	if _, err := rand.Read(dataKey); err != nil {
+		return nil, err
+	}
+
+	keyID := uuid.New().String()
+
+	rec, err := master.newRecord()
+	if err != nil {
+		return nil, err
+	}
+
+	wrapped, err := master.wrapKey(keyID, dataKey, rec)
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = queries.CreateEncryptionKey(ctx, generated.CreateEncryptionKeyParams{
+		ID:         keyID,
+		WrappedKey: wrapped,
+		Kdf:        rec.kdf,
+		Salt:       base64.StdEncoding.EncodeToString(rec.salt),
+		KdfTime:    int64(rec.time),
+		KdfMemory:  int64(rec.memory),
+		KdfThreads: int64(rec.threads),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to store encryption key: %w", err)
+	}
+
+	return newCipher(keyID, dataKey)
+}
+
+func newCipher(keyID string, dataKey []byte) (*Cipher, error) {
+	aead, err := newAEAD(dataKey)
+	if err != nil {
+		return nil, err
+	}
+	return &Cipher{keyID: keyID, dataKey: dataKey, aead: aead}, nil
+}
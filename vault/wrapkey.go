@@ -0,0 +1,126 @@
+package vault
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"fmt"
+
+	"github.com/Knightshrestha/Secret-Injector/database/generated"
+	"golang.org/x/crypto/argon2"
+)
+
+// keyRecord describes how a data key is wrapped, it mirrors the columns of
+// encryption_keys
+type keyRecord struct {
+	kdf     string
+	salt    []byte
+	time    uint32
+	memory  uint32
+	threads uint8
+}
+
+func recordFromRow(row generated.EncryptionKey) (keyRecord, error) {
+	salt, err := base64.StdEncoding.DecodeString(row.Salt)
+	if err != nil {
+		return keyRecord{}, fmt.Errorf("corrupt key salt: %w", err)
+	}
+	return keyRecord{
+		kdf:     row.Kdf,
+		salt:    salt,
+		time:    uint32(row.KdfTime),
+		memory:  uint32(row.KdfMemory),
+		threads: uint8(row.KdfThreads),
+	}, nil
+}
+
+// newRecord picks fresh derivation parameters for the master key
+func (m *MasterKey) newRecord() (keyRecord, error) {
+	if m.kdf == KdfRaw {
+		return keyRecord{kdf: KdfRaw}, nil
+	}
+
+	salt := make([]byte, saltSize)
+	if _, err := rand.Read(salt); err != nil {
+		return keyRecord{}, err
+	}
+	return keyRecord{
+		kdf:     KdfArgon2id,
+		salt:    salt,
+		time:    argonTime,
+		memory:  argonMemory,
+		threads: argonThreads,
+	}, nil
+}
+
+// kek derives the key-encryption key for a record
+func (m *MasterKey) kek(rec keyRecord) ([]byte, error) {
+	if m.kdf != rec.kdf {
+		return nil, fmt.Errorf("master key type %q does not match database (%q)", m.kdf, rec.kdf)
+	}
+	if rec.kdf == KdfRaw {
+		return m.secret, nil
+	}
+	return argon2.IDKey(m.secret, rec.salt, rec.time, rec.memory, rec.threads, keySize), nil
+}
+
+// wrapKey seals the data key under the master key, bound to its key ID
+func (m *MasterKey) wrapKey(keyID string, dataKey []byte, rec keyRecord) (string, error) {
+	kek, err := m.kek(rec)
+	if err != nil {
+		return "", err
+	}
+	aead, err := newAEAD(kek)
+	if err != nil {
+		return "", err
+	}
+	return seal(aead, dataKey, []byte(keyID))
+}
+
+// unwrapKey recovers the data key, failing if the master key is wrong
+func (m *MasterKey) unwrapKey(keyID string, wrapped string, rec keyRecord) ([]byte, error) {
+	kek, err := m.kek(rec)
+	if err != nil {
+		return nil, err
+	}
+	aead, err := newAEAD(kek)
+	if err != nil {
+		return nil, err
+	}
+	dataKey, err := open(aead, wrapped, []byte(keyID))
+	if err != nil {
+		return nil, fmt.Errorf("wrong master key")
+	}
+	return dataKey, nil
+}
+
+func newAEAD(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+// seal returns base64(nonce || ciphertext)
+func seal(aead cipher.AEAD, plaintext, additional []byte) (string, error) {
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return "", err
+	}
+	sealed := aead.Seal(nonce, nonce, plaintext, additional)
+	return base64.StdEncoding.EncodeToString(sealed), nil
+}
+
+func open(aead cipher.AEAD, payload string, additional []byte) ([]byte, error) {
+	data, err := base64.StdEncoding.DecodeString(payload)
+	if err != nil {
+		return nil, err
+	}
+	if len(data) < aead.NonceSize() {
+		return nil, fmt.Errorf("ciphertext too short")
+	}
+	nonce, ciphertext := data[:aead.NonceSize()], data[aead.NonceSize():]
+	return aead.Open(nil, nonce, ciphertext, additional)
+}